@@ -3,6 +3,14 @@
 // @description Сервис авторизации с JWT и Swagger UI.
 // @host        localhost:8080
 // @BasePath    /
+// @securityDefinitions.apikey BearerAuth
+// @in          header
+// @name        Authorization
+// @description Access-токен в формате "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in          header
+// @name        X-API-Key
+// @description API-ключ киоска или интеграции (альтернатива: "Authorization: ApiKey <key>")
 package main
 
 import (
@@ -12,8 +20,12 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "library-Mongo/cmd/app/docs"
+	"library-Mongo/internal/auth"
 	"library-Mongo/internal/config"
 	"library-Mongo/internal/handler"
+	"library-Mongo/internal/notify"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/repo/filestore"
 	"library-Mongo/internal/repo/mongo"
 	"library-Mongo/internal/usecase"
 	"log"
@@ -53,30 +65,115 @@ func main() {
 	// Инициализация репозиториев
 	userRepo := mongo.NewUserRepo(db)
 	bookRepo := mongo.NewBookRepo(db)
+	itemRepo := mongo.NewItemRepo(db)
+	authorRepo := mongo.NewAuthorRepo(db)
+	genreRepo := mongo.NewGenreRepo(db)
+	workRepo := mongo.NewWorkRepo(db)
+	seriesRepo := mongo.NewSeriesRepo(db)
 	borrowRepo := mongo.NewBorrowRepo(db)
+	sessionRepo := mongo.NewSessionRepo(db)
+	loginAttemptRepo := mongo.NewLoginAttemptRepo(db)
+	verificationCodeRepo := mongo.NewVerificationCodeRepo(db)
+	twoFactorRepo := mongo.NewTwoFactorRepo(db)
+	settingsRepo := mongo.NewSettingsRepo(db)
+	apiKeyRepo := mongo.NewAPIKeyRepo(db)
+	auditRepo := mongo.NewAuditRepo(db)
+	revisionRepo := mongo.NewBookRevisionRepo(db)
+
+	// Файлы обложек: GridFS или каталог на диске
+	var coverStorage repo.CoverStorage = mongo.NewCoverStorage(db)
+	if cfg.CoverStorage == "fs" {
+		coverStorage = filestore.NewCoverStorage(cfg.CoverDir)
+	}
+
+	// Выпуск и проверка JWT, хэширование паролей
+	tokenManager := auth.NewTokenManager(cfg.JWTSecret, cfg.AccessTokenTTL, cfg.TwoFactorChallengeTTL)
+	passwordHasher := auth.NewPasswordHasher(cfg.BcryptCost)
+	secretBox, err := auth.NewSecretBox(cfg.TOTPEncryptionKey)
+	if err != nil {
+		log.Fatal("Ошибка инициализации шифрования:", err)
+	}
+
+	// Доставка одноразовых кодов
+	var sender notify.Sender = notify.NewLogSender(cfg.SenderLogFile)
+	if cfg.Sender == "sms" {
+		sender = notify.NewSMSSender(cfg.SMSGatewayURL, cfg.SMSGatewayToken)
+	}
 
 	// Инициализация usecase
-	BorrowUC := usecase.NewBorrowUsecase(borrowRepo, bookRepo, userRepo)
-	BookUC := usecase.NewBookUsecase(bookRepo)
-	UserUC := usecase.NewUserUsecase(userRepo)
+	AuditUC := usecase.NewAuditUsecase(auditRepo, cfg.AuditRetention)
+	BorrowUC := usecase.NewBorrowUsecase(borrowRepo, bookRepo, itemRepo, userRepo, AuditUC)
+	BookUC := usecase.NewBookUsecase(bookRepo, itemRepo, authorRepo, genreRepo, workRepo, seriesRepo, borrowRepo, revisionRepo, AuditUC)
+	RevisionUC := usecase.NewRevisionUsecase(revisionRepo, bookRepo, genreRepo, BookUC)
+	DuplicateUC := usecase.NewDuplicateUsecase(bookRepo, itemRepo, borrowRepo, BookUC, AuditUC)
+	AuthorUC := usecase.NewAuthorUsecase(authorRepo, bookRepo, AuditUC)
+	GenreUC := usecase.NewGenreUsecase(genreRepo, bookRepo, AuditUC)
+	WorkUC := usecase.NewWorkUsecase(workRepo, bookRepo, borrowRepo, BookUC, AuditUC)
+	SeriesUC := usecase.NewSeriesUsecase(seriesRepo, bookRepo, AuditUC)
+	MARCUC := usecase.NewMARCUsecase(bookRepo, authorRepo, BookUC, AuthorUC)
+	SheetUC := usecase.NewSheetUsecase(bookRepo, BookUC)
+	CoverUC := usecase.NewCoverUsecase(bookRepo, coverStorage, AuditUC, cfg.CoverMaxSize)
+	ItemUC := usecase.NewItemUsecase(itemRepo, bookRepo, AuditUC)
+	SessionUC := usecase.NewSessionUsecase(sessionRepo, userRepo, tokenManager, cfg.RefreshTokenTTL)
+	loginGuard := usecase.NewLoginGuard(loginAttemptRepo, usecase.LoginGuardPolicy{
+		MaxFailures:   cfg.LoginMaxFailures,
+		MaxFailuresIP: cfg.LoginMaxFailuresIP,
+		Window:        cfg.LoginFailureWindow,
+		LockBase:      cfg.LoginLockBase,
+		LockMax:       cfg.LoginLockMax,
+	})
+	VerificationUC := usecase.NewVerificationUsecase(verificationCodeRepo, userRepo, sender, passwordHasher, SessionUC, usecase.VerificationPolicy{
+		CodeTTL:        cfg.CodeTTL,
+		MaxAttempts:    cfg.CodeMaxAttempts,
+		ResendCooldown: cfg.CodeResendCooldown,
+		TicketTTL:      cfg.ResetTicketTTL,
+	})
+	TwoFactorUC := usecase.NewTwoFactorUsecase(twoFactorRepo, settingsRepo, userRepo, tokenManager, secretBox, SessionUC, loginGuard, cfg.TOTPIssuer)
+	APIKeyUC := usecase.NewAPIKeyUsecase(apiKeyRepo)
+	UserUC := usecase.NewUserUsecase(userRepo, SessionUC, passwordHasher, loginGuard, VerificationUC, TwoFactorUC, AuditUC)
 
 	// Инициализация хендлеров
 	borrowHandler := handler.NewBorrowHandler(BorrowUC)
 	bookHandler := handler.NewBookHandler(BookUC)
+	itemHandler := handler.NewItemHandler(ItemUC)
+	authorHandler := handler.NewAuthorHandler(AuthorUC, BookUC)
+	genreHandler := handler.NewGenreHandler(GenreUC)
+	workHandler := handler.NewWorkHandler(WorkUC)
+	seriesHandler := handler.NewSeriesHandler(SeriesUC)
+	marcHandler := handler.NewMARCHandler(MARCUC)
+	sheetHandler := handler.NewSheetHandler(SheetUC)
+	coverHandler := handler.NewCoverHandler(CoverUC)
+	revisionHandler := handler.NewRevisionHandler(RevisionUC)
+	duplicateHandler := handler.NewDuplicateHandler(DuplicateUC)
 	userHandler := handler.NewUserHandler(UserUC)
+	sessionHandler := handler.NewSessionHandler(SessionUC)
+	verificationHandler := handler.NewVerificationHandler(VerificationUC)
+	twoFactorHandler := handler.NewTwoFactorHandler(TwoFactorUC)
+	apiKeyHandler := handler.NewAPIKeyHandler(APIKeyUC)
+	auditHandler := handler.NewAuditHandler(AuditUC)
 
 	// HTTP сервер на Gin
 	r := gin.Default()
+	// По умолчанию gin доверяет любому прокси и берёт IP клиента из X-Forwarded-For как есть
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After", "X-Request-ID", "X-Total-Count", "Link", "X-Did-You-Mean"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// ID запроса и адрес клиента для журнала аудита
+	r.Use(handler.RequestID())
+
+	// Аутентификация (JWT или API-ключ) и проверка ролей/областей по таблице auth.Policy
+	r.Use(handler.AuthMiddleware(tokenManager, SessionUC, APIKeyUC), handler.Authorize(auth.Policy))
+
 	// Регистрация Swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.GET("/borrow/history/:userID", borrowHandler.GetBorrowHistory)
@@ -91,14 +188,110 @@ func main() {
 	r.GET("/books/search", bookHandler.SearchBooks)
 	r.DELETE("/books/:id", bookHandler.DeleteBook)
 	r.GET("/books/:id", bookHandler.GetBookByID)
+	r.POST("/books/:id/restore", bookHandler.RestoreBook)
+	r.GET("/books/:id/related", bookHandler.RelatedBooks)
+
+	r.GET("/books/duplicates", duplicateHandler.FindDuplicates)
+	r.POST("/books/:id/merge", duplicateHandler.MergeBooks)
+
+	r.GET("/books/:id/revisions", revisionHandler.ListRevisions)
+	r.GET("/books/:id/revisions/diff", revisionHandler.DiffRevisions)
+	r.GET("/books/:id/revisions/:number", revisionHandler.GetRevision)
+	r.POST("/books/:id/revisions/:number/revert", revisionHandler.RevertBook)
 	r.GET("/books/count", bookHandler.CountBooks)
+	r.GET("/books/isbn/:isbn", bookHandler.GetBookByISBN)
+
+	r.POST("/books/import/marc", marcHandler.ImportMARC)
+	r.GET("/books/export/marc", marcHandler.ExportMARC)
+	r.POST("/books/import/spreadsheet", sheetHandler.ImportBooks)
+	r.GET("/books/export/spreadsheet", sheetHandler.ExportBooks)
+
+	r.POST("/books/:id/cover", coverHandler.SetCover)
+	r.GET("/books/:id/cover", coverHandler.GetCover)
+	r.DELETE("/books/:id/cover", coverHandler.DeleteCover)
+
+	r.GET("/books/:id/items", itemHandler.ListItems)
+	r.POST("/books/:id/items", itemHandler.CreateItem)
+	r.GET("/items/:id", itemHandler.GetItem)
+	r.GET("/items/barcode/:barcode", itemHandler.GetItemByBarcode)
+	r.PUT("/items/:id", itemHandler.UpdateItem)
+	r.DELETE("/items/:id", itemHandler.DeleteItem)
+
+	r.POST("/authors", authorHandler.CreateAuthor)
+	r.GET("/authors", authorHandler.SearchAuthors)
+	r.GET("/authors/:id", authorHandler.GetAuthor)
+	r.GET("/authors/:id/books", authorHandler.ListAuthorBooks)
+	r.PUT("/authors/:id", authorHandler.UpdateAuthor)
+	r.DELETE("/authors/:id", authorHandler.DeleteAuthor)
+	r.POST("/authors/:id/merge", authorHandler.MergeAuthors)
+
+	r.POST("/genres", genreHandler.CreateGenre)
+	r.GET("/genres", genreHandler.GenreTree)
+	r.GET("/genres/:id", genreHandler.GetGenre)
+	r.PUT("/genres/:id", genreHandler.UpdateGenre)
+	r.DELETE("/genres/:id", genreHandler.DeleteGenre)
+	r.POST("/genres/:id/merge", genreHandler.MergeGenres)
+
+	r.POST("/works", workHandler.CreateWork)
+	r.GET("/works", workHandler.SearchWorks)
+	r.GET("/works/:id", workHandler.GetWork)
+	r.PUT("/works/:id", workHandler.UpdateWork)
+	r.DELETE("/works/:id", workHandler.DeleteWork)
+	r.GET("/works/:id/borrows", workHandler.WorkBorrows)
+	r.GET("/works/:id/stats", workHandler.WorkStats)
+
+	r.POST("/series", seriesHandler.CreateSeries)
+	r.GET("/series", seriesHandler.SearchSeries)
+	r.GET("/series/:id", seriesHandler.GetSeries)
+	r.PUT("/series/:id", seriesHandler.UpdateSeries)
+	r.DELETE("/series/:id", seriesHandler.DeleteSeries)
 
 	r.POST("/users/login", userHandler.Login)
+	r.POST("/users", userHandler.RegisterUser)
 	r.GET("/users/search", userHandler.SearchUsers)
+	r.GET("/users/lockouts", userHandler.ListLockoutEvents)
 	r.PUT("/users", userHandler.UpdateUser)
-	r.POST("/users", userHandler.RegisterUser)
 	r.GET("/users/:id", userHandler.GetUserByID)
 
+	r.POST("/users/refresh", sessionHandler.Refresh)
+	r.POST("/users/logout", sessionHandler.Logout)
+	r.GET("/users/me/sessions", sessionHandler.ListSessions)
+	r.DELETE("/users/me/sessions", sessionHandler.RevokeAllSessions)
+	r.DELETE("/users/me/sessions/:id", sessionHandler.RevokeSession)
+
+	r.POST("/users/password/reset/request", verificationHandler.RequestPasswordReset)
+	r.POST("/users/password/reset/verify", verificationHandler.VerifyPasswordReset)
+	r.POST("/users/password/reset", verificationHandler.ResetPassword)
+	r.POST("/users/verify-phone", verificationHandler.VerifyPhone)
+	r.POST("/users/verify-phone/resend", verificationHandler.ResendPhoneVerification)
+
+	r.POST("/users/login/2fa", twoFactorHandler.CompleteLogin)
+	r.POST("/users/login/2fa/enroll", twoFactorHandler.BeginLoginEnrollment)
+	r.GET("/users/me/2fa", twoFactorHandler.GetStatus)
+	r.DELETE("/users/me/2fa", twoFactorHandler.Disable)
+	r.POST("/users/me/2fa/enroll", twoFactorHandler.BeginEnrollment)
+	r.POST("/users/me/2fa/confirm", twoFactorHandler.ConfirmEnrollment)
+	r.DELETE("/users/:id/2fa", twoFactorHandler.ResetUser)
+	r.GET("/settings/2fa", twoFactorHandler.GetPolicy)
+	r.PUT("/settings/2fa", twoFactorHandler.SetPolicy)
+
+	r.GET("/audit", auditHandler.ListAuditEntries)
+
+	r.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+	r.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+	r.GET("/api-keys/:id", apiKeyHandler.GetAPIKey)
+	r.POST("/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+	r.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+
+	// Каждый маршрут обязан иметь правило доступа
+	var routes []string
+	for _, ri := range r.Routes() {
+		routes = append(routes, auth.RouteKey(ri.Method, ri.Path))
+	}
+	if err := auth.Policy.Verify(routes); err != nil {
+		log.Fatal("Ошибка таблицы доступа:", err)
+	}
+
 	srv := &http.Server{
 		Addr:    ":" + cfg.HTTPPort,
 		Handler: r,
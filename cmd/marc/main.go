@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"library-Mongo/internal/config"
+	"library-Mongo/internal/domain"
+	"library-Mongo/internal/repo/mongo"
+	"library-Mongo/internal/usecase"
+	"log"
+	"os"
+	"strings"
+)
+
+// go run ./cmd/marc import [-dry-run] [-format iso2709|marcxml] file.mrc
+// go run ./cmd/marc export [-o catalog.xml] [-q ...] [-author ...] [-genre ...]
+// импорт и выгрузка каталога в MARC21 (из корня проекта); отчёт импорта печатается в stdout как JSON
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	ctx := context.Background()
+
+	// Загрузка конфигурации
+	cfg := config.LoadConfig()
+
+	// Подключение к Mongo
+	db, err := mongo.Connect(ctx, cfg)
+	if err != nil {
+		log.Fatal("Ошибка подключения к Mongo:", err)
+	}
+
+	bookRepo := mongo.NewBookRepo(db)
+	authorRepo := mongo.NewAuthorRepo(db)
+	AuditUC := usecase.NewAuditUsecase(mongo.NewAuditRepo(db), cfg.AuditRetention)
+	BookUC := usecase.NewBookUsecase(bookRepo, mongo.NewItemRepo(db), authorRepo, mongo.NewGenreRepo(db), mongo.NewWorkRepo(db), mongo.NewSeriesRepo(db), mongo.NewBorrowRepo(db), mongo.NewBookRevisionRepo(db), AuditUC)
+	AuthorUC := usecase.NewAuthorUsecase(authorRepo, bookRepo, AuditUC)
+	MARCUC := usecase.NewMARCUsecase(bookRepo, authorRepo, BookUC, AuthorUC)
+
+	switch os.Args[1] {
+	case "import":
+		runImport(ctx, MARCUC, os.Args[2:])
+	case "export":
+		runExport(ctx, MARCUC, os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func runImport(ctx context.Context, uc usecase.MARCUC, args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "только отчёт, без изменений каталога")
+	format := fs.String("format", "", "iso2709 или marcxml; по умолчанию по содержимому")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatal("Ошибка открытия файла:", err)
+	}
+	defer f.Close()
+
+	report, err := uc.ImportMARC(ctx, f, *format, *dryRun)
+	if err != nil {
+		log.Fatal("Ошибка импорта:", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Записей: %d, заведено: %d, готово к импорту: %d, дубликатов: %d, ошибочных: %d, не сохранено: %d",
+		report.Total, report.Created, report.Ready, report.Duplicates, report.Invalid, report.Failed)
+}
+
+func runExport(ctx context.Context, uc usecase.MARCUC, args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("o", "", "файл выгрузки; по умолчанию stdout")
+	var filter domain.BookFilter
+	fs.StringVar(&filter.Query, "q", "", "полнотекстовый запрос")
+	fs.StringVar(&filter.Title, "title", "", "название (подстрока)")
+	fs.StringVar(&filter.Author, "author", "", "автор (подстрока)")
+	fs.StringVar(&filter.AuthorID, "author-id", "", "ID автора из справочника")
+	fs.StringVar(&filter.ISBN, "isbn", "", "ISBN или его часть")
+	fs.IntVar(&filter.Decade, "decade", 0, "десятилетие издания (1990)")
+	genres := fs.String("genre", "", "жанры через запятую")
+	_ = fs.Parse(args)
+	if *genres != "" {
+		filter.Genres = strings.Split(*genres, ",")
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("Ошибка создания файла:", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	count, err := uc.ExportMARC(ctx, filter, w)
+	if err != nil {
+		log.Fatal("Ошибка выгрузки:", err)
+	}
+	log.Printf("Выгружено записей: %d", count)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  marc import [-dry-run] [-format iso2709|marcxml] file
+  marc export [-o file] [-q query] [-title t] [-author a] [-author-id id] [-genre g1,g2] [-isbn n] [-decade 1990]`)
+	os.Exit(2)
+}
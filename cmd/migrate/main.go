@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"library-Mongo/internal/config"
+	migration "library-Mongo/internal/migration/mongo"
+	"library-Mongo/internal/repo/mongo"
+	"log"
+)
+
+// go run ./cmd/migrate
+// создаёт индексы и выполняет разовые миграции данных (из корня проекта)
+
+func main() {
+	ctx := context.Background()
+
+	// Загрузка конфигурации
+	cfg := config.LoadConfig()
+
+	// Подключение к Mongo
+	db, err := mongo.Connect(ctx, cfg)
+	if err != nil {
+		log.Fatal("Ошибка подключения к Mongo:", err)
+	}
+
+	if err := migration.Migrate(db, cfg); err != nil {
+		log.Fatal("Ошибка миграции:", err)
+	}
+
+	log.Println("Миграция завершена")
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"library-Mongo/internal/config"
+	"library-Mongo/internal/domain"
+	"library-Mongo/internal/repo/mongo"
+	"library-Mongo/internal/sheet"
+	"library-Mongo/internal/usecase"
+	"log"
+	"os"
+	"strings"
+)
+
+// go run ./cmd/sheet import [-dry-run] [-format csv|xlsx] [-map title=Название ...] file.xlsx
+// go run ./cmd/sheet export -format csv|xlsx [-o books.xlsx] [-q ...] [-author ...] [-genre ...]
+// импорт и выгрузка книг таблицами (из корня проекта); отчёт импорта печатается в stdout как JSON
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	ctx := context.Background()
+
+	// Загрузка конфигурации
+	cfg := config.LoadConfig()
+
+	// Подключение к Mongo
+	db, err := mongo.Connect(ctx, cfg)
+	if err != nil {
+		log.Fatal("Ошибка подключения к Mongo:", err)
+	}
+
+	bookRepo := mongo.NewBookRepo(db)
+	AuditUC := usecase.NewAuditUsecase(mongo.NewAuditRepo(db), cfg.AuditRetention)
+	BookUC := usecase.NewBookUsecase(bookRepo, mongo.NewItemRepo(db), mongo.NewAuthorRepo(db), mongo.NewGenreRepo(db), mongo.NewWorkRepo(db), mongo.NewSeriesRepo(db), mongo.NewBorrowRepo(db), mongo.NewBookRevisionRepo(db), AuditUC)
+	SheetUC := usecase.NewSheetUsecase(bookRepo, BookUC)
+
+	switch os.Args[1] {
+	case "import":
+		runImport(ctx, SheetUC, os.Args[2:])
+	case "export":
+		runExport(ctx, SheetUC, os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func runImport(ctx context.Context, uc usecase.SheetUC, args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "только отчёт, без изменений каталога")
+	format := fs.String("format", "", "csv или xlsx; по умолчанию по содержимому")
+	mapping := mappingFlag{}
+	fs.Var(mapping, "map", "сопоставление поле=Заголовок колонки (можно несколько)")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatal("Ошибка открытия файла:", err)
+	}
+	defer f.Close()
+
+	report, err := uc.ImportBooks(ctx, f, *format, mapping, *dryRun)
+	if err != nil {
+		log.Fatal("Ошибка импорта:", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Строк: %d, заведено: %d, обновлено: %d, без изменений: %d, прошло проверку: %d, ошибочных: %d, не сохранено: %d",
+		report.Total, report.Created, report.Updated, report.Unchanged, report.Valid, report.Invalid, report.Failed)
+}
+
+func runExport(ctx context.Context, uc usecase.SheetUC, args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "csv или xlsx")
+	out := fs.String("o", "", "файл выгрузки; по умолчанию stdout")
+	var filter domain.BookFilter
+	fs.StringVar(&filter.Query, "q", "", "полнотекстовый запрос")
+	fs.StringVar(&filter.Title, "title", "", "название (подстрока)")
+	fs.StringVar(&filter.Author, "author", "", "автор (подстрока)")
+	fs.StringVar(&filter.AuthorID, "author-id", "", "ID автора из справочника")
+	fs.StringVar(&filter.ISBN, "isbn", "", "ISBN или его часть")
+	fs.IntVar(&filter.Decade, "decade", 0, "десятилетие издания (1990)")
+	genres := fs.String("genre", "", "жанры через запятую")
+	_ = fs.Parse(args)
+	if !sheet.IsFormat(*format) {
+		usage()
+	}
+	if *genres != "" {
+		filter.Genres = strings.Split(*genres, ",")
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("Ошибка создания файла:", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	count, err := uc.ExportBooks(ctx, filter, *format, w)
+	if err != nil {
+		log.Fatal("Ошибка выгрузки:", err)
+	}
+	log.Printf("Выгружено книг: %d", count)
+}
+
+// mappingFlag — повторяемый флаг -map поле=Заголовок
+type mappingFlag map[string]string
+
+func (m mappingFlag) String() string {
+	return fmt.Sprint(map[string]string(m))
+}
+
+func (m mappingFlag) Set(v string) error {
+	field, column, ok := strings.Cut(v, "=")
+	if !ok || field == "" || column == "" {
+		return fmt.Errorf("want field=Column, got %q", v)
+	}
+	m[strings.ToLower(strings.TrimSpace(field))] = column
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  sheet import [-dry-run] [-format csv|xlsx] [-map field=Column ...] file
+  sheet export -format csv|xlsx [-o file] [-q query] [-title t] [-author a] [-author-id id] [-genre g1,g2] [-isbn n] [-decade 1990]`)
+	os.Exit(2)
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+// RequestMeta — сведения о HTTP-запросе, которые попадают в журнал аудита
+type RequestMeta struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+type requestKey struct{}
+
+func WithRequest(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestKey{}, meta)
+}
+
+// RequestFromContext возвращает сведения о запросе, положенные request-id middleware
+func RequestFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestKey{}).(RequestMeta)
+	return meta
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+)
+
+// redacted — поля, значения которых не пишутся в журнал (фиксируется только факт изменения)
+var redacted = map[string]bool{
+	"password": true,
+}
+
+const redactedValue = "***"
+
+// Diff сравнивает два состояния сущности по их bson-представлению.
+// before == nil — создание, after == nil — удаление.
+func Diff(before, after any) (map[string]domain.AuditChange, error) {
+	from, err := toMap(before)
+	if err != nil {
+		return nil, fmt.Errorf("audit.Diff: %w", err)
+	}
+	to, err := toMap(after)
+	if err != nil {
+		return nil, fmt.Errorf("audit.Diff: %w", err)
+	}
+
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make(map[string]domain.AuditChange)
+	for _, k := range keys {
+		if k == "_id" {
+			continue
+		}
+		a, b := from[k], to[k]
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+		if redacted[k] {
+			a, b = maskValue(a), maskValue(b)
+		}
+		changes[k] = domain.AuditChange{From: a, To: b}
+	}
+	return changes, nil
+}
+
+func maskValue(v any) any {
+	if v == nil {
+		return nil
+	}
+	return redactedValue
+}
+
+func toMap(v any) (bson.M, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return bson.M{}, nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	for k, val := range m {
+		m[k] = normalize(val)
+	}
+	return m, nil
+}
+
+// normalize приводит bson-типы к значениям, которые одинаково читаются в Mongo и в JSON
+func normalize(v any) any {
+	switch t := v.(type) {
+	case primitive.ObjectID:
+		return t.Hex()
+	case primitive.DateTime:
+		return t.Time().UTC()
+	case primitive.A:
+		res := make([]any, len(t))
+		for i, item := range t {
+			res[i] = normalize(item)
+		}
+		return res
+	case bson.M:
+		res := make(map[string]any, len(t))
+		for k, item := range t {
+			res[k] = normalize(item)
+		}
+		return res
+	case primitive.D:
+		res := make(map[string]any, len(t))
+		for _, e := range t {
+			res[e.Key] = normalize(e.Value)
+		}
+		return res
+	}
+	return v
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Области действия API-ключей
+const (
+	ScopeCatalogRead      = "catalog:read"      // поиск и просмотр книг
+	ScopeCatalogWrite     = "catalog:write"     // изменение каталога
+	ScopeCirculationRead  = "circulation:read"  // отчёты по выдачам
+	ScopeCirculationWrite = "circulation:write" // выдача и возврат книг
+)
+
+var scopes = []string{ScopeCatalogRead, ScopeCatalogWrite, ScopeCirculationRead, ScopeCirculationWrite}
+
+// apiKeyPrefix отличает API-ключи от других секретов (например, в логах и сканерах утечек)
+const apiKeyPrefix = "lib_"
+
+// IsValidScope — одна из известных областей действия
+func IsValidScope(scope string) bool {
+	return slices.Contains(scopes, scope)
+}
+
+// NewAPIKey генерирует ключ вида lib_<8 hex>_<секрет>. Видимый префикс
+// lib_<8 hex> хранится открыто для списка ключей, сам ключ — только как SHA-256.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", fmt.Errorf("NewAPIKey: %w", err)
+	}
+	secret, _, err := NewOpaqueToken()
+	if err != nil {
+		return "", "", "", fmt.Errorf("NewAPIKey: %w", err)
+	}
+	prefix = apiKeyPrefix + hex.EncodeToString(id)
+	key = prefix + "_" + secret
+	return key, prefix, HashOpaqueToken(key), nil
+}
+
+// LooksLikeAPIKey — строка имеет формат ключа (до обращения к БД)
+func LooksLikeAPIKey(key string) bool {
+	return strings.HasPrefix(key, apiKeyPrefix) && len(key) > len(apiKeyPrefix)+9
+}
+
+// ParseAllowList проверяет список разрешённых адресов: отдельные IP или подсети CIDR
+func ParseAllowList(list []string) ([]netip.Prefix, error) {
+	res := make([]netip.Prefix, 0, len(list))
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if strings.Contains(item, "/") {
+			p, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("ParseAllowList: %w", err)
+			}
+			res = append(res, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("ParseAllowList: %w", err)
+		}
+		res = append(res, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return res, nil
+}
+
+// IPAllowed — пустой список разрешает любой адрес
+func IPAllowed(list []string, ip string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	prefixes, err := ParseAllowList(list)
+	if err != nil {
+		return false
+	}
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package auth
+
+import "context"
+
+// Principal — аутентифицированный пользователь текущего запроса.
+// Для запроса по API-ключу заполнены только APIKeyID и Scopes.
+type Principal struct {
+	UserID    string
+	Role      string
+	SessionID string
+	APIKeyID  string
+	Scopes    []string
+}
+
+// IsAPIKey — запрос выполнен по API-ключу, а не от имени пользователя
+func (p Principal) IsAPIKey() bool {
+	return p.APIKeyID != ""
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext возвращает пользователя, положенного в контекст auth-middleware
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
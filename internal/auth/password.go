@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+	customErr "library-Mongo/internal/errors"
+)
+
+// MinPasswordLength — минимальная длина пароля
+const MinPasswordLength = 8
+
+type PasswordHasher struct {
+	cost  int
+	dummy []byte // хэш с той же стоимостью для VerifyDummy
+}
+
+func NewPasswordHasher(cost int) *PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	// Считается сразу: иначе первый вход с неизвестным телефоном был бы заметно дольше остальных
+	dummy, _ := bcrypt.GenerateFromPassword([]byte("dummy password for unknown users"), cost)
+	return &PasswordHasher{cost: cost, dummy: dummy}
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("PasswordHasher.Hash: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify сравнивает пароль с сохранённым значением. Старые записи, где пароль
+// хранится открытым текстом, сравниваются напрямую; needsRehash == true, если
+// запись надо перезаписать хэшем с текущей стоимостью.
+func (h *PasswordHasher) Verify(stored, password string) (ok bool, needsRehash bool) {
+	if !IsPasswordHashed(stored) {
+		ok = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return ok, ok
+	}
+	if bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(stored))
+	return true, err != nil || cost != h.cost
+}
+
+// VerifyDummy тратит на проверку столько же, сколько Verify с хэшем текущей стоимости,
+// и всегда неуспешна: по времени ответа входа нельзя узнать, зарегистрирован ли телефон
+func (h *PasswordHasher) VerifyDummy(password string) {
+	_ = bcrypt.CompareHashAndPassword(h.dummy, []byte(password))
+}
+
+// IsPasswordHashed — значение является bcrypt-хэшем
+func IsPasswordHashed(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// ValidatePassword — минимальная политика: не короче MinPasswordLength,
+// есть хотя бы одна буква и одна цифра
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return customErr.ErrWeakPassword
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return customErr.ErrWeakPassword
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	customErr "library-Mongo/internal/errors"
+)
+
+const (
+	RoleAdmin     = "admin"
+	RoleLibrarian = "librarian"
+	RoleReader    = "reader"
+)
+
+var (
+	staff    = []string{RoleAdmin, RoleLibrarian}
+	everyone = []string{RoleAdmin, RoleLibrarian, RoleReader}
+)
+
+// Rule — правило доступа к одному маршруту
+type Rule struct {
+	Public bool     // доступен без аутентификации
+	Roles  []string // роли, которым разрешён вызов
+	Scopes []string // области API-ключа, любой из которых разрешает вызов; пусто — ключам недоступен
+}
+
+// RoutePolicy — таблица доступа, ключ "METHOD /path" в формате gin FullPath
+type RoutePolicy map[string]Rule
+
+// Policy — единая таблица доступа ко всем маршрутам из cmd/app/main.go.
+// Ограничения "только свои данные" для читателей проверяются в usecase (CanAccessOwn).
+var Policy = RoutePolicy{
+	"GET /swagger/*any": {Public: true},
+
+	"POST /users/login":   {Public: true},
+	"POST /users/refresh": {Public: true},
+	"POST /users/logout":  {Roles: everyone},
+
+	// Второй шаг входа авторизуется challenge-токеном из ответа /users/login
+	"POST /users/login/2fa":        {Public: true},
+	"POST /users/login/2fa/enroll": {Public: true},
+	"GET /users/me/2fa":            {Roles: everyone},
+	"DELETE /users/me/2fa":         {Roles: everyone},
+	"POST /users/me/2fa/enroll":    {Roles: everyone},
+	"POST /users/me/2fa/confirm":   {Roles: everyone},
+	"DELETE /users/:id/2fa":        {Roles: []string{RoleAdmin}},
+	"GET /settings/2fa":            {Roles: []string{RoleAdmin}},
+	"PUT /settings/2fa":            {Roles: []string{RoleAdmin}},
+
+	"POST /users/password/reset/request": {Public: true},
+	"POST /users/password/reset/verify":  {Public: true},
+	"POST /users/password/reset":         {Public: true},
+	"POST /users/verify-phone":           {Public: true},
+	"POST /users/verify-phone/resend":    {Public: true},
+
+	"GET /users/me/sessions":        {Roles: everyone},
+	"DELETE /users/me/sessions":     {Roles: everyone},
+	"DELETE /users/me/sessions/:id": {Roles: everyone},
+
+	"POST /users":         {Public: true}, // роль нового пользователя ограничивается в UserUsecase.RegisterUser
+	"GET /users/search":   {Roles: staff},
+	"GET /users/lockouts": {Roles: []string{RoleAdmin}},
+	"PUT /users":          {Roles: everyone},
+	"GET /users/:id":      {Roles: everyone},
+
+	"GET /borrow/history/:userID": {Roles: everyone},
+	"POST /borrow":                {Roles: staff, Scopes: []string{ScopeCirculationWrite}},
+	"POST /borrow/return":         {Roles: staff, Scopes: []string{ScopeCirculationWrite}},
+	"GET /borrow/overdue":         {Roles: staff, Scopes: []string{ScopeCirculationRead}},
+	"GET /borrow/stats":           {Roles: staff, Scopes: []string{ScopeCirculationRead}},
+	"GET /borrow/active-count":    {Roles: staff, Scopes: []string{ScopeCirculationRead}},
+
+	"POST /books":       {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"PUT /books":        {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /books/search": {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"DELETE /books/:id": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /books/:id":    {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"GET /books/count":  {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+
+	"POST /books/:id/restore": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /books/:id/related":  {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+
+	"GET /books/duplicates": {Roles: staff, Scopes: []string{ScopeCatalogRead}},
+	"POST /books/:id/merge": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"GET /books/:id/revisions":                 {Roles: staff, Scopes: []string{ScopeCatalogRead}},
+	"GET /books/:id/revisions/diff":            {Roles: staff, Scopes: []string{ScopeCatalogRead}},
+	"GET /books/:id/revisions/:number":         {Roles: staff, Scopes: []string{ScopeCatalogRead}},
+	"POST /books/:id/revisions/:number/revert": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"GET /books/isbn/:isbn": {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+
+	"POST /books/import/marc": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /books/export/marc":  {Roles: staff, Scopes: []string{ScopeCatalogRead}},
+
+	"POST /books/import/spreadsheet": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /books/export/spreadsheet":  {Roles: staff, Scopes: []string{ScopeCatalogRead}},
+
+	"POST /books/:id/cover":   {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /books/:id/cover":    {Public: true}, // открывается тегом img, без заголовка авторизации
+	"DELETE /books/:id/cover": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"GET /books/:id/items":        {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"POST /books/:id/items":       {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /items/:id":              {Roles: staff, Scopes: []string{ScopeCatalogRead, ScopeCirculationRead}},
+	"GET /items/barcode/:barcode": {Roles: staff, Scopes: []string{ScopeCatalogRead, ScopeCirculationRead}},
+	"PUT /items/:id":              {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"DELETE /items/:id":           {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"POST /authors":           {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /authors":            {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"GET /authors/:id":        {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"GET /authors/:id/books":  {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"PUT /authors/:id":        {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"DELETE /authors/:id":     {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"POST /authors/:id/merge": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"POST /genres":           {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /genres":            {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"GET /genres/:id":        {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"PUT /genres/:id":        {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"DELETE /genres/:id":     {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"POST /genres/:id/merge": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"POST /works":            {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /works":             {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"GET /works/:id":         {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"PUT /works/:id":         {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"DELETE /works/:id":      {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /works/:id/borrows": {Roles: staff, Scopes: []string{ScopeCirculationRead}},
+	"GET /works/:id/stats":   {Roles: staff, Scopes: []string{ScopeCirculationRead}},
+
+	"POST /series":       {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"GET /series":        {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"GET /series/:id":    {Roles: everyone, Scopes: []string{ScopeCatalogRead}},
+	"PUT /series/:id":    {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+	"DELETE /series/:id": {Roles: staff, Scopes: []string{ScopeCatalogWrite}},
+
+	"GET /audit": {Roles: []string{RoleAdmin}},
+
+	"POST /api-keys":            {Roles: []string{RoleAdmin}},
+	"GET /api-keys":             {Roles: []string{RoleAdmin}},
+	"GET /api-keys/:id":         {Roles: []string{RoleAdmin}},
+	"POST /api-keys/:id/rotate": {Roles: []string{RoleAdmin}},
+	"DELETE /api-keys/:id":      {Roles: []string{RoleAdmin}},
+}
+
+// RouteKey — ключ маршрута в таблице доступа
+func RouteKey(method, path string) string {
+	return method + " " + path
+}
+
+// Check решает, можно ли вызвать маршрут. principal == nil — анонимный запрос.
+// Маршрут без правила запрещён всем (fail closed). API-ключ проходит по Scopes, а не по Roles.
+// Возвращает ErrUnauthorized, если нужна аутентификация, и ErrForbidden при неподходящей роли.
+func (p RoutePolicy) Check(method, path string, principal *Principal) error {
+	rule, ok := p[RouteKey(method, path)]
+	if !ok {
+		return customErr.ErrForbidden
+	}
+	if rule.Public {
+		return nil
+	}
+	if principal == nil {
+		return customErr.ErrUnauthorized
+	}
+	if principal.IsAPIKey() {
+		for _, scope := range principal.Scopes {
+			if slices.Contains(rule.Scopes, scope) {
+				return nil
+			}
+		}
+		return customErr.ErrForbidden
+	}
+	if !slices.Contains(rule.Roles, principal.Role) {
+		return customErr.ErrForbidden
+	}
+	return nil
+}
+
+// Verify проверяет, что для каждого зарегистрированного маршрута (ключи RouteKey) есть правило
+func (p RoutePolicy) Verify(routes []string) error {
+	var missing []string
+	for _, key := range routes {
+		if _, ok := p[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("RoutePolicy.Verify: no access rule for %v", missing)
+	}
+	return nil
+}
+
+// IsStaff — сотрудники библиотеки (admin, librarian)
+func IsStaff(role string) bool {
+	return slices.Contains(staff, role)
+}
+
+// CanAccessOwn — сотрудникам доступны данные любого пользователя,
+// читателю — только свои
+func CanAccessOwn(p Principal, ownerID string) bool {
+	return IsStaff(p.Role) || (p.UserID != "" && p.UserID == ownerID)
+}
+
+// IsValidRole — одна из ролей admin, librarian, reader
+func IsValidRole(role string) bool {
+	return slices.Contains(everyone, role)
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	customErr "library-Mongo/internal/errors"
+)
+
+// Каждая роль и API-ключ против каждого класса маршрутов таблицы Policy
+func TestPolicyCheck(t *testing.T) {
+	var (
+		anonymous *Principal
+		reader    = &Principal{UserID: "u1", Role: RoleReader}
+		librarian = &Principal{UserID: "u2", Role: RoleLibrarian}
+		admin     = &Principal{UserID: "u3", Role: RoleAdmin}
+		unknown   = &Principal{UserID: "u4", Role: "guest"}
+		catalogRO = &Principal{APIKeyID: "k1", Scopes: []string{ScopeCatalogRead}}
+		catalogRW = &Principal{APIKeyID: "k2", Scopes: []string{ScopeCatalogRead, ScopeCatalogWrite}}
+		circRO    = &Principal{APIKeyID: "k3", Scopes: []string{ScopeCirculationRead}}
+		circRW    = &Principal{APIKeyID: "k4", Scopes: []string{ScopeCirculationWrite}}
+		noScopes  = &Principal{APIKeyID: "k5"}
+		// API-ключ с ролью пользователя проходит только по Scopes
+		keyWithRole = &Principal{APIKeyID: "k6", Role: RoleAdmin}
+	)
+
+	type want map[*Principal]error
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   want
+	}{
+		{
+			name: "public", method: "POST", path: "/users/login",
+			want: want{anonymous: nil, reader: nil, librarian: nil, admin: nil, unknown: nil, catalogRO: nil, noScopes: nil},
+		},
+		{
+			name: "public cover", method: "GET", path: "/books/:id/cover",
+			want: want{anonymous: nil, reader: nil, noScopes: nil},
+		},
+		{
+			name: "everyone without scopes", method: "GET", path: "/users/:id",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: nil, librarian: nil, admin: nil,
+				unknown: customErr.ErrForbidden, catalogRO: customErr.ErrForbidden, keyWithRole: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "catalog read", method: "GET", path: "/books/search",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: nil, librarian: nil, admin: nil, unknown: customErr.ErrForbidden,
+				catalogRO: nil, catalogRW: nil, circRO: customErr.ErrForbidden, noScopes: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "catalog write", method: "POST", path: "/books",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: customErr.ErrForbidden, librarian: nil, admin: nil,
+				catalogRO: customErr.ErrForbidden, catalogRW: nil, circRW: customErr.ErrForbidden, keyWithRole: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "circulation read", method: "GET", path: "/borrow/overdue",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: customErr.ErrForbidden, librarian: nil, admin: nil,
+				circRO: nil, circRW: customErr.ErrForbidden, catalogRW: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "circulation write", method: "POST", path: "/borrow",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: customErr.ErrForbidden, librarian: nil, admin: nil,
+				circRW: nil, circRO: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "item lookup by either scope", method: "GET", path: "/items/:id",
+			want: want{reader: customErr.ErrForbidden, catalogRO: nil, circRO: nil, circRW: customErr.ErrForbidden},
+		},
+		{
+			name: "staff only", method: "GET", path: "/users/search",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: customErr.ErrForbidden, librarian: nil, admin: nil,
+				catalogRW: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "admin only", method: "GET", path: "/audit",
+			want: want{
+				anonymous: customErr.ErrUnauthorized, reader: customErr.ErrForbidden, librarian: customErr.ErrForbidden, admin: nil,
+				catalogRW: customErr.ErrForbidden, keyWithRole: customErr.ErrForbidden,
+			},
+		},
+		{
+			name: "route without rule", method: "GET", path: "/nowhere",
+			want: want{anonymous: customErr.ErrForbidden, reader: customErr.ErrForbidden, admin: customErr.ErrForbidden, catalogRW: customErr.ErrForbidden},
+		},
+		{
+			name: "wrong method", method: "DELETE", path: "/books/search",
+			want: want{admin: customErr.ErrForbidden},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for p, wantErr := range tt.want {
+				err := Policy.Check(tt.method, tt.path, p)
+				if !errors.Is(err, wantErr) {
+					t.Errorf("%s %s as %s: got %v, want %v", tt.method, tt.path, describe(p), err, wantErr)
+				}
+			}
+		})
+	}
+}
+
+// Инварианты таблицы: у закрытого правила есть роли, роли и области — только известные
+func TestPolicyRules(t *testing.T) {
+	scopes := map[string]bool{
+		ScopeCatalogRead: true, ScopeCatalogWrite: true,
+		ScopeCirculationRead: true, ScopeCirculationWrite: true,
+	}
+	for key, rule := range Policy {
+		if rule.Public {
+			if len(rule.Roles) > 0 || len(rule.Scopes) > 0 {
+				t.Errorf("%s: public rule must not list roles or scopes", key)
+			}
+			continue
+		}
+		if len(rule.Roles) == 0 {
+			t.Errorf("%s: rule allows nobody", key)
+		}
+		for _, role := range rule.Roles {
+			if !IsValidRole(role) {
+				t.Errorf("%s: unknown role %q", key, role)
+			}
+		}
+		for _, scope := range rule.Scopes {
+			if !scopes[scope] {
+				t.Errorf("%s: unknown scope %q", key, scope)
+			}
+		}
+	}
+}
+
+func TestPolicyVerify(t *testing.T) {
+	if err := Policy.Verify([]string{RouteKey("GET", "/books/search"), RouteKey("POST", "/borrow")}); err != nil {
+		t.Errorf("known routes: %v", err)
+	}
+	if err := Policy.Verify([]string{RouteKey("GET", "/books/search"), RouteKey("GET", "/nowhere")}); err == nil {
+		t.Error("route without rule passed Verify")
+	}
+}
+
+// Читатель видит только свои данные, сотрудники — любые
+func TestCanAccessOwn(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		ownerID   string
+		want      bool
+	}{
+		{name: "reader own", principal: Principal{UserID: "u1", Role: RoleReader}, ownerID: "u1", want: true},
+		{name: "reader other", principal: Principal{UserID: "u1", Role: RoleReader}, ownerID: "u2", want: false},
+		{name: "reader without ID", principal: Principal{Role: RoleReader}, ownerID: "", want: false},
+		{name: "librarian other", principal: Principal{UserID: "u1", Role: RoleLibrarian}, ownerID: "u2", want: true},
+		{name: "admin other", principal: Principal{UserID: "u1", Role: RoleAdmin}, ownerID: "u2", want: true},
+		{name: "unknown role other", principal: Principal{UserID: "u1", Role: "guest"}, ownerID: "u2", want: false},
+		{name: "unknown role own", principal: Principal{UserID: "u1", Role: "guest"}, ownerID: "u1", want: true},
+		{name: "API key", principal: Principal{APIKeyID: "k1", Scopes: []string{ScopeCatalogRead}}, ownerID: "u1", want: false},
+		{name: "API key without owner", principal: Principal{APIKeyID: "k1"}, ownerID: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanAccessOwn(tt.principal, tt.ownerID); got != tt.want {
+				t.Errorf("CanAccessOwn(%+v, %q) = %v, want %v", tt.principal, tt.ownerID, got, tt.want)
+			}
+		})
+	}
+}
+
+func describe(p *Principal) string {
+	switch {
+	case p == nil:
+		return "anonymous"
+	case p.IsAPIKey():
+		return "API key " + p.APIKeyID
+	}
+	return "role " + p.Role
+}
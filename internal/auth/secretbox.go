@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// SecretBox шифрует секреты, которые нужно уметь прочитать (TOTP), AES-256-GCM
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox выводит 256-битный ключ из произвольной строки
+func NewSecretBox(key string) (*SecretBox, error) {
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("NewSecretBox: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("NewSecretBox: %w", err)
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+func (b *SecretBox) Seal(plain string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("SecretBox.Seal: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *SecretBox) Open(sealed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("SecretBox.Open: %w", err)
+	}
+	n := b.aead.NonceSize()
+	if len(raw) < n {
+		return "", fmt.Errorf("SecretBox.Open: ciphertext too short")
+	}
+	plain, err := b.aead.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", fmt.Errorf("SecretBox.Open: %w", err)
+	}
+	return string(plain), nil
+}
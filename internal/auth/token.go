@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+)
+
+// Claims — полезная нагрузка access-токена
+type Claims struct {
+	Role      string `json:"role"` // роль пользователя на момент выдачи
+	SessionID string `json:"sid"`  // сессия, к которой привязан токен
+	jwt.RegisteredClaims
+}
+
+// ChallengeClaims — полезная нагрузка токена второго шага входа
+type ChallengeClaims struct {
+	Purpose string `json:"purpose"` // ChallengeVerify или ChallengeEnroll
+	jwt.RegisteredClaims
+}
+
+// Назначение токена второго шага входа
+const (
+	ChallengeVerify = "verify" // ввести код из приложения или код восстановления
+	ChallengeEnroll = "enroll" // роль требует 2FA, а аутентификатор ещё не привязан
+)
+
+// Значения aud не дают использовать токен второго шага как access-токен и наоборот
+const (
+	audienceAccess    = "access"
+	audienceChallenge = "2fa"
+)
+
+type TokenManager struct {
+	secret       []byte
+	ttl          time.Duration
+	challengeTTL time.Duration
+}
+
+func NewTokenManager(secret string, ttl, challengeTTL time.Duration) *TokenManager {
+	return &TokenManager{
+		secret:       []byte(secret),
+		ttl:          ttl,
+		challengeTTL: challengeTTL,
+	}
+}
+
+// IssueAccessToken подписывает токен с ID пользователя (sub), ролью, сессией и сроком действия
+func (m *TokenManager) IssueAccessToken(user domain.User, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := Claims{
+		Role:      user.Role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			Audience:  jwt.ClaimStrings{audienceAccess},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("TokenManager.IssueAccessToken: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// ParseAccessToken проверяет подпись и срок действия токена
+func (m *TokenManager) ParseAccessToken(raw string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithAudience(audienceAccess))
+	if err != nil || claims.Subject == "" || claims.SessionID == "" {
+		return nil, customErr.ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// IssueChallengeToken подписывает короткоживущий токен, подтверждающий, что пароль уже проверен
+func (m *TokenManager) IssueChallengeToken(userID, purpose string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.challengeTTL)
+
+	claims := ChallengeClaims{
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{audienceChallenge},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("TokenManager.IssueChallengeToken: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// ParseChallengeToken проверяет токен второго шага входа
+func (m *TokenManager) ParseChallengeToken(raw string) (*ChallengeClaims, error) {
+	var claims ChallengeClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithAudience(audienceChallenge))
+	if err != nil || claims.Subject == "" || claims.Purpose == "" {
+		return nil, customErr.ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// NewOpaqueToken генерирует случайный непрозрачный токен и его SHA-256 для хранения в БД
+func NewOpaqueToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("NewOpaqueToken: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken — SHA-256 токена в hex; в БД хранится только он
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
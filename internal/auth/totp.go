@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры TOTP по RFC 6238 — значения по умолчанию всех приложений-аутентификаторов
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	totpSkew   = 1 // допускаем соседний 30-секундный интервал
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret — случайный 160-битный секрет в base32
+func NewTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("NewTOTPSecret: %w", err)
+	}
+	return totpEncoding.EncodeToString(buf), nil
+}
+
+// TOTPURI — otpauth:// URI для QR-кода приложения-аутентификатора
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// ValidateTOTP проверяет код и возвращает номер его интервала — по нему
+// вызывающий отсекает повторное использование кода
+func ValidateTOTP(secret, code string, now time.Time) (step int64, ok bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for delta := int64(-totpSkew); delta <= totpSkew; delta++ {
+		s := current + delta
+		if subtle.ConstantTimeCompare([]byte(hotp(key, s)), []byte(code)) == 1 {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// hotp — RFC 4226 с динамическим усечением
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// NewRecoveryCodes — одноразовые коды восстановления вида XXXXX-XXXXX
+func NewRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	buf := make([]byte, 7)
+	for i := 0; i < n; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("NewRecoveryCodes: %w", err)
+		}
+		raw := totpEncoding.EncodeToString(buf)[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode приводит введённый код к виду, в котором он хэшировался
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	code = strings.ReplaceAll(code, "-", "")
+	if len(code) != 10 {
+		return code
+	}
+	return code[:5] + "-" + code[5:]
+}
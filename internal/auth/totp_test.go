@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// Секрет тестовых векторов RFC 4226 и RFC 6238 (SHA-1)
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// RFC 4226, приложение D
+func TestHOTPVectors(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp([]byte("12345678901234567890"), int64(counter)); got != code {
+			t.Errorf("hotp(%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+// RFC 6238, приложение B: векторы SHA-1 из 8 цифр, у нас — их последние 6
+func TestValidateTOTPVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+		step int64
+	}{
+		{59, "287082", 1},
+		{1111111109, "081804", 37037036},
+		{1111111111, "050471", 37037037},
+		{1234567890, "005924", 41152263},
+		{2000000000, "279037", 66666666},
+		{20000000000, "353130", 666666666},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.step {
+			t.Errorf("ValidateTOTP(%s at %d) = %d, %v; want %d, true", tt.code, tt.unix, step, ok, tt.step)
+		}
+	}
+}
+
+// Код интервала 1 (30–59 с) принимается в соседних интервалах и не дальше
+func TestValidateTOTPWindow(t *testing.T) {
+	const code = "287082" // интервал 1
+	tests := []struct {
+		unix int64
+		ok   bool
+	}{
+		{0, true},   // интервал 0 — на шаг раньше
+		{29, true},  // последняя секунда интервала 0
+		{30, true},  // первая секунда интервала 1
+		{59, true},  // последняя секунда интервала 1
+		{60, true},  // интервал 2 — на шаг позже
+		{89, true},  // последняя секунда интервала 2
+		{90, false}, // интервал 3
+		{120, false},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfcSecret, code, time.Unix(tt.unix, 0))
+		if ok != tt.ok {
+			t.Errorf("ValidateTOTP at %d: ok = %v, want %v", tt.unix, ok, tt.ok)
+		}
+		if ok && step != 1 {
+			t.Errorf("ValidateTOTP at %d: step = %d, want 1", tt.unix, step)
+		}
+	}
+
+	// Код интервала 2 в интервале 0 — на два шага вперёд
+	if _, ok := ValidateTOTP(rfcSecret, "359152", time.Unix(0, 0)); ok {
+		t.Error("code two steps ahead accepted at time 0")
+	}
+}
+
+func TestValidateTOTPInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+	}{
+		{name: "surrounding spaces", secret: rfcSecret, code: " 287082 ", ok: true},
+		{name: "lower-case secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: "287082", ok: true},
+		{name: "wrong code", secret: rfcSecret, code: "287083", ok: false},
+		{name: "8 digits", secret: rfcSecret, code: "94287082", ok: false},
+		{name: "5 digits", secret: rfcSecret, code: "87082", ok: false},
+		{name: "empty", secret: rfcSecret, code: "", ok: false},
+		{name: "invalid secret", secret: "not base32!", code: "287082", ok: false},
+		{name: "other secret", secret: "JBSWY3DPEHPK3PXP", code: "287082", ok: false},
+	}
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now); ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
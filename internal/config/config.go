@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +14,42 @@ type Config struct {
 	MongoURI string
 	Database string
 	HTTPPort string
+
+	// Прокси, которым доверяется X-Forwarded-For/X-Real-IP (IP или CIDR).
+	// Пусто — IP клиента берётся только из адреса соединения: иначе заголовок подделывается
+	// в обход списка IP API-ключей, блокировки входа по IP и журнала аудита
+	TrustedProxies []string
+
+	JWTSecret       string        // ключ подписи access-токенов (HS256)
+	AccessTokenTTL  time.Duration // время жизни access-токена
+	RefreshTokenTTL time.Duration // время жизни сессии (refresh-токена)
+	BcryptCost      int           // стоимость bcrypt для хэшей паролей
+
+	LoginMaxFailures   int           // неудач подряд по телефону до блокировки
+	LoginMaxFailuresIP int           // неудач подряд с одного IP до блокировки
+	LoginFailureWindow time.Duration // через сколько после последней неудачи счётчик сбрасывается
+	LoginLockBase      time.Duration // первая блокировка, далее удваивается
+	LoginLockMax       time.Duration // потолок блокировки
+
+	CodeTTL            time.Duration // срок действия одноразового кода
+	CodeMaxAttempts    int           // неверных вводов кода до его аннулирования
+	CodeResendCooldown time.Duration // пауза между отправками кода на один телефон
+	ResetTicketTTL     time.Duration // срок токена сброса пароля после проверки кода
+
+	TOTPIssuer            string        // название сервиса в приложении-аутентификаторе
+	TOTPEncryptionKey     string        // ключ шифрования TOTP-секретов; по умолчанию JWT_SECRET
+	TwoFactorChallengeTTL time.Duration // сколько действует токен второго шага входа
+
+	AuditRetention time.Duration // срок хранения записей журнала аудита
+
+	CoverStorage string // "gridfs" (по умолчанию) или "fs"
+	CoverDir     string // каталог обложек для COVER_STORAGE=fs
+	CoverMaxSize int    // предельный размер загружаемой обложки в байтах
+
+	Sender          string // "log" (по умолчанию) или "sms"
+	SenderLogFile   string // файл для LogSender; пусто — в лог приложения
+	SMSGatewayURL   string
+	SMSGatewayToken string
 }
 
 func LoadConfig() *Config {
@@ -23,11 +62,106 @@ func LoadConfig() *Config {
 		MongoURI: os.Getenv("MONGO_URI"),
 		Database: os.Getenv("MONGO_DB_NAME"),
 		HTTPPort: os.Getenv("HTTP_PORT"),
+
+		TrustedProxies: listFromEnv("TRUSTED_PROXIES"),
+
+		JWTSecret:       os.Getenv("JWT_SECRET"),
+		AccessTokenTTL:  durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		BcryptCost:      intFromEnv("BCRYPT_COST", 10),
+
+		LoginMaxFailures:   intFromEnv("LOGIN_MAX_FAILURES", 5),
+		LoginMaxFailuresIP: intFromEnv("LOGIN_MAX_FAILURES_IP", 20),
+		LoginFailureWindow: durationFromEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LoginLockBase:      durationFromEnv("LOGIN_LOCK_BASE", 30*time.Second),
+		LoginLockMax:       durationFromEnv("LOGIN_LOCK_MAX", time.Hour),
+
+		CodeTTL:            durationFromEnv("CODE_TTL", 10*time.Minute),
+		CodeMaxAttempts:    intFromEnv("CODE_MAX_ATTEMPTS", 5),
+		CodeResendCooldown: durationFromEnv("CODE_RESEND_COOLDOWN", time.Minute),
+		ResetTicketTTL:     durationFromEnv("RESET_TICKET_TTL", 15*time.Minute),
+
+		TOTPIssuer:            os.Getenv("TOTP_ISSUER"),
+		TOTPEncryptionKey:     os.Getenv("TOTP_ENCRYPTION_KEY"),
+		TwoFactorChallengeTTL: durationFromEnv("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute),
+
+		AuditRetention: durationFromEnv("AUDIT_RETENTION", 365*24*time.Hour),
+
+		CoverStorage: os.Getenv("COVER_STORAGE"),
+		CoverDir:     os.Getenv("COVER_DIR"),
+		CoverMaxSize: intFromEnv("COVER_MAX_SIZE", 5<<20),
+
+		Sender:          os.Getenv("SENDER"),
+		SenderLogFile:   os.Getenv("SENDER_LOG_FILE"),
+		SMSGatewayURL:   os.Getenv("SMS_GATEWAY_URL"),
+		SMSGatewayToken: os.Getenv("SMS_GATEWAY_TOKEN"),
 	}
 
 	if cfg.MongoURI == "" || cfg.Database == "" || cfg.HTTPPort == "" {
 		log.Fatal("Missing Mongo configuration in environment")
 	}
+	if cfg.JWTSecret == "" {
+		log.Fatal("Missing JWT_SECRET in environment")
+	}
+	if cfg.TOTPIssuer == "" {
+		cfg.TOTPIssuer = "Library"
+	}
+	if cfg.TOTPEncryptionKey == "" {
+		cfg.TOTPEncryptionKey = cfg.JWTSecret
+	}
+	if cfg.CoverStorage == "" {
+		cfg.CoverStorage = "gridfs"
+	}
+	if cfg.CoverStorage != "gridfs" && cfg.CoverStorage != "fs" {
+		log.Fatalf("Invalid COVER_STORAGE: %q", cfg.CoverStorage)
+	}
+	if cfg.CoverDir == "" {
+		cfg.CoverDir = "covers"
+	}
+	if cfg.Sender == "" {
+		cfg.Sender = "log"
+	}
+	if cfg.Sender == "sms" && cfg.SMSGatewayURL == "" {
+		log.Fatal("Missing SMS_GATEWAY_URL for SENDER=sms")
+	}
 
 	return cfg
 }
+
+// durationFromEnv читает длительность в формате time.ParseDuration ("15m", "1h"),
+// при отсутствии переменной возвращает значение по умолчанию
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s: %q", key, raw)
+	}
+	return d
+}
+
+// listFromEnv читает список через запятую; при отсутствии переменной возвращает nil
+func listFromEnv(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// intFromEnv читает положительное целое, при отсутствии переменной возвращает значение по умолчанию
+func intFromEnv(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Fatalf("Invalid %s: %q", key, raw)
+	}
+	return n
+}
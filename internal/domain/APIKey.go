@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+)
+
+// APIKey — ключ доступа для киосков и интеграций без входа пользователя
+type APIKey struct {
+	ID         string     `bson:"_id,omitempty" json:"id,omitempty"`                // строковый ID
+	Name       string     `bson:"name" json:"name"`                                 // назначение, например "Киоск, 2 этаж"
+	Prefix     string     `bson:"prefix" json:"prefix"`                             // видимая часть ключа lib_xxxxxxxx
+	Hash       string     `bson:"hash" json:"-"`                                    // SHA-256 полного ключа
+	Scopes     []string   `bson:"scopes" json:"scopes"`                             // catalog:read, circulation:write, ...
+	AllowedIPs []string   `bson:"allowedIps,omitempty" json:"allowedIps,omitempty"` // IP или CIDR; пусто — без ограничения
+	ExpiresAt  *time.Time `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`   // null — бессрочный
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	CreatedBy  string     `bson:"createdBy" json:"createdBy"`                       // ID администратора
+	RotatedAt  *time.Time `bson:"rotatedAt,omitempty" json:"rotatedAt,omitempty"`   // последняя смена секрета
+	LastUsedAt *time.Time `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"` // последний запрос (с точностью до минуты)
+	LastUsedIP string     `bson:"lastUsedIp,omitempty" json:"lastUsedIp,omitempty"` // адрес последнего запроса
+	RevokedAt  *time.Time `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`   // null, пока ключ действует
+}
+
+// Active — ключ не отозван и не истёк
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+)
+
+// AuditEntry — запись журнала аудита об изменении данных
+type AuditEntry struct {
+	ID         string                 `bson:"_id,omitempty" json:"id,omitempty"`          // строковый ID
+	Actor      AuditActor             `bson:"actor" json:"actor"`                         // кто выполнил действие
+	Action     string                 `bson:"action" json:"action"`                       // например book.delete, user.unblock
+	EntityType string                 `bson:"entityType" json:"entityType"`               // book, item, user, borrow
+	EntityID   string                 `bson:"entityId" json:"entityId"`                   // ID изменённой сущности
+	Changes    map[string]AuditChange `bson:"changes,omitempty" json:"changes,omitempty"` // изменённые поля: было/стало
+	At         time.Time              `bson:"at" json:"at"`                               // время действия
+	IP         string                 `bson:"ip,omitempty" json:"ip,omitempty"`           // IP клиента
+	UserAgent  string                 `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	RequestID  string                 `bson:"requestId,omitempty" json:"requestId,omitempty"` // X-Request-ID запроса
+	ExpireAt   time.Time              `bson:"expireAt" json:"-"`                              // TTL-индекс: срок хранения из AUDIT_RETENTION
+}
+
+// AuditActor — пользователь или API-ключ; пустой для анонимных действий (самостоятельная регистрация)
+type AuditActor struct {
+	UserID   string `bson:"userId,omitempty" json:"userId,omitempty"`
+	Role     string `bson:"role,omitempty" json:"role,omitempty"`
+	APIKeyID string `bson:"apiKeyId,omitempty" json:"apiKeyId,omitempty"`
+}
+
+// AuditChange — значение поля до и после изменения (null — поля не было)
+type AuditChange struct {
+	From any `bson:"from" json:"from"`
+	To   any `bson:"to" json:"to"`
+}
+
+type AuditFilter struct {
+	ActorID    string // userId или apiKeyId
+	EntityType string
+	EntityID   string
+	Action     string
+	From       time.Time // нижняя граница (включительно), нулевое значение — без ограничения
+	To         time.Time // верхняя граница (не включительно)
+	Limit      int64     // по умолчанию 100
+}
+
+// Типы сущностей журнала аудита
+const (
+	AuditEntityBook   = "book"
+	AuditEntityUser   = "user"
+	AuditEntityBorrow = "borrow"
+	AuditEntityItem   = "item"
+	AuditEntityAuthor = "author"
+	AuditEntityGenre  = "genre"
+	AuditEntityWork   = "work"
+	AuditEntitySeries = "series"
+)
+
+// Действия журнала аудита
+const (
+	AuditBookCreate   = "book.create"
+	AuditBookUpdate   = "book.update"
+	AuditBookDelete   = "book.delete" // в старых записях: книги удалялись до появления списания
+	AuditBookWithdraw = "book.withdraw"
+	AuditBookRestore  = "book.restore"
+	AuditBookRevert   = "book.revert"
+	AuditBookCover    = "book.cover"
+	AuditBookMerge    = "book.merge"
+	AuditUserRegister = "user.register"
+	AuditUserUpdate   = "user.update"
+	AuditUserBlock    = "user.block"
+	AuditUserUnblock  = "user.unblock"
+	AuditUserDelete   = "user.delete"
+	AuditItemCreate   = "item.create"
+	AuditItemUpdate   = "item.update"
+	AuditItemDelete   = "item.delete"
+	AuditBorrowCreate = "borrow.create"
+	AuditBorrowReturn = "borrow.return"
+	AuditAuthorCreate = "author.create"
+	AuditAuthorUpdate = "author.update"
+	AuditAuthorDelete = "author.delete"
+	AuditAuthorMerge  = "author.merge"
+	AuditGenreCreate  = "genre.create"
+	AuditGenreUpdate  = "genre.update"
+	AuditGenreDelete  = "genre.delete"
+	AuditGenreMerge   = "genre.merge"
+	AuditWorkCreate   = "work.create"
+	AuditWorkUpdate   = "work.update"
+	AuditWorkDelete   = "work.delete"
+	AuditSeriesCreate = "series.create"
+	AuditSeriesUpdate = "series.update"
+	AuditSeriesDelete = "series.delete"
+)
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Author — авторитетная запись о персоне: одно каноническое имя на все написания
+type Author struct {
+	ID         string    `bson:"_id,omitempty" json:"id,omitempty"`                // строковый ID
+	Name       string    `bson:"name" json:"name"`                                 // каноническое имя: "Толстой, Лев Николаевич"
+	Variants   []string  `bson:"variants,omitempty" json:"variants,omitempty"`     // другие написания: "Лев Толстой", "L. Tolstoy"
+	Keys       []string  `bson:"keys" json:"-"`                                    // NameKey имени и вариантов — для поиска по любому написанию
+	BirthYear  int       `bson:"birthYear,omitempty" json:"birthYear,omitempty"`   // год рождения
+	DeathYear  int       `bson:"deathYear,omitempty" json:"deathYear,omitempty"`   // год смерти
+	MergedInto string    `bson:"mergedInto,omitempty" json:"mergedInto,omitempty"` // ID записи, в которую влита эта (дубликат)
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Contributor — участник создания книги в определённой роли
+type Contributor struct {
+	AuthorID primitive.ObjectID `bson:"authorId" json:"authorId"` // ObjectID записи в authors
+	Name     string             `bson:"name" json:"name"`         // каноническое имя автора (копия для поиска и вывода)
+	Role     string             `bson:"role" json:"role"`         // author, translator, illustrator, editor
+}
+
+// Роли участников
+const (
+	RoleAuthor      = "author"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+	RoleEditor      = "editor"
+)
+
+func IsValidContributorRole(role string) bool {
+	switch role {
+	case RoleAuthor, RoleTranslator, RoleIllustrator, RoleEditor:
+		return true
+	}
+	return false
+}
+
+// NameKey — ключ сравнения написаний: нижний регистр, без пунктуации, слова через пробел.
+// "Толстой Л.Н." и "толстой л н" дают один ключ
+func NameKey(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// AuthorKeys — ключи имени и всех вариантов без повторов
+func AuthorKeys(a Author) []string {
+	seen := map[string]bool{}
+	keys := make([]string, 0, len(a.Variants)+1)
+	for _, n := range append([]string{a.Name}, a.Variants...) {
+		if k := NameKey(n); k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// PrimaryAuthors — имена участников в роли автора через запятую (значение Book.Author)
+func PrimaryAuthors(contributors []Contributor) string {
+	var names []string
+	for _, c := range contributors {
+		if c.Role == RoleAuthor {
+			names = append(names, c.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
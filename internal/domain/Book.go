@@ -1,15 +1,86 @@
 package domain
 
+import "time"
+
 type Book struct {
-	ID     string `bson:"_id,omitempty" json:"id,omitempty"` // строковый ID
-	Title  string `bson:"title" json:"title"`                // название книги
-	Author string `bson:"author" json:"author"`              // автор
-	Year   int    `bson:"year" json:"year"`                  // год издания
-	Genre  string `bson:"genre" json:"genre"`                // жанр
+	ID     string `bson:"_id,omitempty" json:"id,omitempty"`        // строковый ID
+	Title  string `bson:"title" json:"title"`                       // название книги
+	Author string `bson:"author" json:"author"`                     // автор(ы); при заданных contributors — имена в роли author
+	Year   int    `bson:"year" json:"year"`                         // год издания
+	Genre  string `bson:"genre" json:"genre"`                       // жанр; при заданном genreId — русское название узла рубрикатора
+	ISBN13 string `bson:"isbn13,omitempty" json:"isbn13,omitempty"` // ISBN-13 без дефисов, уникален
+	ISBN10 string `bson:"isbn10,omitempty" json:"isbn10,omitempty"` // ISBN-10, если у книги он есть (префикс 978)
+
+	GenreID string       `bson:"genreId,omitempty" json:"genreId,omitempty"` // ID жанра в рубрикаторе; пусто — жанр только строкой
+	WorkID  string       `bson:"workId,omitempty" json:"workId,omitempty"`   // ID произведения, изданием которого является книга
+	Series  *SeriesEntry `bson:"series,omitempty" json:"series,omitempty"`   // серия и номер тома; nil — вне серии
+
+	Contributors []Contributor `bson:"contributors,omitempty" json:"contributors,omitempty"` // авторы, переводчики, иллюстраторы
+
+	Cover *Cover `bson:"cover,omitempty" json:"cover,omitempty"` // обложка; nil — не загружена
+
+	Withdrawn  *Withdrawal `bson:"withdrawn,omitempty" json:"withdrawn,omitempty"`   // списана; nil — в каталоге
+	MergedInto string      `bson:"mergedInto,omitempty" json:"mergedInto,omitempty"` // ID книги, в которую влита эта (дубликат)
+
+	Keys BookKeys `bson:"keys" json:"-"` // ключи поиска; пишет BookRepoMongo при каждом сохранении
+
+	Score    float64 `bson:"score,omitempty" json:"-"`    // релевантность в полнотекстовом поиске, не хранится
+	Editions int     `bson:"editions,omitempty" json:"-"` // найдено изданий произведения при GroupByWork, не хранится
+}
+
+// BookKeys — слова названия и автора в виде textsim.Latin: по ним находятся "tolstoy", "Толстои" и "ежик"
+type BookKeys struct {
+	Title  []string `bson:"title"`
+	Author []string `bson:"author"`
+}
+
+// Withdrawal — списание книги из каталога. Документ книги остаётся, чтобы история выдач
+// и отчёты по-прежнему показывали название; списание можно отменить
+type Withdrawal struct {
+	Reason string    `bson:"reason,omitempty" json:"reason,omitempty"` // причина списания
+	At     time.Time `bson:"at" json:"at"`                             // дата списания
 }
 
 type BookFilter struct {
-	Title  string   `json:"title"`  // фильтр по названию (нечувствительный к регистру)
-	Author string   `json:"author"` // фильтр по автору
-	Genres []string `json:"genres"` // один или несколько жанров
+	Query    string   `json:"q"`        // полнотекстовый запрос: "фраза в кавычках", -исключение
+	Title    string   `json:"title"`    // фильтр по названию (нечувствительный к регистру)
+	Author   string   `json:"author"`   // фильтр по автору
+	AuthorID string   `json:"authorId"` // книги, в которых участвует автор (в любой роли)
+	WorkID   string   `json:"workId"`   // издания произведения
+	SeriesID string   `json:"seriesId"` // книги серии
+	Genres   []string `json:"genres"`   // жанры: ID или название/синоним из рубрикатора, вместе с поджанрами
+	ISBN     string   `json:"isbn"`     // ISBN целиком или его часть, с дефисами или без
+	Decade   int      `json:"decade"`   // десятилетие издания (1990 — годы 1990-1999); 0 — любое
+
+	AvailableOnly bool   `json:"availableOnly"` // только книги со свободными экземплярами
+	QueryByKeys   bool   `json:"-"`             // искать слова Query по ключам названия и автора, а не полнотекстовым индексом
+	Withdrawn     string `json:"withdrawn"`     // списанные книги: "" — скрыть, include — показать, only — только они
+	GroupByWork   bool   `json:"groupByWork"`   // SearchPage: одно издание на произведение (первое в порядке сортировки)
 }
+
+// Значения BookFilter.Withdrawn
+const (
+	WithdrawnExclude = ""
+	WithdrawnInclude = "include"
+	WithdrawnOnly    = "only"
+)
+
+// FacetBucket — значение фасета и число книг с ним
+type FacetBucket struct {
+	Value string `bson:"_id" json:"value"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// BookFacets — распределение найденных книг по жанру, автору, десятилетию и доступности
+type BookFacets struct {
+	Genre        []FacetBucket `bson:"genre" json:"genre"`
+	Author       []FacetBucket `bson:"author" json:"author"`
+	Decade       []FacetBucket `bson:"decade" json:"decade"`             // "1990", "2000", ...
+	Availability []FacetBucket `bson:"availability" json:"availability"` // "available" / "unavailable"
+}
+
+// Значения фасета доступности
+const (
+	FacetAvailable   = "available"
+	FacetUnavailable = "unavailable"
+)
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// BookRevision — неизменяемый снимок библиографической записи после очередного изменения
+type BookRevision struct {
+	ID       string             `bson:"_id,omitempty" json:"id,omitempty"`            // строковый ID
+	BookID   primitive.ObjectID `bson:"bookId" json:"bookId"`                         // ObjectID книги
+	Number   int                `bson:"number" json:"number"`                         // 1, 2, ... в пределах книги
+	Action   string             `bson:"action" json:"action"`                         // create, update, revert, baseline
+	RevertOf int                `bson:"revertOf,omitempty" json:"revertOf,omitempty"` // для revert — номер восстановленной ревизии
+	Record   BookRecord         `bson:"record" json:"record"`                         // состояние записи после изменения
+	Actor    AuditActor         `bson:"actor" json:"actor"`                           // кто изменил; пусто для baseline
+	At       time.Time          `bson:"at" json:"at"`
+}
+
+// BookRecord — поля книги, которые хранятся в ревизиях и восстанавливаются откатом.
+// Экземпляры, обложка и списание сюда не входят: у них свой учёт
+type BookRecord struct {
+	Title   string `bson:"title" json:"title"`
+	Author  string `bson:"author" json:"author"`
+	Year    int    `bson:"year" json:"year"`
+	Genre   string `bson:"genre" json:"genre"`
+	GenreID string `bson:"genreId,omitempty" json:"genreId,omitempty"` // жанр рубрикатора: по названию откат мог бы попасть в другой жанр
+	ISBN13  string `bson:"isbn13,omitempty" json:"isbn13,omitempty"`
+	ISBN10  string `bson:"isbn10,omitempty" json:"isbn10,omitempty"`
+
+	Contributors []Contributor `bson:"contributors,omitempty" json:"contributors,omitempty"`
+
+	WorkID string       `bson:"workId,omitempty" json:"workId,omitempty"`
+	Series *SeriesEntry `bson:"series,omitempty" json:"series,omitempty"`
+}
+
+// Действия ревизий
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionRevert   = "revert"
+	RevisionBaseline = "baseline" // снимок книги, заведённой до появления ревизий (миграция)
+)
+
+// Record — версионируемые поля книги
+func (b Book) Record() BookRecord {
+	return BookRecord{
+		Title:        b.Title,
+		Author:       b.Author,
+		Year:         b.Year,
+		Genre:        b.Genre,
+		GenreID:      b.GenreID,
+		ISBN13:       b.ISBN13,
+		ISBN10:       b.ISBN10,
+		Contributors: b.Contributors,
+		WorkID:       b.WorkID,
+		Series:       b.Series,
+	}
+}
@@ -9,6 +9,7 @@ type Borrow struct {
 	ID         string             `bson:"_id,omitempty" json:"id,omitempty"`                // строковый ID
 	ClientID   primitive.ObjectID `bson:"clientId" json:"clientId"`                         // ObjectID читателя
 	BookID     primitive.ObjectID `bson:"bookId" json:"bookId"`                             // ObjectID книги
+	ItemID     primitive.ObjectID `bson:"itemId" json:"itemId"`                             // ObjectID выданного экземпляра
 	BorrowedAt time.Time          `bson:"borrowedAt" json:"borrowedAt"`                     // Дата выдачи
 	ReturnedAt *time.Time         `bson:"returnedAt,omitempty" json:"returnedAt,omitempty"` // null, если ещё не вернули
 }
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Cover — обложка книги. Сами файлы (оригинал и миниатюры) лежат в хранилище обложек,
+// в книге — только их описание
+type Cover struct {
+	Hash        string    `bson:"hash" json:"hash"`               // SHA-256 оригинала (hex), основа ETag
+	ContentType string    `bson:"contentType" json:"contentType"` // тип оригинала: image/jpeg, image/png, ...
+	Size        int64     `bson:"size" json:"size"`               // размер оригинала в байтах
+	Width       int       `bson:"width" json:"width"`
+	Height      int       `bson:"height" json:"height"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Варианты файла обложки
+const (
+	CoverOriginal = "original"
+	CoverSmall    = "small"
+	CoverMedium   = "medium"
+)
+
+// CoverThumbnails — сторона квадрата (px), в который вписывается миниатюра; миниатюры хранятся в JPEG
+var CoverThumbnails = map[string]int{
+	CoverSmall:  160,
+	CoverMedium: 480,
+}
+
+// Version — короткая версия обложки для адресов и ETag
+func (c Cover) Version() string {
+	if len(c.Hash) > 16 {
+		return c.Hash[:16]
+	}
+	return c.Hash
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"library-Mongo/internal/textsim"
+	"time"
+)
+
+// Genre — узел рубрикатора жанров. Книга ссылается на узел, а поиск по жанру
+// захватывает и все его поджанры
+type Genre struct {
+	ID        string    `bson:"_id,omitempty" json:"id,omitempty"`            // строковый ID
+	ParentID  string    `bson:"parentId,omitempty" json:"parentId,omitempty"` // ID родительского жанра; пусто — корень
+	Ancestors []string  `bson:"ancestors" json:"ancestors"`                   // ID предков от корня до родителя
+	LabelRU   string    `bson:"labelRu" json:"labelRu"`                       // название по-русски: "Научная фантастика"
+	LabelEN   string    `bson:"labelEn,omitempty" json:"labelEn,omitempty"`   // название по-английски: "Science fiction"
+	Synonyms  []string  `bson:"synonyms,omitempty" json:"synonyms,omitempty"` // другие написания: "sci-fi", "НФ"
+	Keys      []string  `bson:"keys" json:"-"`                                // textsim.Key названий и синонимов, уникальны в рубрикаторе
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// GenreKeys — ключи обоих названий и всех синонимов без повторов
+func GenreKeys(g Genre) []string {
+	seen := map[string]bool{}
+	keys := make([]string, 0, len(g.Synonyms)+2)
+	for _, label := range append([]string{g.LabelRU, g.LabelEN}, g.Synonyms...) {
+		if k := textsim.Key(label); k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Path — ID предков и самого жанра: Ancestors его поджанров
+func (g Genre) Path() []string {
+	return append(append([]string{}, g.Ancestors...), g.ID)
+}
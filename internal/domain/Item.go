@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Item — физический экземпляр книги (Book — библиографическая запись)
+type Item struct {
+	ID         string             `bson:"_id,omitempty" json:"id,omitempty"` // строковый ID
+	BookID     primitive.ObjectID `bson:"bookId" json:"bookId"`              // ObjectID книги
+	Barcode    string             `bson:"barcode" json:"barcode"`            // штрихкод, уникален
+	Status     string             `bson:"status" json:"status"`              // available, on_loan, in_repair, lost
+	Location   string             `bson:"location" json:"location"`          // зал, стеллаж, полка
+	AcquiredAt time.Time          `bson:"acquiredAt" json:"acquiredAt"`      // дата поступления
+}
+
+// Статусы экземпляра
+const (
+	ItemAvailable = "available"
+	ItemOnLoan    = "on_loan"
+	ItemInRepair  = "in_repair"
+	ItemLost      = "lost"
+)
+
+// IsValidItemStatus — статус, который можно задать вручную (on_loan ставит только выдача)
+func IsValidItemStatus(status string) bool {
+	switch status {
+	case ItemAvailable, ItemInRepair, ItemLost:
+		return true
+	}
+	return false
+}
+
+// DefaultBarcode — штрихкод экземпляра, заведённого без сканера (при создании книги и миграции)
+func DefaultBarcode(bookID string, n int) string {
+	return fmt.Sprintf("%s-%02d", bookID, n)
+}
+
+// Availability — доступность экземпляров одной книги
+type Availability struct {
+	BookID    string `bson:"_id" json:"-"`
+	Total     int    `bson:"total" json:"total"`         // все экземпляры, кроме утерянных
+	Available int    `bson:"available" json:"available"` // можно выдать сейчас
+}
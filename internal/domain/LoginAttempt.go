@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// LoginAttempt — счётчик неудачных входов по телефону или IP
+type LoginAttempt struct {
+	Key         string     `bson:"_id" json:"key"`                                     // "phone:<номер>" или "ip:<адрес>"
+	Failures    int        `bson:"failures" json:"failures"`                           // неудачных попыток подряд
+	LastFailure time.Time  `bson:"lastFailure" json:"lastFailure"`                     // время последней неудачи
+	LockedUntil *time.Time `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"` // временная блокировка входа
+	ExpireAt    time.Time  `bson:"expireAt" json:"-"`                                  // TTL-индекс: счётчик забывается
+}
+
+// LockoutEvent — запись о временной блокировке входа (для администраторов)
+type LockoutEvent struct {
+	ID          string    `bson:"_id,omitempty" json:"id,omitempty"`
+	Kind        string    `bson:"kind" json:"kind"`         // "phone" или "ip"
+	Value       string    `bson:"value" json:"value"`       // номер телефона или IP
+	Failures    int       `bson:"failures" json:"failures"` // число неудач, вызвавших блокировку
+	IP          string    `bson:"ip" json:"ip"`             // IP последней попытки
+	LockedUntil time.Time `bson:"lockedUntil" json:"lockedUntil"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+type LockoutEventFilter struct {
+	Since time.Time `json:"since"` // события не раньше этого времени
+	Value string    `json:"value"` // точный телефон или IP
+	Limit int64     `json:"limit"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+// Размер страницы списков по умолчанию и его потолок
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageRequest — параметры постраничной выдачи списка
+type PageRequest struct {
+	Limit int    // размер страницы, 1..MaxPageLimit
+	After string // курсор из предыдущей страницы; пусто — с начала
+	Sort  string // поле сортировки, "-" в начале — по убыванию; пусто — порядок списка по умолчанию
+}
+
+// Page — одна страница списка
+type Page[T any] struct {
+	Items []T
+	Total int64  // всего записей по фильтру
+	Next  string // курсор следующей страницы; пусто — страница последняя
+}
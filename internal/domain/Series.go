@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Series — многотомное издание или цикл; книги входят в него с номером тома
+type Series struct {
+	ID        string    `bson:"_id,omitempty" json:"id,omitempty"` // строковый ID
+	Title     string    `bson:"title" json:"title"`                // название серии: "Хроники Нарнии"
+	Keys      []string  `bson:"keys" json:"-"`                     // textsim.SearchKeys названия — для поиска
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// SeriesEntry — место книги в серии
+type SeriesEntry struct {
+	SeriesID string `bson:"seriesId" json:"seriesId"`                 // ID серии
+	Title    string `bson:"title" json:"title"`                       // название серии (копия для вывода)
+	Volume   int    `bson:"volume,omitempty" json:"volume,omitempty"` // номер тома; 0 — без номера
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Session struct {
+	ID           string             `bson:"_id,omitempty" json:"id,omitempty"`                    // строковый ID
+	UserID       primitive.ObjectID `bson:"userId" json:"userId"`                                 // ObjectID пользователя
+	RefreshHash  string             `bson:"refreshHash" json:"-"`                                 // SHA-256 текущего refresh-токена
+	UsedHashes   []string           `bson:"usedHashes" json:"-"`                                  // хэши уже обменянных refresh-токенов (обнаружение повторного использования)
+	UserAgent    string             `bson:"userAgent" json:"userAgent"`                           // устройство/клиент
+	IP           string             `bson:"ip" json:"ip"`                                         // IP при входе
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`                           // время входа
+	LastUsedAt   time.Time          `bson:"lastUsedAt" json:"lastUsedAt"`                         // последнее обновление токенов
+	ExpiresAt    time.Time          `bson:"expiresAt" json:"expiresAt"`                           // срок действия refresh-токена (TTL-индекс)
+	RevokedAt    *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`       // null, пока сессия активна
+	RevokeReason string             `bson:"revokeReason,omitempty" json:"revokeReason,omitempty"` // logout, blocked, password_changed, refresh_reuse, 2fa_reset
+}
+
+// Active — сессия не отозвана и не истекла
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Причины отзыва сессии
+const (
+	RevokeLogout          = "logout"
+	RevokeBlocked         = "blocked"
+	RevokePasswordChanged = "password_changed"
+	RevokeRefreshReuse    = "refresh_reuse"
+	RevokeTwoFactorReset  = "2fa_reset"
+	RevokeRoleChanged     = "role_changed"
+)
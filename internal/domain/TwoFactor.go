@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// TwoFactor — TOTP-аутентификатор пользователя (одна запись на пользователя)
+type TwoFactor struct {
+	UserID        primitive.ObjectID `bson:"_id" json:"userId"`                              // ObjectID пользователя
+	Secret        string             `bson:"secret" json:"-"`                                // TOTP-секрет, зашифрованный auth.SecretBox
+	Enabled       bool               `bson:"enabled" json:"enabled"`                         // false, пока привязка не подтверждена кодом
+	RecoveryCodes []string           `bson:"recoveryCodes" json:"-"`                         // SHA-256 неиспользованных кодов восстановления
+	LastStep      int64              `bson:"lastStep" json:"-"`                              // интервал последнего принятого кода (защита от повтора)
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`                     // начало привязки
+	EnabledAt     *time.Time         `bson:"enabledAt,omitempty" json:"enabledAt,omitempty"` // подтверждение привязки
+}
+
+// SecuritySettings — настройки безопасности, которые администратор меняет без перезапуска
+type SecuritySettings struct {
+	TwoFactorRoles []string  `bson:"twoFactorRoles" json:"twoFactorRoles"` // роли, для которых 2FA обязательна
+	UpdatedAt      time.Time `bson:"updatedAt" json:"updatedAt"`
+	UpdatedBy      string    `bson:"updatedBy" json:"updatedBy"` // ID администратора
+}
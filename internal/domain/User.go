@@ -3,11 +3,15 @@ package domain
 type User struct {
 	ID           string `bson:"_id,omitempty"     json:"id,omitempty"` // строковый ID
 	FullName     string `bson:"fullName"          json:"fullName"`     // ФИО
-	Password     string `bson:"password"          json:"password"`     // пароль (пока не хэшируется)
+	Password     string `bson:"password"          json:"-"`            // bcrypt-хэш пароля, в JSON не попадает
 	Role         string `bson:"role"              json:"role"`         // "admin", "librarian", "reader"
 	Phone        string `bson:"phone"             json:"phone"`        // телефон
 	RegisteredAt string `bson:"registeredAt"      json:"registeredAt"` // дата регистрации (ISO string)
 	IsActive     bool   `bson:"isActive"          json:"isActive"`     // активен или заблокирован
+
+	PendingVerification bool `bson:"pendingVerification,omitempty" json:"pendingVerification,omitempty"` // телефон ещё не подтверждён кодом
+
+	NameKeys []string `bson:"nameKeys,omitempty" json:"-"` // слова ФИО в виде textsim.Latin, пишет UserRepoMongo
 }
 
 type UserFilter struct {
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Назначение одноразового кода
+const (
+	PurposePasswordReset     = "password_reset"
+	PurposePhoneVerification = "phone_verification"
+)
+
+// VerificationCode — одноразовый код, отправленный на телефон (хранится только хэш)
+type VerificationCode struct {
+	ID              string     `bson:"_id,omitempty" json:"id,omitempty"`
+	Phone           string     `bson:"phone" json:"phone"`                                         // получатель
+	Purpose         string     `bson:"purpose" json:"purpose"`                                     // password_reset / phone_verification
+	CodeHash        string     `bson:"codeHash" json:"-"`                                          // bcrypt-хэш кода
+	Attempts        int        `bson:"attempts" json:"attempts"`                                   // неверных вводов
+	CreatedAt       time.Time  `bson:"createdAt" json:"createdAt"`                                 // время отправки
+	ExpiresAt       time.Time  `bson:"expiresAt" json:"expiresAt"`                                 // код недействителен после
+	ConsumedAt      *time.Time `bson:"consumedAt,omitempty" json:"consumedAt,omitempty"`           // код использован или отменён
+	TicketHash      string     `bson:"ticketHash,omitempty" json:"-"`                              // SHA-256 токена сброса, выданного после проверки кода
+	TicketExpiresAt *time.Time `bson:"ticketExpiresAt,omitempty" json:"ticketExpiresAt,omitempty"` // срок токена сброса
+	ExpireAt        time.Time  `bson:"expireAt" json:"-"`                                          // TTL-индекс: удаление записи
+}
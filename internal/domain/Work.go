@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Work — произведение: объединяет издания и переводы одного текста, каждое из которых — отдельная Book
+type Work struct {
+	ID        string    `bson:"_id,omitempty" json:"id,omitempty"`    // строковый ID
+	Title     string    `bson:"title" json:"title"`                   // каноническое название: "Война и мир"
+	Author    string    `bson:"author,omitempty" json:"author"`       // автор произведения
+	Year      int       `bson:"year,omitempty" json:"year,omitempty"` // год первой публикации
+	Keys      []string  `bson:"keys" json:"-"`                        // textsim.SearchKeys названия — для поиска
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// WorkBorrowStat — выдачи одного издания произведения
+type WorkBorrowStat struct {
+	BookID  string   `bson:"_id"`
+	Borrows int64    `bson:"borrows"`
+	Active  int64    `bson:"active"`  // не возвращены
+	Readers []string `bson:"readers"` // ID читателей без повторов
+}
@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrUserNotFound        = errors.New("user not found")
@@ -10,4 +13,79 @@ var (
 	ErrBookAlreadyBorrowed = errors.New("book is already borrowed")
 	ErrBorrowNotFound      = errors.New("borrow not found")
 	ErrAlreadyReturned     = errors.New("book already returned")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrLoginLocked         = errors.New("too many failed login attempts, try again later")
+	ErrPhoneNotVerified    = errors.New("phone is not verified")
+	ErrPhoneTaken          = errors.New("phone is already registered")
+	ErrCodeInvalid         = errors.New("invalid or expired code")
+	ErrCodeAttempts        = errors.New("too many wrong codes, request a new one")
+	ErrCodeCooldown        = errors.New("code was sent recently, try again later")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+	ErrForbidden           = errors.New("access denied")
+	ErrWeakPassword        = errors.New("password must be at least 8 characters and contain letters and digits")
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrNotOwner            = errors.New("access to another user's resource denied")
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not set up")
+	ErrTwoFactorRequired   = errors.New("two-factor authentication is required for this role")
+	ErrInvalidAPIKey       = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyIPDenied      = errors.New("API key is not allowed from this address")
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrInvalidScope        = errors.New("invalid API key scope")
+	ErrItemNotFound        = errors.New("item not found")
+	ErrNoAvailableCopies   = errors.New("no available copies")
+	ErrItemOnLoan          = errors.New("item is on loan")
+	ErrItemUnavailable     = errors.New("item is not available for loan")
+	ErrInvalidItemStatus   = errors.New("invalid item status")
+	ErrBarcodeTaken        = errors.New("barcode is already in use")
+	ErrBarcodeRequired     = errors.New("barcode is required")
+	ErrInvalidISBN         = errors.New("invalid ISBN")
+	ErrISBNTaken           = errors.New("ISBN is already used by another book")
+	ErrInvalidCursor       = errors.New("invalid page cursor")
+	ErrInvalidSort         = errors.New("unsupported sort field")
+	ErrAuthorNotFound      = errors.New("author not found")
+	ErrAuthorInUse         = errors.New("author is referenced by books")
+	ErrGenreNotFound       = errors.New("genre not found")
+	ErrGenreLabelRequired  = errors.New("genre label (labelRu) is required")
+	ErrGenreInUse          = errors.New("genre is referenced by books")
+	ErrGenreHasChildren    = errors.New("genre has subgenres")
+	ErrGenreCycle          = errors.New("genre cannot be placed under itself or its subgenre")
+	ErrGenreLabelTaken     = errors.New("genre label or synonym is already used by another genre")
+	ErrWorkNotFound        = errors.New("work not found")
+	ErrWorkTitleRequired   = errors.New("work title is required")
+	ErrWorkInUse           = errors.New("work has editions")
+	ErrSeriesNotFound      = errors.New("series not found")
+	ErrSeriesTitleRequired = errors.New("series title is required")
+	ErrSeriesInUse         = errors.New("series has books")
+	ErrInvalidVolume       = errors.New("volume must not be negative")
+	ErrInvalidContributor  = errors.New("invalid contributor role")
+	ErrMergeSelf           = errors.New("cannot merge a record into itself")
+	ErrInvalidMARC         = errors.New("malformed MARC data")
+	ErrInvalidSpreadsheet  = errors.New("unreadable spreadsheet")
+	ErrColumnMapping       = errors.New("invalid column mapping")
+	ErrBookHasLoans        = errors.New("book has active loans")
+	ErrBookWithdrawn       = errors.New("book is withdrawn")
+	ErrBookNotWithdrawn    = errors.New("book is not withdrawn")
+	ErrRevisionNotFound    = errors.New("revision not found")
+	ErrCoverNotFound       = errors.New("cover not found")
+	ErrCoverTooLarge       = errors.New("cover image is too large")
+	ErrUnsupportedImage    = errors.New("unsupported image format, use JPEG, PNG, GIF or WebP")
+	ErrInvalidCoverSize    = errors.New("invalid cover size")
 )
+
+// LockoutError — вход временно заблокирован после серии неудач
+// (errors.Is(err, ErrLoginLocked) == true). В отличие от ErrUserBlocked
+// снимается сам по истечении RetryAfter.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return ErrLoginLocked.Error()
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrLoginLocked
+}
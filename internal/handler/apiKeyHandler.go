@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type APIKeyHandler struct {
+	apiKeyUC usecase.APIKeyUC
+}
+
+func NewAPIKeyHandler(apiKeyUC usecase.APIKeyUC) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyUC: apiKeyUC}
+}
+
+// CreateAPIKey godoc
+// @Summary Выпустить API-ключ
+// @Description Полный ключ возвращается только в этом ответе; передаётся в заголовке "Authorization: ApiKey <key>" или "X-API-Key"
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateAPIKeyInput true "Название, области, разрешённые IP и срок действия"
+// @Success 201 {object} dto.APIKeyCreatedResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var input dto.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	key, err := h.apiKeyUC.CreateAPIKey(c.Request.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrInvalidScope):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid scope"})
+		default:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys godoc
+// @Summary Список API-ключей
+// @Tags api-keys
+// @Produce json
+// @Param includeRevoked query bool false "Включая отозванные"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyUC.ListAPIKeys(c.Request.Context(), c.Query("includeRevoked") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, keys)
+}
+
+// GetAPIKey godoc
+// @Summary Получить API-ключ по ID
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "ID ключа"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.APIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	key, err := h.apiKeyUC.GetAPIKey(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, key)
+}
+
+// RotateAPIKey godoc
+// @Summary Заменить секрет API-ключа
+// @Description Старый ключ перестаёт действовать сразу; новый возвращается только в этом ответе
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "ID ключа"
+// @Success 200 {object} dto.APIKeyCreatedResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	key, err := h.apiKeyUC.RotateAPIKey(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+// RevokeAPIKey godoc
+// @Summary Отозвать API-ключ
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "ID ключа"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.apiKeyUC.RevokeAPIKey(c.Request.Context(), c.Param("id")); err != nil {
+		apiKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "revoked"})
+}
+
+func apiKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "API key not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/domain"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strconv"
+)
+
+type AuditHandler struct {
+	auditUC usecase.AuditUC
+}
+
+func NewAuditHandler(auditUC usecase.AuditUC) *AuditHandler {
+	return &AuditHandler{auditUC: auditUC}
+}
+
+// ListAuditEntries godoc
+// @Summary Журнал аудита изменений
+// @Tags audit
+// @Produce json
+// @Param actor query string false "ID пользователя или API-ключа"
+// @Param entityType query string false "Тип сущности (book, user, borrow)"
+// @Param entityId query string false "ID сущности"
+// @Param action query string false "Действие, например book.delete"
+// @Param from query string false "Не раньше (YYYY-MM-DD или RFC3339)"
+// @Param to query string false "Раньше (YYYY-MM-DD или RFC3339)"
+// @Param limit query int false "Максимум записей (по умолчанию 100)"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} domain.AuditEntry
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /audit [get]
+func (h *AuditHandler) ListAuditEntries(c *gin.Context) {
+	filter := domain.AuditFilter{
+		ActorID:    c.Query("actor"),
+		EntityType: c.Query("entityType"),
+		EntityID:   c.Query("entityId"),
+		Action:     c.Query("action"),
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseDateTime(fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from"})
+			return
+		}
+		filter.From = from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseDateTime(toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to"})
+			return
+		}
+		filter.To = to
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.auditUC.ListAuditEntries(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, entries)
+}
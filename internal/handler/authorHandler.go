@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type AuthorHandler struct {
+	authorUC usecase.AuthorUC
+	bookUC   usecase.BookUC
+}
+
+func NewAuthorHandler(authorUC usecase.AuthorUC, bookUC usecase.BookUC) *AuthorHandler {
+	return &AuthorHandler{authorUC: authorUC, bookUC: bookUC}
+}
+
+// CreateAuthor godoc
+// @Summary Добавить автора
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateAuthorInput true "Каноническое имя, варианты написания, годы жизни"
+// @Success 201 {object} dto.AuthorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors [post]
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var input dto.CreateAuthorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	author, err := h.authorUC.CreateAuthor(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, author)
+}
+
+// SearchAuthors godoc
+// @Summary Поиск авторов
+// @Description Ищет по каноническому имени и всем вариантам написания
+// @Tags authors
+// @Produce json
+// @Param name query string false "Часть имени"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: name (по умолчанию), birthYear; \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.AuthorResponse
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors [get]
+func (h *AuthorHandler) SearchAuthors(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	authors, err := h.authorUC.SearchAuthors(c.Request.Context(), c.Query("name"), page)
+	if err != nil {
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	setPageHeaders(c, authors.Total, authors.Next)
+	respond(c, http.StatusOK, authors.Items)
+}
+
+// GetAuthor godoc
+// @Summary Получить автора
+// @Description По ID записи, влитой в другую, возвращается действующая запись
+// @Tags authors
+// @Produce json
+// @Param id path string true "ID автора"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.AuthorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors/{id} [get]
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	author, err := h.authorUC.GetAuthor(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		authorError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, author)
+}
+
+// ListAuthorBooks godoc
+// @Summary Книги автора
+// @Description Все книги, в которых автор участвует в любой роли
+// @Tags authors
+// @Produce json
+// @Param id path string true "ID автора"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: title (по умолчанию), year; \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.BookResponse
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors/{id}/books [get]
+func (h *AuthorHandler) ListAuthorBooks(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	author, err := h.authorUC.GetAuthor(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		authorError(c, err)
+		return
+	}
+	books, err := h.bookUC.SearchBooks(c.Request.Context(), domain.BookFilter{AuthorID: author.ID}, page, false)
+	if err != nil {
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	setPageHeaders(c, books.Total, books.Next)
+	respond(c, http.StatusOK, books.Items)
+}
+
+// UpdateAuthor godoc
+// @Summary Изменить автора
+// @Description Новое каноническое имя переносится во все книги автора, прежнее становится вариантом написания
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "ID автора"
+// @Param input body dto.UpdateAuthorInput true "Обновляемые поля"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	var input dto.UpdateAuthorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	input.ID = c.Param("id")
+
+	if err := h.authorUC.UpdateAuthor(c.Request.Context(), input); err != nil {
+		authorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "updated"})
+}
+
+// DeleteAuthor godoc
+// @Summary Удалить автора
+// @Description Удаляется только запись, на которую не ссылается ни одна книга
+// @Tags authors
+// @Produce json
+// @Param id path string true "ID автора"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	if err := h.authorUC.DeleteAuthor(c.Request.Context(), c.Param("id")); err != nil {
+		authorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "deleted"})
+}
+
+// MergeAuthors godoc
+// @Summary Объединить записи об одном авторе
+// @Description Дубликат вливается в запись из пути: его написания становятся вариантами, книги перепривязываются,
+// @Description а ID дубликата продолжает открывать объединённую запись
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "ID записи, которая остаётся"
+// @Param input body dto.MergeAuthorsInput true "ID дубликата"
+// @Success 200 {object} dto.MergeAuthorsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /authors/{id}/merge [post]
+func (h *AuthorHandler) MergeAuthors(c *gin.Context) {
+	var input dto.MergeAuthorsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	res, err := h.authorUC.MergeAuthors(c.Request.Context(), c.Param("id"), input.DuplicateID)
+	if err != nil {
+		authorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func authorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrMergeSelf):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "cannot merge an author into itself"})
+	case errors.Is(err, customErr.ErrAuthorNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "author not found"})
+	case errors.Is(err, customErr.ErrAuthorInUse):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "author is referenced by books"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
 	"library-Mongo/internal/usecase"
 	"library-Mongo/internal/usecase/dto"
 	"net/http"
+	"strconv"
 )
 
 type BookHandler struct {
@@ -22,9 +25,13 @@ func NewBookHandler(bookUC usecase.BookUC) *BookHandler {
 // @Accept json
 // @Produce json
 // @Param input body dto.CreateBookInput true "Данные книги"
-// @Success 200 {object} domain.Book
+// @Success 200 {object} dto.BookResponse
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books [post]
 func (h *BookHandler) CreateBook(c *gin.Context) {
 	var input dto.CreateBookInput
@@ -35,7 +42,7 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 
 	book, err := h.bookUC.CreateBook(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		bookWriteError(c, err)
 		return
 	}
 
@@ -50,7 +57,11 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 // @Param input body dto.UpdateBookInput true "Обновляемые поля"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books [put]
 func (h *BookHandler) UpdateBook(c *gin.Context) {
 	var input dto.UpdateBookInput
@@ -60,7 +71,7 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	}
 
 	if err := h.bookUC.UpdateBook(c.Request.Context(), input); err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		bookWriteError(c, err)
 		return
 	}
 
@@ -68,21 +79,49 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 }
 
 // DeleteBook godoc
-// @Summary Удалить книгу
+// @Summary Списать книгу
+// @Description Книга не удаляется: она скрывается из поиска, а история выдач и отчёты по-прежнему её показывают.
+// @Description Книгу, которая сейчас на руках у читателя, списать нельзя. Вернуть в каталог — POST /books/{id}/restore.
 // @Tags books
 // @Produce json
 // @Param id path string true "ID книги"
+// @Param reason query string false "Причина списания"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/{id} [delete]
 func (h *BookHandler) DeleteBook(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.bookUC.DeleteBook(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	if err := h.bookUC.DeleteBook(c.Request.Context(), id, c.Query("reason")); err != nil {
+		bookStateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, map[string]string{"status": "withdrawn"})
+}
+
+// RestoreBook godoc
+// @Summary Вернуть списанную книгу в каталог
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/restore [post]
+func (h *BookHandler) RestoreBook(c *gin.Context) {
+	if err := h.bookUC.RestoreBook(c.Request.Context(), c.Param("id")); err != nil {
+		bookStateError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+	c.JSON(http.StatusOK, map[string]string{"status": "restored"})
 }
 
 // GetBookByID godoc
@@ -90,9 +129,12 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 // @Tags books
 // @Produce json
 // @Param id path string true "ID книги"
-// @Success 200 {object} domain.Book
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.BookResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/{id} [get]
 func (h *BookHandler) GetBookByID(c *gin.Context) {
 	id := c.Param("id")
@@ -101,40 +143,156 @@ func (h *BookHandler) GetBookByID(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, book)
+	respond(c, http.StatusOK, book)
+}
+
+// RelatedBooks godoc
+// @Summary Другие издания и соседние тома книги
+// @Description Издания того же произведения и предыдущий и следующий тома серии (по номеру тома).
+// @Description Списанные книги не показываются
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.RelatedBooksResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/related [get]
+func (h *BookHandler) RelatedBooks(c *gin.Context) {
+	related, err := h.bookUC.RelatedBooks(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrInvalidID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid book ID"})
+		case errors.Is(err, customErr.ErrBookNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "book not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	respond(c, http.StatusOK, related)
+}
+
+// GetBookByISBN godoc
+// @Summary Найти книгу по ISBN
+// @Description ISBN-10 или ISBN-13, с дефисами или без
+// @Tags books
+// @Produce json
+// @Param isbn path string true "ISBN"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.BookResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByISBN(c *gin.Context) {
+	book, err := h.bookUC.GetBookByISBN(c.Request.Context(), c.Param("isbn"))
+	if err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrInvalidISBN):
+			c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ISBN"})
+		case errors.Is(err, customErr.ErrBookNotFound):
+			c.JSON(http.StatusNotFound, map[string]string{"error": "book not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+	respond(c, http.StatusOK, book)
 }
 
 // SearchBooks godoc
 // @Summary Поиск книг
+// @Description q — полнотекстовый поиск по названию, автору и жанру с учётом словоформ; результаты упорядочены по релевантности.
+// @Description Поддерживаются фразы в кавычках ("война и мир") и исключение слов через минус (-мир).
+// @Description С facets=true ответ — объект {items, facets} с распределением всей выборки по жанру, автору, десятилетию и доступности.
+// @Description title, author и q находят книги независимо от алфавита, регистра и ё/е ("tolstoy", "Толстои", "ежик").
+// @Description Если ничего не нашлось, слова с опечатками заменяются ближайшими словами каталога: выдача — по исправленному
+// @Description запросу, а сам запрос приходит в заголовке X-Did-You-Mean (и в поле didYouMean при facets=true).
+// @Description С groupBy=work издания одного произведения сворачиваются в одну книгу (первую по сортировке)
+// @Description с числом изданий в поле editions; X-Total-Count тогда считает произведения.
 // @Tags books
 // @Produce json
-// @Param title query string false "Название книги"
-// @Param author query string false "Автор"
-// @Param genre query []string false "Жанры (можно несколько)" collectionFormat(multi)
-// @Success 200 {array} domain.Book
+// @Param q query string false "Полнотекстовый запрос"
+// @Param title query string false "Название книги (подстрока)"
+// @Param author query string false "Автор (подстрока)"
+// @Param authorId query string false "ID автора из справочника (любая роль)"
+// @Param genre query []string false "Жанры: ID или название из рубрикатора, вместе с поджанрами (можно несколько)" collectionFormat(multi)
+// @Param isbn query string false "ISBN или его часть, с дефисами или без"
+// @Param decade query int false "Десятилетие издания (1990 — годы 1990-1999)"
+// @Param available query bool false "Только книги со свободными экземплярами"
+// @Param withdrawn query string false "Списанные книги: include — показать вместе с остальными, only — только их"
+// @Param workId query string false "Только издания произведения"
+// @Param seriesId query string false "Только книги серии (по томам — sort=volume)"
+// @Param groupBy query string false "work — по одной книге на произведение"
+// @Param facets query bool false "Добавить фасеты к ответу"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: title, author, year, volume, relevance (только с q; по умолчанию при q); \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.BookResponse
+// @Success 200 {object} dto.BookSearchResult "при facets=true"
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Header 200 {string} X-Did-You-Mean "Исправленный запрос в виде параметров: title=...&author=..."
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/search [get]
 func (h *BookHandler) SearchBooks(c *gin.Context) {
-	filter := domain.BookFilter{
-		Title:  c.Query("title"),
-		Author: c.Query("author"),
-		Genres: c.QueryArray("genre"),
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	filter, ok := bookFilter(c)
+	if !ok {
+		return
 	}
+	switch c.Query("groupBy") {
+	case "":
+	case "work":
+		filter.GroupByWork = true
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "groupBy must be work"})
+		return
+	}
+	withFacets := c.Query("facets") == "true"
 
-	books, err := h.bookUC.SearchBooks(c.Request.Context(), filter)
+	books, err := h.bookUC.SearchBooks(c.Request.Context(), filter, page, withFacets)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		if errors.Is(err, customErr.ErrInvalidID) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid author ID"})
+			return
+		}
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	setSearchHeaders(c, books.Total, books.Next, books.DidYouMean)
+	if withFacets {
+		respond(c, http.StatusOK, books)
 		return
 	}
-	c.JSON(http.StatusOK, books)
+	respond(c, http.StatusOK, books.Items)
 }
 
 // CountBooks godoc
 // @Summary Подсчитать общее количество книг
 // @Tags books
 // @Produce json
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
 // @Success 200 {object} map[string]int64
 // @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /books/count [get]
 func (h *BookHandler) CountBooks(c *gin.Context) {
 	count, err := h.bookUC.CountBooks(c.Request.Context())
@@ -142,5 +300,82 @@ func (h *BookHandler) CountBooks(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, map[string]int64{"count": count})
+	respond(c, http.StatusOK, map[string]int64{"count": count})
+}
+
+// bookFilter — фильтр каталога из параметров запроса (общий для поиска и экспорта);
+// false — ответ 400 уже отправлен
+func bookFilter(c *gin.Context) (domain.BookFilter, bool) {
+	filter := domain.BookFilter{
+		Query:  c.Query("q"),
+		Title:  c.Query("title"),
+		Author: c.Query("author"),
+		Genres: c.QueryArray("genre"),
+		ISBN:   c.Query("isbn"),
+
+		AuthorID:      c.Query("authorId"),
+		WorkID:        c.Query("workId"),
+		SeriesID:      c.Query("seriesId"),
+		AvailableOnly: c.Query("available") == "true",
+		Withdrawn:     c.Query("withdrawn"),
+	}
+	switch filter.Withdrawn {
+	case domain.WithdrawnExclude, domain.WithdrawnInclude, domain.WithdrawnOnly:
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "withdrawn must be include or only"})
+		return filter, false
+	}
+	if raw := c.Query("decade"); raw != "" {
+		decade, err := strconv.Atoi(raw)
+		if err != nil || decade%10 != 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "decade must be a year divisible by 10"})
+			return filter, false
+		}
+		filter.Decade = decade
+	}
+	return filter, true
+}
+
+// bookStateError — ошибки списания и возврата книги
+func bookStateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid book ID"})
+	case errors.Is(err, customErr.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, map[string]string{"error": "book not found"})
+	case errors.Is(err, customErr.ErrBookHasLoans):
+		c.JSON(http.StatusConflict, map[string]string{"error": "book is on loan, return all copies first"})
+	case errors.Is(err, customErr.ErrBookWithdrawn):
+		c.JSON(http.StatusConflict, map[string]string{"error": "book is already withdrawn"})
+	case errors.Is(err, customErr.ErrBookNotWithdrawn):
+		c.JSON(http.StatusConflict, map[string]string{"error": "book is not withdrawn"})
+	default:
+		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// bookWriteError — ошибки создания и изменения книги
+func bookWriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidISBN):
+		c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ISBN"})
+	case errors.Is(err, customErr.ErrISBNTaken):
+		c.JSON(http.StatusConflict, map[string]string{"error": "ISBN already used by another book"})
+	case errors.Is(err, customErr.ErrInvalidContributor):
+		c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid contributor role"})
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid ID"})
+	case errors.Is(err, customErr.ErrAuthorNotFound):
+		c.JSON(http.StatusNotFound, map[string]string{"error": "author not found"})
+	case errors.Is(err, customErr.ErrGenreNotFound):
+		c.JSON(http.StatusNotFound, map[string]string{"error": "genre not found"})
+	case errors.Is(err, customErr.ErrWorkNotFound):
+		c.JSON(http.StatusNotFound, map[string]string{"error": "work not found"})
+	case errors.Is(err, customErr.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, map[string]string{"error": "series not found"})
+	case errors.Is(err, customErr.ErrInvalidVolume):
+		c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
 }
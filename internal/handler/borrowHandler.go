@@ -24,10 +24,13 @@ func NewBorrowHandler(borrowUC usecase.BorrowUC) *BorrowHandler {
 // @Accept json
 // @Produce json
 // @Param input body dto.BorrowBookInput true "Данные для выдачи"
-// @Success 200 {object} domain.Borrow
+// @Success 200 {object} dto.BorrowResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /borrow [post]
 func (h *BorrowHandler) BorrowBook(c *gin.Context) {
 	var input dto.BorrowBookInput
@@ -43,8 +46,16 @@ func (h *BorrowHandler) BorrowBook(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
 		case errors.Is(err, customErr.ErrBookAlreadyBorrowed):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "book already borrowed"})
+		case errors.Is(err, customErr.ErrNoAvailableCopies):
+			c.JSON(http.StatusConflict, gin.H{"error": "no available copies"})
+		case errors.Is(err, customErr.ErrItemUnavailable):
+			c.JSON(http.StatusConflict, gin.H{"error": "item is not available"})
+		case errors.Is(err, customErr.ErrBookWithdrawn):
+			c.JSON(http.StatusConflict, gin.H{"error": "book is withdrawn"})
 		case errors.Is(err, customErr.ErrBookNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
+		case errors.Is(err, customErr.ErrItemNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
 		case errors.Is(err, customErr.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		default:
@@ -66,6 +77,8 @@ func (h *BorrowHandler) BorrowBook(c *gin.Context) {
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /borrow/return [post]
 func (h *BorrowHandler) ReturnBook(c *gin.Context) {
 	var input dto.ReturnBookInput
@@ -97,16 +110,33 @@ func (h *BorrowHandler) ReturnBook(c *gin.Context) {
 // @Tags borrow
 // @Produce json
 // @Param userID path string true "ID пользователя"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: borrowedAt (по умолчанию); \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
 // @Success 200 {object} dto.BorrowHistoryResponse
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /borrow/history/{userID} [get]
 func (h *BorrowHandler) GetBorrowHistory(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
 	userID := c.Param("userID")
-	history, err := h.borrowUC.GetBorrowHistory(c.Request.Context(), userID)
+	history, err := h.borrowUC.GetBorrowHistory(c.Request.Context(), userID, page)
 	if err != nil {
+		if pageError(c, err) {
+			return
+		}
 		switch {
+		case isForbidden(err):
+			forbidden(c, err)
 		case errors.Is(err, customErr.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		case errors.Is(err, customErr.ErrInvalidID):
@@ -116,23 +146,40 @@ func (h *BorrowHandler) GetBorrowHistory(c *gin.Context) {
 		}
 		return
 	}
-	c.JSON(http.StatusOK, history)
+	setPageHeaders(c, history.Total, history.Next)
+	respond(c, http.StatusOK, history)
 }
 
 // GetOverdueBorrows godoc
 // @Summary Просроченные книги
 // @Tags borrow
 // @Produce json
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: borrowedAt (по умолчанию); \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
 // @Success 200 {array} dto.OverdueReportItem
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /borrow/overdue [get]
 func (h *BorrowHandler) GetOverdueBorrows(c *gin.Context) {
-	result, err := h.borrowUC.GetOverdueBorrows(c.Request.Context())
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	result, err := h.borrowUC.GetOverdueBorrows(c.Request.Context(), page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	setPageHeaders(c, result.Total, result.Next)
+	respond(c, http.StatusOK, result.Items)
 }
 
 // GetDailyBorrowStats godoc
@@ -141,9 +188,13 @@ func (h *BorrowHandler) GetOverdueBorrows(c *gin.Context) {
 // @Produce json
 // @Param from query string true "Дата начала (YYYY-MM-DD)"
 // @Param to query string true "Дата конца (YYYY-MM-DD)"
+// @Param workId query string false "Только выдачи изданий произведения"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
 // @Success 200 {array} domain.BorrowStat
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /borrow/stats [get]
 func (h *BorrowHandler) GetDailyBorrowStats(c *gin.Context) {
 	fromStr := c.Query("from")
@@ -160,20 +211,27 @@ func (h *BorrowHandler) GetDailyBorrowStats(c *gin.Context) {
 		return
 	}
 
-	stats, err := h.borrowUC.GetDailyBorrowStats(c.Request.Context(), from, to)
+	stats, err := h.borrowUC.GetDailyBorrowStats(c.Request.Context(), from, to, c.Query("workId"))
 	if err != nil {
+		if errors.Is(err, customErr.ErrInvalidID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid work ID"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
-	c.JSON(http.StatusOK, stats)
+	respond(c, http.StatusOK, stats)
 }
 
 // CountActiveBorrows godoc
 // @Summary Кол-во активных выдач
 // @Tags borrow
 // @Produce json
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
 // @Success 200 {object} dto.CountResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /borrow/active-count [get]
 func (h *BorrowHandler) CountActiveBorrows(c *gin.Context) {
 	count, err := h.borrowUC.CountActiveBorrows(c.Request.Context())
@@ -181,5 +239,5 @@ func (h *BorrowHandler) CountActiveBorrows(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
-	c.JSON(http.StatusOK, map[string]int64{"count": count})
+	respond(c, http.StatusOK, map[string]int64{"count": count})
 }
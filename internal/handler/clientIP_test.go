@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/audit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Подделанный X-Forwarded-For не должен менять IP для блокировки входа и журнала аудита
+func TestClientIPIgnoresForwardedFromUntrustedPeer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		remote  string
+		want    string
+	}{
+		{name: "no trusted proxies", proxies: nil, remote: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "peer is not a trusted proxy", proxies: []string{"10.0.0.0/8"}, remote: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "trusted proxy", proxies: []string{"10.0.0.0/8"}, remote: "10.1.2.3:5000", want: "198.51.100.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			if err := r.SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatal(err)
+			}
+			var loginIP, auditIP string
+			r.Use(RequestID())
+			r.GET("/", func(c *gin.Context) {
+				loginIP = clientInfo(c).IP
+				auditIP = audit.RequestFromContext(c.Request.Context()).IP
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "198.51.100.9")
+			req.Header.Set("X-Real-IP", "198.51.100.9")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if loginIP != tt.want || auditIP != tt.want {
+				t.Fatalf("login IP %q, audit IP %q, want %q", loginIP, auditIP, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type CoverHandler struct {
+	coverUC usecase.CoverUC
+}
+
+func NewCoverHandler(coverUC usecase.CoverUC) *CoverHandler {
+	return &CoverHandler{coverUC: coverUC}
+}
+
+// SetCover godoc
+// @Summary Загрузить обложку книги
+// @Description Изображение JPEG, PNG, GIF или WebP телом запроса либо полем file формы; тип определяется по содержимому.
+// @Description Прежняя обложка заменяется. Миниатюры small (160px) и medium (480px) строятся сразу, в JPEG.
+// @Tags books
+// @Accept image/jpeg
+// @Accept image/png
+// @Accept image/gif
+// @Accept image/webp
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param file formData file false "Изображение (для multipart/form-data)"
+// @Success 200 {object} dto.CoverResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 415 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/cover [post]
+func (h *CoverHandler) SetCover(c *gin.Context) {
+	body, err := uploadReader(c)
+	if err != nil {
+		coverError(c, err)
+		return
+	}
+	defer body.Close()
+
+	cover, err := h.coverUC.SetCover(c.Request.Context(), c.Param("id"), body)
+	if err != nil {
+		coverError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cover)
+}
+
+// GetCover godoc
+// @Summary Обложка книги
+// @Description Без авторизации, чтобы обложку можно было показать тегом img. Поддерживаются If-None-Match и If-Modified-Since.
+// @Description Адрес с актуальной версией (?v= из dto.CoverResponse) кэшируется бессрочно, без неё — с проверкой ETag.
+// @Tags books
+// @Produce image/jpeg
+// @Produce image/png
+// @Produce image/gif
+// @Produce image/webp
+// @Param id path string true "ID книги"
+// @Param size query string false "small или medium; по умолчанию оригинал"
+// @Param v query string false "Версия обложки"
+// @Success 200 {file} file "Изображение"
+// @Success 304 "Не изменилась"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /books/{id}/cover [get]
+func (h *CoverHandler) GetCover(c *gin.Context) {
+	file, err := h.coverUC.GetCover(c.Request.Context(), c.Param("id"), c.Query("size"))
+	if err != nil {
+		coverError(c, err)
+		return
+	}
+
+	// Новая обложка получает новую версию, а с ней и новый адрес
+	if v := c.Query("v"); v != "" && v == file.Version {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "public, no-cache")
+	}
+	c.Header("ETag", file.ETag)
+	c.Header("Content-Type", file.ContentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, "", file.ModTime, bytes.NewReader(file.Data))
+}
+
+// DeleteCover godoc
+// @Summary Удалить обложку книги
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/cover [delete]
+func (h *CoverHandler) DeleteCover(c *gin.Context) {
+	if err := h.coverUC.DeleteCover(c.Request.Context(), c.Param("id")); err != nil {
+		coverError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+func coverError(c *gin.Context, err error) {
+	if uploadFailed(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid book ID"})
+	case errors.Is(err, customErr.ErrInvalidCoverSize):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "size must be small or medium"})
+	case errors.Is(err, customErr.ErrCoverTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: customErr.ErrCoverTooLarge.Error()})
+	case errors.Is(err, customErr.ErrUnsupportedImage):
+		c.JSON(http.StatusUnsupportedMediaType, dto.ErrorResponse{Error: customErr.ErrUnsupportedImage.Error()})
+	case errors.Is(err, customErr.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "book not found"})
+	case errors.Is(err, customErr.ErrCoverNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "cover not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
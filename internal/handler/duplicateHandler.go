@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strconv"
+)
+
+// Параметры поиска дубликатов по умолчанию
+const (
+	defaultDuplicateScore = 0.85
+	defaultDuplicateLimit = 100
+	maxDuplicateLimit     = 1000
+)
+
+type DuplicateHandler struct {
+	duplicateUC usecase.DuplicateUC
+}
+
+func NewDuplicateHandler(duplicateUC usecase.DuplicateUC) *DuplicateHandler {
+	return &DuplicateHandler{duplicateUC: duplicateUC}
+}
+
+// FindDuplicates godoc
+// @Summary Вероятные дубликаты книг
+// @Description Книги каталога сравниваются по нормализованным названию, автору и году (регистр, пробелы и пунктуация не важны).
+// @Description Пары отсортированы по убыванию сходства; книги с разными ISBN получают пониженную оценку.
+// @Tags books
+// @Produce json
+// @Param minScore query number false "Минимальное сходство пары (0-1, по умолчанию 0.85)"
+// @Param limit query int false "Сколько пар вернуть (1-1000, по умолчанию 100)"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.DuplicateCandidate
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/duplicates [get]
+func (h *DuplicateHandler) FindDuplicates(c *gin.Context) {
+	minScore := defaultDuplicateScore
+	if raw := c.Query("minScore"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v <= 0 || v > 1 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "minScore must be a number in (0, 1]"})
+			return
+		}
+		minScore = v
+	}
+	limit := defaultDuplicateLimit
+	if raw := c.Query("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxDuplicateLimit {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "limit must be an integer in [1, 1000]"})
+			return
+		}
+		limit = v
+	}
+
+	pairs, err := h.duplicateUC.FindDuplicates(c.Request.Context(), minScore, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, pairs)
+}
+
+// MergeBooks godoc
+// @Summary Объединить дубликаты книги
+// @Description Выдачи и экземпляры дубликата переносятся на книгу из пути, поля этой книги не меняются.
+// @Description ID дубликата продолжает открывать оставшуюся книгу, в поиске дубликат больше не показывается.
+// @Description Если перенос оборвался, повторный запрос с тем же дубликатом его завершит; счётчики — перенесённое этим запросом
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "ID книги, которая остаётся"
+// @Param input body dto.MergeBooksInput true "ID дубликата"
+// @Success 200 {object} dto.MergeBooksResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/merge [post]
+func (h *DuplicateHandler) MergeBooks(c *gin.Context) {
+	var input dto.MergeBooksInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	res, err := h.duplicateUC.MergeBooks(c.Request.Context(), c.Param("id"), input.DuplicateID)
+	if err != nil {
+		mergeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func mergeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrMergeSelf):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "cannot merge a book into itself"})
+	case errors.Is(err, customErr.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "book not found"})
+	case errors.Is(err, customErr.ErrBookWithdrawn):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "cannot merge into a withdrawn book"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strings"
+)
+
+// fieldTree — разобранный параметр ?fields=a,b.c: ключ -> вложенные поля (nil — поле целиком)
+type fieldTree map[string]fieldTree
+
+// parseFields разбирает список полей через запятую, вложенные поля — через точку
+func parseFields(raw string) fieldTree {
+	tree := fieldTree{}
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		node := tree
+		parts := strings.Split(f, ".")
+		for i, part := range parts {
+			child, seen := node[part]
+			if seen && child == nil {
+				break // поле уже запрошено целиком
+			}
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+			if child == nil {
+				child = fieldTree{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// pick оставляет в JSON-значении только поля из дерева; массивы обрабатываются поэлементно
+func (t fieldTree) pick(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for key, sub := range t {
+			fv, ok := val[key]
+			if !ok {
+				continue
+			}
+			if sub == nil {
+				out[key] = fv
+			} else {
+				out[key] = sub.pick(fv)
+			}
+		}
+		return out
+	case []any:
+		for i := range val {
+			val[i] = t.pick(val[i])
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// respond отдаёт JSON с учётом sparse fieldsets (?fields=id,title,history.title)
+func respond(c *gin.Context, status int, payload any) {
+	raw := c.Query("fields")
+	if raw == "" {
+		c.JSON(status, payload)
+		return
+	}
+	tree := parseFields(raw)
+	if len(tree) == 0 {
+		c.JSON(status, payload)
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.JSON(status, tree.pick(generic))
+}
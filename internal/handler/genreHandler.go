@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type GenreHandler struct {
+	genreUC usecase.GenreUC
+}
+
+func NewGenreHandler(genreUC usecase.GenreUC) *GenreHandler {
+	return &GenreHandler{genreUC: genreUC}
+}
+
+// CreateGenre godoc
+// @Summary Добавить жанр в рубрикатор
+// @Description Названия и синонимы уникальны во всём рубрикаторе без учёта регистра и пунктуации
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateGenreInput true "Названия, синонимы и родительский жанр"
+// @Success 201 {object} dto.GenreResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var input dto.CreateGenreInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	genre, err := h.genreUC.CreateGenre(c.Request.Context(), input)
+	if err != nil {
+		genreError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, genre)
+}
+
+// GenreTree godoc
+// @Summary Рубрикатор жанров
+// @Description Дерево жанров с числом книг: books — в самом жанре, totalBooks — вместе с поджанрами
+// @Description (столько найдёт /books?genre=...). Списанные книги не считаются
+// @Tags genres
+// @Produce json
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.GenreTree
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /genres [get]
+func (h *GenreHandler) GenreTree(c *gin.Context) {
+	tree, err := h.genreUC.GenreTree(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, tree)
+}
+
+// GetGenre godoc
+// @Summary Получить жанр
+// @Tags genres
+// @Produce json
+// @Param id path string true "ID жанра"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.GenreResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /genres/{id} [get]
+func (h *GenreHandler) GetGenre(c *gin.Context) {
+	genre, err := h.genreUC.GetGenre(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		genreError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, genre)
+}
+
+// UpdateGenre godoc
+// @Summary Изменить жанр
+// @Description Новое русское название переносится во все книги жанра, прежнее становится синонимом.
+// @Description Смена parentId переносит жанр вместе с поджанрами; "" — в корень рубрикатора
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path string true "ID жанра"
+// @Param input body dto.UpdateGenreInput true "Обновляемые поля"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /genres/{id} [put]
+func (h *GenreHandler) UpdateGenre(c *gin.Context) {
+	var input dto.UpdateGenreInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	input.ID = c.Param("id")
+
+	if err := h.genreUC.UpdateGenre(c.Request.Context(), input); err != nil {
+		genreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "updated"})
+}
+
+// DeleteGenre godoc
+// @Summary Удалить жанр
+// @Description Удаляется только жанр без поджанров, на который не ссылается ни одна книга
+// @Tags genres
+// @Produce json
+// @Param id path string true "ID жанра"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /genres/{id} [delete]
+func (h *GenreHandler) DeleteGenre(c *gin.Context) {
+	if err := h.genreUC.DeleteGenre(c.Request.Context(), c.Param("id")); err != nil {
+		genreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "deleted"})
+}
+
+// MergeGenres godoc
+// @Summary Объединить два жанра
+// @Description Дубликат вливается в жанр из пути: его названия становятся синонимами, книги перепривязываются,
+// @Description поджанры переносятся, а сам дубликат удаляется
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path string true "ID жанра, который остаётся"
+// @Param input body dto.MergeGenresInput true "ID дубликата"
+// @Success 200 {object} dto.MergeGenresResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /genres/{id}/merge [post]
+func (h *GenreHandler) MergeGenres(c *gin.Context) {
+	var input dto.MergeGenresInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	res, err := h.genreUC.MergeGenres(c.Request.Context(), c.Param("id"), input.DuplicateID)
+	if err != nil {
+		genreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func genreError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrMergeSelf):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "cannot merge a genre into itself"})
+	case errors.Is(err, customErr.ErrGenreLabelRequired), errors.Is(err, customErr.ErrGenreCycle):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, customErr.ErrGenreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "genre not found"})
+	case errors.Is(err, customErr.ErrGenreLabelTaken),
+		errors.Is(err, customErr.ErrGenreInUse),
+		errors.Is(err, customErr.ErrGenreHasChildren):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
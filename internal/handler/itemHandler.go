@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type ItemHandler struct {
+	itemUC usecase.ItemUC
+}
+
+func NewItemHandler(itemUC usecase.ItemUC) *ItemHandler {
+	return &ItemHandler{itemUC: itemUC}
+}
+
+// ListItems godoc
+// @Summary Экземпляры книги
+// @Tags items
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.ItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/items [get]
+func (h *ItemHandler) ListItems(c *gin.Context) {
+	items, err := h.itemUC.ListItems(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		itemError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, items)
+}
+
+// CreateItem godoc
+// @Summary Добавить экземпляр книги
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param input body dto.CreateItemInput true "Штрихкод, место хранения, дата поступления"
+// @Success 201 {object} dto.ItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/items [post]
+func (h *ItemHandler) CreateItem(c *gin.Context) {
+	var input dto.CreateItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	input.BookID = c.Param("id")
+
+	item, err := h.itemUC.CreateItem(c.Request.Context(), input)
+	if err != nil {
+		itemError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// GetItem godoc
+// @Summary Получить экземпляр по ID
+// @Tags items
+// @Produce json
+// @Param id path string true "ID экземпляра"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.ItemResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /items/{id} [get]
+func (h *ItemHandler) GetItem(c *gin.Context) {
+	item, err := h.itemUC.GetItem(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		itemError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, item)
+}
+
+// GetItemByBarcode godoc
+// @Summary Найти экземпляр по штрихкоду
+// @Tags items
+// @Produce json
+// @Param barcode path string true "Штрихкод"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.ItemResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /items/barcode/{barcode} [get]
+func (h *ItemHandler) GetItemByBarcode(c *gin.Context) {
+	item, err := h.itemUC.GetItemByBarcode(c.Request.Context(), c.Param("barcode"))
+	if err != nil {
+		itemError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, item)
+}
+
+// UpdateItem godoc
+// @Summary Изменить экземпляр
+// @Description Статус выданного экземпляра меняется возвратом; вручную его можно только пометить утерянным
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "ID экземпляра"
+// @Param input body dto.UpdateItemInput true "Обновляемые поля"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /items/{id} [put]
+func (h *ItemHandler) UpdateItem(c *gin.Context) {
+	var input dto.UpdateItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	input.ID = c.Param("id")
+
+	if err := h.itemUC.UpdateItem(c.Request.Context(), input); err != nil {
+		itemError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "updated"})
+}
+
+// DeleteItem godoc
+// @Summary Списать экземпляр
+// @Tags items
+// @Produce json
+// @Param id path string true "ID экземпляра"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /items/{id} [delete]
+func (h *ItemHandler) DeleteItem(c *gin.Context) {
+	if err := h.itemUC.DeleteItem(c.Request.Context(), c.Param("id")); err != nil {
+		itemError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "deleted"})
+}
+
+func itemError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrBarcodeRequired):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "barcode required"})
+	case errors.Is(err, customErr.ErrInvalidItemStatus):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid item status"})
+	case errors.Is(err, customErr.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "book not found"})
+	case errors.Is(err, customErr.ErrItemNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "item not found"})
+	case errors.Is(err, customErr.ErrBarcodeTaken):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "barcode already taken"})
+	case errors.Is(err, customErr.ErrItemOnLoan):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "item is on loan"})
+	case errors.Is(err, customErr.ErrItemUnavailable):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "item status changed concurrently"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/marc"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type MARCHandler struct {
+	marcUC usecase.MARCUC
+}
+
+func NewMARCHandler(marcUC usecase.MARCUC) *MARCHandler {
+	return &MARCHandler{marcUC: marcUC}
+}
+
+// ImportMARC godoc
+// @Summary Импорт каталога из MARC21
+// @Description Принимает двоичный MARC21 (ISO 2709) или MARCXML телом запроса либо полем file формы.
+// @Description Поля: 020 — ISBN, 100/700 — автор и участники ($e/$4 — роль), 245 — название, 260/264 $c — год, 650 — жанр.
+// @Description Книги с тем же ISBN или тем же названием и автором (в каталоге или выше в файле) пропускаются как дубликаты.
+// @Description С dryRun=true ничего не сохраняется: отчёт показывает, какие книги и авторы были бы заведены.
+// @Tags books
+// @Accept octet-stream
+// @Accept xml
+// @Accept mpfd
+// @Produce json
+// @Param format query string false "iso2709 или marcxml; по умолчанию определяется по содержимому"
+// @Param dryRun query bool false "Только проверить и показать отчёт"
+// @Param file formData file false "Файл MARC (для multipart/form-data)"
+// @Success 200 {object} dto.MARCImportReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/import/marc [post]
+func (h *MARCHandler) ImportMARC(c *gin.Context) {
+	format := c.Query("format")
+	if format != "" && format != marc.FormatISO2709 && format != marc.FormatXML {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "format must be iso2709 or marcxml"})
+		return
+	}
+	dryRun := c.Query("dryRun") == "true"
+
+	body, err := uploadReader(c)
+	if err != nil {
+		marcUploadError(c, err)
+		return
+	}
+	defer body.Close()
+
+	report, err := h.marcUC.ImportMARC(c.Request.Context(), body, format, dryRun)
+	if err != nil {
+		marcUploadError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportMARC godoc
+// @Summary Экспорт каталога в MARCXML
+// @Description Выгружает потоком весь каталог или книги под фильтром (те же параметры, что у /books/search).
+// @Tags books
+// @Produce xml
+// @Param q query string false "Полнотекстовый запрос"
+// @Param title query string false "Название книги (подстрока)"
+// @Param author query string false "Автор (подстрока)"
+// @Param authorId query string false "ID автора из справочника (любая роль)"
+// @Param genre query []string false "Жанры: ID или название из рубрикатора, вместе с поджанрами (можно несколько)" collectionFormat(multi)
+// @Param isbn query string false "ISBN или его часть, с дефисами или без"
+// @Param decade query int false "Десятилетие издания (1990 — годы 1990-1999)"
+// @Param available query bool false "Только книги со свободными экземплярами"
+// @Param withdrawn query string false "Списанные книги: include — показать вместе с остальными, only — только их"
+// @Success 200 {file} file "MARCXML (collection)"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/export/marc [get]
+func (h *MARCHandler) ExportMARC(c *gin.Context) {
+	filter, ok := bookFilter(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "application/marcxml+xml; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="catalog.xml"`)
+	c.Status(http.StatusOK)
+
+	if _, err := h.marcUC.ExportMARC(c.Request.Context(), filter, c.Writer); err != nil {
+		// Выгрузка уже началась — статус не поменять, ответ просто обрывается
+		if c.Writer.Written() {
+			_ = c.Error(err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		if errors.Is(err, customErr.ErrInvalidID) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid author ID"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
+
+func marcUploadError(c *gin.Context, err error) {
+	if uploadFailed(c, err) {
+		return
+	}
+	if errors.Is(err, customErr.ErrInvalidMARC) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/auth"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strings"
+)
+
+// TokenParser — проверка access-токена (реализуется auth.TokenManager)
+type TokenParser interface {
+	ParseAccessToken(raw string) (*auth.Claims, error)
+}
+
+// SessionChecker — проверка, что сессия токена не отозвана (реализуется usecase.SessionUsecase)
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, id string) (bool, error)
+}
+
+// APIKeyAuthenticator — проверка API-ключа (реализуется usecase.APIKeyUsecase)
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key, ip string) (auth.Principal, error)
+}
+
+// AuthMiddleware проверяет заголовок Authorization: Bearer <token> (пользователь)
+// или Authorization: ApiKey <key> / X-API-Key: <key> (киоски и интеграции) и кладёт
+// principal в контекст запроса. Без заголовков запрос пропускается анонимным,
+// недействительный токен, ключ или отозванная сессия — 401.
+func AuthMiddleware(tokens TokenParser, sessions SessionChecker, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if key := c.GetHeader("X-API-Key"); key != "" && header == "" {
+			authenticateAPIKey(c, apiKeys, key)
+			return
+		}
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		scheme, raw, ok := strings.Cut(header, " ")
+		raw = strings.TrimSpace(raw)
+		if ok && strings.EqualFold(scheme, "ApiKey") && raw != "" {
+			authenticateAPIKey(c, apiKeys, raw)
+			return
+		}
+		if !ok || !strings.EqualFold(scheme, "Bearer") || raw == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid authorization header", Code: dto.CodeInvalidToken})
+			return
+		}
+
+		claims, err := tokens.ParseAccessToken(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid or expired token", Code: dto.CodeInvalidToken})
+			return
+		}
+
+		active, err := sessions.IsSessionActive(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "session revoked", Code: dto.CodeSessionRevoked})
+			return
+		}
+
+		setPrincipal(c, auth.Principal{UserID: claims.Subject, Role: claims.Role, SessionID: claims.SessionID})
+		c.Next()
+	}
+}
+
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, key string) {
+	principal, err := apiKeys.AuthenticateAPIKey(c.Request.Context(), key, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrInvalidAPIKey):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid API key", Code: dto.CodeInvalidAPIKey})
+		case errors.Is(err, customErr.ErrAPIKeyIPDenied):
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: "API key is not allowed from this address", Code: dto.CodeIPNotAllowed})
+		default:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	setPrincipal(c, principal)
+	c.Next()
+}
+
+func setPrincipal(c *gin.Context, principal auth.Principal) {
+	c.Set(principalKey, principal)
+	c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+}
+
+// Authorize применяет таблицу доступа к маршруту запроса.
+// Запросы к незарегистрированным маршрутам отдаются gin (404).
+func Authorize(policy auth.RoutePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			c.Next()
+			return
+		}
+
+		var principal *auth.Principal
+		if p, ok := c.Get(principalKey); ok {
+			pr := p.(auth.Principal)
+			principal = &pr
+		}
+
+		if err := policy.Check(c.Request.Method, path, principal); err != nil {
+			if errors.Is(err, customErr.ErrUnauthorized) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "authorization required", Code: dto.CodeAuthRequired})
+				return
+			}
+			code := dto.CodeRoleForbidden
+			if principal != nil && principal.IsAPIKey() {
+				code = dto.CodeScopeForbidden
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: "access denied", Code: code})
+			return
+		}
+		c.Next()
+	}
+}
+
+// forbidden — ответ 403 на отказ usecase: чужие данные или недостаточная роль
+func forbidden(c *gin.Context, err error) {
+	code := dto.CodeRoleForbidden
+	if errors.Is(err, customErr.ErrNotOwner) {
+		code = dto.CodeNotOwner
+	}
+	c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "access denied", Code: code})
+}
+
+// isForbidden — ошибка доступа из usecase
+func isForbidden(err error) bool {
+	return errors.Is(err, customErr.ErrForbidden) || errors.Is(err, customErr.ErrNotOwner)
+}
+
+// clientInfo — IP и User-Agent запроса для журнала сессий и блокировки входа по IP.
+// X-Forwarded-For учитывается только от прокси из TRUSTED_PROXIES: иначе каждый запрос
+// с новым заголовком получал бы свой счётчик неудач
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+const principalKey = "principal"
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	totalCountHeader = "X-Total-Count"
+	suggestionHeader = "X-Did-You-Mean"
+)
+
+// pageRequest читает limit, after и sort; при неверном limit отвечает 400 и возвращает false
+func pageRequest(c *gin.Context) (domain.PageRequest, bool) {
+	page := domain.PageRequest{
+		Limit: domain.DefaultPageLimit,
+		After: c.Query("after"),
+		Sort:  c.Query("sort"),
+	}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > domain.MaxPageLimit {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "limit must be between 1 and " + strconv.Itoa(domain.MaxPageLimit)})
+			return page, false
+		}
+		page.Limit = n
+	}
+	return page, true
+}
+
+// setPageHeaders — общее число записей в X-Total-Count и ссылка на следующую страницу в Link
+func setPageHeaders(c *gin.Context, total int64, next string) {
+	setSearchHeaders(c, total, next, nil)
+}
+
+// setSearchHeaders — setPageHeaders для поиска с подсказкой: исправленный запрос уходит в X-Did-You-Mean
+// параметрами запроса, а ссылка на следующую страницу ведёт по нему, иначе курсор к ней не подойдёт
+func setSearchHeaders(c *gin.Context, total int64, next string, suggestion dto.SearchSuggestion) {
+	c.Header(totalCountHeader, strconv.FormatInt(total, 10))
+	u := *c.Request.URL
+	q := u.Query()
+	if len(suggestion) > 0 {
+		fixed := url.Values{}
+		for param, value := range suggestion {
+			fixed.Set(param, value)
+			q.Set(param, value)
+		}
+		c.Header(suggestionHeader, fixed.Encode())
+	}
+	if next == "" {
+		return
+	}
+	q.Set("after", next)
+	u.RawQuery = q.Encode()
+	c.Header("Link", "<"+u.RequestURI()+`>; rel="next"`)
+}
+
+// pageError отвечает 400 на неверный курсор или поле сортировки; false — ошибка другая
+func pageError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid cursor"})
+	case errors.Is(err, customErr.ErrInvalidSort):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "unsupported sort"})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"library-Mongo/internal/audit"
+	"regexp"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID — принимаем ID от прокси/клиента, только если он безопасен для журналов
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID присваивает запросу ID (или берёт X-Request-ID клиента), возвращает его
+// в ответе и кладёт вместе с IP и User-Agent в контекст для журнала аудита.
+// IP — адрес соединения либо X-Forwarded-For от доверенного прокси (TRUSTED_PROXIES)
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(requestIDHeader, id)
+
+		meta := audit.RequestMeta{RequestID: id, IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+		c.Request = c.Request.WithContext(audit.WithRequest(c.Request.Context(), meta))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strconv"
+)
+
+type RevisionHandler struct {
+	revisionUC usecase.RevisionUC
+}
+
+func NewRevisionHandler(revisionUC usecase.RevisionUC) *RevisionHandler {
+	return &RevisionHandler{revisionUC: revisionUC}
+}
+
+// ListRevisions godoc
+// @Summary Ревизии книги
+// @Description Каждое создание, изменение и откат книги сохраняет ревизию с состоянием записи, автором изменения и временем.
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: number (по умолчанию -number — от новых к старым)"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.BookRevisionResponse
+// @Header 200 {integer} X-Total-Count "Всего ревизий"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/revisions [get]
+func (h *RevisionHandler) ListRevisions(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	revs, err := h.revisionUC.ListRevisions(c.Request.Context(), c.Param("id"), page)
+	if err != nil {
+		if !pageError(c, err) {
+			revisionError(c, err)
+		}
+		return
+	}
+	setPageHeaders(c, revs.Total, revs.Next)
+	respond(c, http.StatusOK, revs.Items)
+}
+
+// GetRevision godoc
+// @Summary Ревизия книги
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param number path int true "Номер ревизии"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.BookRevisionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/revisions/{number} [get]
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+	rev, err := h.revisionUC.GetRevision(c.Request.Context(), c.Param("id"), number)
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, rev)
+}
+
+// DiffRevisions godoc
+// @Summary Сравнить две ревизии книги
+// @Description Поля, которые различаются в ревизиях from и to: from — значение в ревизии from, to — в ревизии to.
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param from query int true "Номер первой ревизии"
+// @Param to query int true "Номер второй ревизии"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.RevisionDiff
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	from, ok := revisionNumber(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := revisionNumber(c, c.Query("to"))
+	if !ok {
+		return
+	}
+	diff, err := h.revisionUC.DiffRevisions(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, diff)
+}
+
+// RevertBook godoc
+// @Summary Откатить книгу к ревизии
+// @Description Возвращает название, автора, год, жанр, ISBN и участников из ревизии; откат сохраняется новой ревизией.
+// @Description Проверки те же, что у PUT /books: например, ISBN ревизии мог с тех пор достаться другой книге.
+// @Tags books
+// @Produce json
+// @Param id path string true "ID книги"
+// @Param number path int true "Номер ревизии"
+// @Success 200 {object} dto.BookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/{id}/revisions/{number}/revert [post]
+func (h *RevisionHandler) RevertBook(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("number"))
+	if !ok {
+		return
+	}
+	book, err := h.revisionUC.RevertBook(c.Request.Context(), c.Param("id"), number)
+	if err != nil {
+		revisionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, book)
+}
+
+// revisionNumber — номер ревизии из пути или параметра запроса; false — ответ 400 уже отправлен
+func revisionNumber(c *gin.Context, raw string) (int, bool) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "revision number must be a positive integer"})
+		return 0, false
+	}
+	return n, true
+}
+
+func revisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "book not found"})
+	case errors.Is(err, customErr.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "revision not found"})
+	case errors.Is(err, customErr.ErrAuthorNotFound):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "author of this revision no longer exists"})
+	case errors.Is(err, customErr.ErrISBNTaken):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "ISBN already used by another book"})
+	case errors.Is(err, customErr.ErrInvalidISBN):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ISBN"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
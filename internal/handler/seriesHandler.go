@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type SeriesHandler struct {
+	seriesUC usecase.SeriesUC
+}
+
+func NewSeriesHandler(seriesUC usecase.SeriesUC) *SeriesHandler {
+	return &SeriesHandler{seriesUC: seriesUC}
+}
+
+// CreateSeries godoc
+// @Summary Добавить серию
+// @Description Книги входят в серию через seriesId и volume в POST и PUT /books
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateSeriesInput true "Название серии"
+// @Success 201 {object} dto.SeriesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /series [post]
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var input dto.CreateSeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	series, err := h.seriesUC.CreateSeries(c.Request.Context(), input)
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, series)
+}
+
+// SearchSeries godoc
+// @Summary Поиск серий
+// @Tags series
+// @Produce json
+// @Param title query string false "Название (подстрока или слова)"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: title (по умолчанию); \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.SeriesResponse
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /series [get]
+func (h *SeriesHandler) SearchSeries(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	series, err := h.seriesUC.SearchSeries(c.Request.Context(), c.Query("title"), page)
+	if err != nil {
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	setPageHeaders(c, series.Total, series.Next)
+	respond(c, http.StatusOK, series.Items)
+}
+
+// GetSeries godoc
+// @Summary Получить серию
+// @Description Тома серии — GET /books/search?seriesId=...&sort=volume
+// @Tags series
+// @Produce json
+// @Param id path string true "ID серии"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.SeriesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /series/{id} [get]
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	series, err := h.seriesUC.GetSeries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		seriesError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, series)
+}
+
+// UpdateSeries godoc
+// @Summary Изменить серию
+// @Description Новое название переносится во все книги серии
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "ID серии"
+// @Param input body dto.UpdateSeriesInput true "Обновляемые поля"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /series/{id} [put]
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	var input dto.UpdateSeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	input.ID = c.Param("id")
+
+	if err := h.seriesUC.UpdateSeries(c.Request.Context(), input); err != nil {
+		seriesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "updated"})
+}
+
+// DeleteSeries godoc
+// @Summary Удалить серию
+// @Description Удаляется только серия, в которую не входит ни одна книга
+// @Tags series
+// @Produce json
+// @Param id path string true "ID серии"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /series/{id} [delete]
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	if err := h.seriesUC.DeleteSeries(c.Request.Context(), c.Param("id")); err != nil {
+		seriesError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "deleted"})
+}
+
+func seriesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrSeriesTitleRequired):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, customErr.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "series not found"})
+	case errors.Is(err, customErr.ErrSeriesInUse):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type SessionHandler struct {
+	sessionUC usecase.SessionUC
+}
+
+func NewSessionHandler(sessionUC usecase.SessionUC) *SessionHandler {
+	return &SessionHandler{sessionUC: sessionUC}
+}
+
+// Refresh godoc
+// @Summary Обновить пару токенов по refresh-токену
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param input body dto.RefreshRequest true "Refresh-токен"
+// @Success 200 {object} dto.TokenPair
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/refresh [post]
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+
+	tokens, err := h.sessionUC.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "refresh token reuse detected", Code: dto.CodeTokenReused})
+		case errors.Is(err, customErr.ErrInvalidToken):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid or expired refresh token", Code: dto.CodeInvalidToken})
+		case errors.Is(err, customErr.ErrUserBlocked):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "user is blocked"})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary Выйти (завершить текущую сессию)
+// @Tags sessions
+// @Produce json
+// @Success 200 {object} dto.StatusResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/logout [post]
+func (h *SessionHandler) Logout(c *gin.Context) {
+	if err := h.sessionUC.Logout(c.Request.Context()); err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrUnauthorized):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "authorization required", Code: dto.CodeAuthRequired})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "logged out"})
+}
+
+// ListSessions godoc
+// @Summary Активные сессии текущего пользователя
+// @Tags sessions
+// @Produce json
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.SessionResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.sessionUC.ListSessions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Завершить одну свою сессию (выход на устройстве)
+// @Tags sessions
+// @Produce json
+// @Param id path string true "ID сессии"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	if err := h.sessionUC.RevokeSession(c.Request.Context(), c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrInvalidID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+		case errors.Is(err, customErr.ErrSessionNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "session not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "revoked"})
+}
+
+// RevokeAllSessions godoc
+// @Summary Завершить все свои сессии (выход на всех устройствах)
+// @Tags sessions
+// @Produce json
+// @Success 200 {object} dto.CountResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/sessions [delete]
+func (h *SessionHandler) RevokeAllSessions(c *gin.Context) {
+	count, err := h.sessionUC.RevokeAllSessions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.CountResponse{Count: count})
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/sheet"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strings"
+)
+
+type SheetHandler struct {
+	sheetUC usecase.SheetUC
+}
+
+func NewSheetHandler(sheetUC usecase.SheetUC) *SheetHandler {
+	return &SheetHandler{sheetUC: sheetUC}
+}
+
+// ImportBooks godoc
+// @Summary Импорт книг из CSV или XLSX
+// @Description Первая строка — заголовок. Колонки id, title, author, year, genre, isbn, copies находятся по имени
+// @Description или сопоставляются параметром map (поле=Заголовок, например map=title=Название).
+// @Description Строки без id заводят книги, строки с id обновляют их (пустая ячейка — поле без изменений).
+// @Description Каждая строка проверяется по правилам POST/PUT /books; ошибки возвращаются по строкам.
+// @Description Выгрузка /books/export/spreadsheet загружается обратно без сопоставления.
+// @Tags books
+// @Accept text/csv
+// @Accept octet-stream
+// @Accept mpfd
+// @Produce json
+// @Param format query string false "csv или xlsx; по умолчанию определяется по содержимому"
+// @Param map query []string false "Сопоставление поле=Заголовок колонки" collectionFormat(multi)
+// @Param dryRun query bool false "Только проверить и показать отчёт"
+// @Param file formData file false "Файл таблицы (для multipart/form-data)"
+// @Success 200 {object} dto.SheetImportReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/import/spreadsheet [post]
+func (h *SheetHandler) ImportBooks(c *gin.Context) {
+	format := c.Query("format")
+	if format != "" && !sheet.IsFormat(format) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "format must be csv or xlsx"})
+		return
+	}
+	mapping := map[string]string{}
+	for _, pair := range c.QueryArray("map") {
+		field, column, ok := strings.Cut(pair, "=")
+		if !ok || field == "" || column == "" {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "map must look like field=Column"})
+			return
+		}
+		mapping[strings.ToLower(strings.TrimSpace(field))] = column
+	}
+	dryRun := c.Query("dryRun") == "true"
+
+	body, err := uploadReader(c)
+	if err != nil {
+		sheetImportError(c, err)
+		return
+	}
+	defer body.Close()
+
+	report, err := h.sheetUC.ImportBooks(c.Request.Context(), body, format, mapping, dryRun)
+	if err != nil {
+		sheetImportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportBooks godoc
+// @Summary Экспорт книг в CSV или XLSX
+// @Description Выгружает весь каталог или книги под фильтром (те же параметры, что у /books/search)
+// @Description колонками id, title, author, year, genre, isbn.
+// @Tags books
+// @Produce text/csv
+// @Produce octet-stream
+// @Param format query string true "csv или xlsx"
+// @Param q query string false "Полнотекстовый запрос"
+// @Param title query string false "Название книги (подстрока)"
+// @Param author query string false "Автор (подстрока)"
+// @Param authorId query string false "ID автора из справочника (любая роль)"
+// @Param genre query []string false "Жанры: ID или название из рубрикатора, вместе с поджанрами (можно несколько)" collectionFormat(multi)
+// @Param isbn query string false "ISBN или его часть, с дефисами или без"
+// @Param decade query int false "Десятилетие издания (1990 — годы 1990-1999)"
+// @Param available query bool false "Только книги со свободными экземплярами"
+// @Param withdrawn query string false "Списанные книги: include — показать вместе с остальными, only — только их"
+// @Success 200 {file} file "Таблица книг"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /books/export/spreadsheet [get]
+func (h *SheetHandler) ExportBooks(c *gin.Context) {
+	format := c.Query("format")
+	if !sheet.IsFormat(format) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "format must be csv or xlsx"})
+		return
+	}
+	filter, ok := bookFilter(c)
+	if !ok {
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == sheet.FormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="books.`+format+`"`)
+	c.Status(http.StatusOK)
+
+	if _, err := h.sheetUC.ExportBooks(c.Request.Context(), filter, format, c.Writer); err != nil {
+		// Выгрузка уже началась — статус не поменять, ответ просто обрывается
+		if c.Writer.Written() {
+			_ = c.Error(err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		if errors.Is(err, customErr.ErrInvalidID) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid author ID"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
+
+func sheetImportError(c *gin.Context, err error) {
+	if uploadFailed(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, customErr.ErrInvalidSpreadsheet), errors.Is(err, customErr.ErrColumnMapping):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type TwoFactorHandler struct {
+	twoFactorUC usecase.TwoFactorUC
+}
+
+func NewTwoFactorHandler(twoFactorUC usecase.TwoFactorUC) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorUC: twoFactorUC}
+}
+
+// CompleteLogin godoc
+// @Summary Второй шаг входа: код из приложения или код восстановления
+// @Description Для challenge типа "enroll" код подтверждает привязку, начатую через /users/login/2fa/enroll; в ответе приходят коды восстановления
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorLoginRequest true "Challenge-токен и код"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Header 429 {integer} Retry-After "Секунд до снятия временной блокировки"
+// @Router /users/login/2fa [post]
+func (h *TwoFactorHandler) CompleteLogin(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	resp, err := h.twoFactorUC.CompleteLogin(c.Request.Context(), dto.TwoFactorLoginInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		RecoveryCode:   req.RecoveryCode,
+		Client:         clientInfo(c),
+	})
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// BeginLoginEnrollment godoc
+// @Summary Привязать аутентификатор при входе (роль требует 2FA)
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body dto.ChallengeRequest true "Challenge-токен типа enroll"
+// @Success 200 {object} dto.TwoFactorEnrollment
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /users/login/2fa/enroll [post]
+func (h *TwoFactorHandler) BeginLoginEnrollment(c *gin.Context) {
+	var req dto.ChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	enrollment, err := h.twoFactorUC.BeginLoginEnrollment(c.Request.Context(), req.ChallengeToken)
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// GetStatus godoc
+// @Summary Состояние 2FA текущего пользователя
+// @Tags 2fa
+// @Produce json
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.TwoFactorStatus
+// @Failure 401 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorUC.GetStatus(c.Request.Context())
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, status)
+}
+
+// BeginEnrollment godoc
+// @Summary Начать привязку аутентификатора
+// @Description Возвращает секрет и otpauth:// URI для QR-кода; 2FA включается после /users/me/2fa/confirm
+// @Tags 2fa
+// @Produce json
+// @Success 200 {object} dto.TwoFactorEnrollment
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa/enroll [post]
+func (h *TwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	enrollment, err := h.twoFactorUC.BeginEnrollment(c.Request.Context())
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmEnrollment godoc
+// @Summary Подтвердить привязку первым кодом и получить коды восстановления
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorCodeRequest true "Код из приложения"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa/confirm [post]
+func (h *TwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	codes, err := h.twoFactorUC.ConfirmEnrollment(c.Request.Context(), req.Code)
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// Disable godoc
+// @Summary Отключить свою 2FA
+// @Description Недоступно, если 2FA обязательна для роли
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorCodeRequest true "Код из приложения или код восстановления"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/2fa [delete]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	if err := h.twoFactorUC.Disable(c.Request.Context(), req); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "disabled"})
+}
+
+// ResetUser godoc
+// @Summary Сбросить аутентификатор пользователя (потеря устройства)
+// @Description Удаляет привязку и завершает все сессии пользователя
+// @Tags 2fa
+// @Produce json
+// @Param id path string true "ID пользователя"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/{id}/2fa [delete]
+func (h *TwoFactorHandler) ResetUser(c *gin.Context) {
+	if err := h.twoFactorUC.ResetUser(c.Request.Context(), c.Param("id")); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "reset"})
+}
+
+// GetPolicy godoc
+// @Summary Роли с обязательной 2FA
+// @Tags 2fa
+// @Produce json
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.TwoFactorPolicy
+// @Security BearerAuth
+// @Router /settings/2fa [get]
+func (h *TwoFactorHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.twoFactorUC.GetPolicy(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, policy)
+}
+
+// SetPolicy godoc
+// @Summary Задать роли с обязательной 2FA
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body dto.TwoFactorPolicy true "Роли (admin, librarian, reader)"
+// @Success 200 {object} dto.TwoFactorPolicy
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /settings/2fa [put]
+func (h *TwoFactorHandler) SetPolicy(c *gin.Context) {
+	var req dto.TwoFactorPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	policy, err := h.twoFactorUC.SetPolicy(c.Request.Context(), req.Roles)
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// twoFactorError — ответы на ошибки 2FA
+func twoFactorError(c *gin.Context, err error) {
+	var lockout *customErr.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		retryAfter := int(math.Ceil(lockout.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{Error: "too many failed login attempts", Code: dto.CodeLoginLocked})
+	case errors.Is(err, customErr.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid or expired challenge token", Code: dto.CodeInvalidToken})
+	case errors.Is(err, customErr.ErrUnauthorized):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "authorization required", Code: dto.CodeAuthRequired})
+	case errors.Is(err, customErr.ErrCodeInvalid):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid code", Code: dto.CodeInvalidCode})
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid role"})
+	case errors.Is(err, customErr.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "user not found"})
+	case errors.Is(err, customErr.ErrUserBlocked):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "user is blocked"})
+	case errors.Is(err, customErr.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error(), Code: dto.CodeTwoFactorNeeded})
+	case errors.Is(err, customErr.ErrTwoFactorEnabled):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error(), Code: dto.CodeTwoFactorEnabled})
+	case errors.Is(err, customErr.ErrTwoFactorNotEnabled):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error(), Code: dto.CodeTwoFactorMissing})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+	"strings"
+)
+
+// maxUpload — предельный размер загружаемого файла каталога
+const maxUpload = 64 << 20
+
+// uploadReader — файл из поля file формы multipart/form-data или тело запроса целиком
+func uploadReader(c *gin.Context) (io.ReadCloser, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUpload)
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return header.Open()
+}
+
+// uploadFailed — ответ на ошибку чтения загрузки; false — ошибка не связана с загрузкой
+func uploadFailed(c *gin.Context, err error) bool {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: "file is too large"})
+	case errors.Is(err, http.ErrMissingFile):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "file required"})
+	default:
+		return false
+	}
+	return true
+}
@@ -7,7 +7,10 @@ import (
 	customErr "library-Mongo/internal/errors"
 	"library-Mongo/internal/usecase"
 	"library-Mongo/internal/usecase/dto"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type UserHandler struct {
@@ -24,10 +27,12 @@ func NewUserHandler(userUC usecase.UserUC) *UserHandler {
 // @Accept json
 // @Produce json
 // @Param input body dto.RegisterUserInput true "Данные пользователя"
-// @Success 200 {object} domain.User
+// @Success 200 {object} dto.UserResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
-// @Router /users/register [post]
+// @Router /users [post]
 func (h *UserHandler) RegisterUser(c *gin.Context) {
 	var input dto.RegisterUserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -36,7 +41,18 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 	}
 	user, err := h.userUC.RegisterUser(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		switch {
+		case errors.Is(err, customErr.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid role"})
+		case errors.Is(err, customErr.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, customErr.ErrPhoneTaken):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "phone is already registered"})
+		case isForbidden(err):
+			forbidden(c, err)
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -48,10 +64,12 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param credentials body dto.LoginRequest true "Телефон и пароль"
-// @Success 200 {object} domain.User
+// @Success 200 {object} dto.LoginResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Header 429 {integer} Retry-After "Секунд до снятия временной блокировки"
 // @Router /users/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -59,19 +77,30 @@ func (h *UserHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
 		return
 	}
-	user, err := h.userUC.Login(c.Request.Context(), req.Phone, req.Password)
+	resp, err := h.userUC.Login(c.Request.Context(), dto.LoginInput{
+		Phone:    req.Phone,
+		Password: req.Password,
+		Client:   clientInfo(c),
+	})
 	if err != nil {
+		var lockout *customErr.LockoutError
 		switch {
+		case errors.As(err, &lockout):
+			retryAfter := int(math.Ceil(lockout.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{Error: "too many failed login attempts", Code: dto.CodeLoginLocked})
 		case errors.Is(err, customErr.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "user not found"})
 		case errors.Is(err, customErr.ErrUserBlocked):
 			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "user is blocked"})
+		case errors.Is(err, customErr.ErrPhoneNotVerified):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "phone is not verified", Code: dto.CodePhoneNotVerified})
 		default:
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
 		}
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, resp)
 }
 
 // GetUserByID godoc
@@ -79,9 +108,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 // @Tags users
 // @Produce json
 // @Param id path string true "ID пользователя"
-// @Success 200 {object} domain.User
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.UserResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /users/{id} [get]
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	id := c.Param("id")
@@ -90,6 +122,8 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		switch {
 		case errors.Is(err, customErr.ErrInvalidID):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+		case isForbidden(err):
+			forbidden(c, err)
 		case errors.Is(err, customErr.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "user not found"})
 		default:
@@ -97,7 +131,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		}
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	respond(c, http.StatusOK, user)
 }
 
 // SearchUsers godoc
@@ -108,10 +142,23 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Param phone query string false "Телефон"
 // @Param role query string false "Роль"
 // @Param onlyActive query boolean false "Только активные"
-// @Success 200 {array} domain.User
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: fullName (по умолчанию), phone, registeredAt; \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.UserResponse
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Header 200 {string} X-Did-You-Mean "По запросу ничего не нашлось, выдача — по исправленному: query=..."
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /users/search [get]
 func (h *UserHandler) SearchUsers(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
 	q := c.Query("query")
 
 	filter := domain.UserFilter{
@@ -123,24 +170,37 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 		val := activeStr == "true"
 		filter.OnlyActive = &val
 	}
-	users, err := h.userUC.SearchUsers(c.Request.Context(), filter)
+	users, suggestion, err := h.userUC.SearchUsers(c.Request.Context(), filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
 		return
 	}
-	c.JSON(http.StatusOK, users)
+	// ФИО, телефон и роль ищутся одним параметром query
+	if name, ok := suggestion["fullName"]; ok {
+		suggestion = dto.SearchSuggestion{"query": name}
+	}
+	setSearchHeaders(c, users.Total, users.Next, suggestion)
+	respond(c, http.StatusOK, users.Items)
 }
 
 // UpdateUser godoc
 // @Summary Обновление пользователя
+// @Description Новый телефон, указанный самим читателем, подтверждается кодом (POST /users/verify-phone);
+// @Description до подтверждения вход по нему недоступен.
+// @Description Блокировка, смена пароля или роли завершает все сессии пользователя
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param input body dto.UpdateUserInput true "Данные обновления"
 // @Success 200 {object} dto.StatusResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /users [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	var input dto.UpdateUserInput
@@ -152,6 +212,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		switch {
 		case errors.Is(err, customErr.ErrInvalidID):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+		case errors.Is(err, customErr.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid role"})
+		case errors.Is(err, customErr.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		case errors.Is(err, customErr.ErrPhoneTaken):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "phone is already registered"})
+		case isForbidden(err):
+			forbidden(c, err)
 		case errors.Is(err, customErr.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "user not found"})
 		default:
@@ -169,7 +237,9 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Param id path string true "ID пользователя"
 // @Success 200 {object} dto.StatusResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
@@ -177,6 +247,8 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		switch {
 		case errors.Is(err, customErr.ErrInvalidID):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+		case errors.Is(err, customErr.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "user not found"})
 		default:
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
 		}
@@ -185,4 +257,52 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.StatusResponse{Status: "deleted"})
 }
 
+// ListLockoutEvents godoc
+// @Summary Журнал временных блокировок входа
+// @Tags users
+// @Produce json
+// @Param since query string false "Не раньше даты (YYYY-MM-DD или RFC3339)"
+// @Param value query string false "Телефон или IP"
+// @Param limit query int false "Максимум записей (по умолчанию 100)"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} domain.LockoutEvent
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /users/lockouts [get]
+func (h *UserHandler) ListLockoutEvents(c *gin.Context) {
+	filter := domain.LockoutEventFilter{Value: c.Query("value")}
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		since, err := parseDateTime(sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid since"})
+			return
+		}
+		filter.Since = since
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.userUC.ListLockoutEvents(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	respond(c, http.StatusOK, events)
+}
+
+// parseDateTime принимает дату YYYY-MM-DD или время RFC3339
+func parseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
 // BlockUser / UnblockUser — можешь оформить аналогично по схеме UpdateUser.
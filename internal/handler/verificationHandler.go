@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type VerificationHandler struct {
+	verificationUC usecase.VerificationUC
+}
+
+func NewVerificationHandler(verificationUC usecase.VerificationUC) *VerificationHandler {
+	return &VerificationHandler{verificationUC: verificationUC}
+}
+
+// RequestPasswordReset godoc
+// @Summary Запросить код сброса пароля
+// @Description Ответ одинаковый для известных и неизвестных телефонов
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body dto.PhoneRequest true "Телефон"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/password/reset/request [post]
+func (h *VerificationHandler) RequestPasswordReset(c *gin.Context) {
+	var req dto.PhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Phone == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	if err := h.verificationUC.RequestPasswordReset(c.Request.Context(), req.Phone); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "code sent"})
+}
+
+// VerifyPasswordReset godoc
+// @Summary Проверить код сброса пароля
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body dto.PhoneCodeRequest true "Телефон и код"
+// @Success 200 {object} dto.ResetTicketResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/password/reset/verify [post]
+func (h *VerificationHandler) VerifyPasswordReset(c *gin.Context) {
+	var req dto.PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	ticket, err := h.verificationUC.VerifyPasswordReset(c.Request.Context(), req.Phone, req.Code)
+	if err != nil {
+		codeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ticket)
+}
+
+// ResetPassword godoc
+// @Summary Установить новый пароль по токену сброса
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body dto.ResetPasswordInput true "Токен сброса и новый пароль"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/password/reset [post]
+func (h *VerificationHandler) ResetPassword(c *gin.Context) {
+	var input dto.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	if err := h.verificationUC.ResetPassword(c.Request.Context(), input); err != nil {
+		switch {
+		case errors.Is(err, customErr.ErrInvalidToken):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid or expired reset token", Code: dto.CodeInvalidToken})
+		case errors.Is(err, customErr.ErrWeakPassword):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "password changed"})
+}
+
+// VerifyPhone godoc
+// @Summary Подтвердить телефон кодом из SMS
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body dto.PhoneCodeRequest true "Телефон и код"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/verify-phone [post]
+func (h *VerificationHandler) VerifyPhone(c *gin.Context) {
+	var req dto.PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	if err := h.verificationUC.VerifyPhone(c.Request.Context(), req.Phone, req.Code); err != nil {
+		codeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "verified"})
+}
+
+// ResendPhoneVerification godoc
+// @Summary Повторно отправить код подтверждения телефона
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body dto.PhoneRequest true "Телефон"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/verify-phone/resend [post]
+func (h *VerificationHandler) ResendPhoneVerification(c *gin.Context) {
+	var req dto.PhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Phone == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	if err := h.verificationUC.ResendPhoneVerification(c.Request.Context(), req.Phone); err != nil {
+		codeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "code sent"})
+}
+
+// codeError — ответы на ошибки проверки одноразового кода
+func codeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrCodeInvalid):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid or expired code", Code: dto.CodeInvalidCode})
+	case errors.Is(err, customErr.ErrCodeAttempts):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error(), Code: dto.CodeCodeAttempts})
+	case errors.Is(err, customErr.ErrCodeCooldown):
+		c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
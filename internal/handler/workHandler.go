@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/usecase"
+	"library-Mongo/internal/usecase/dto"
+	"net/http"
+)
+
+type WorkHandler struct {
+	workUC usecase.WorkUC
+}
+
+func NewWorkHandler(workUC usecase.WorkUC) *WorkHandler {
+	return &WorkHandler{workUC: workUC}
+}
+
+// CreateWork godoc
+// @Summary Добавить произведение
+// @Description Книги из bookIds сразу становятся изданиями произведения (как при PUT /books с workId)
+// @Tags works
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateWorkInput true "Название, автор, год и издания"
+// @Success 201 {object} dto.WorkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works [post]
+func (h *WorkHandler) CreateWork(c *gin.Context) {
+	var input dto.CreateWorkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	work, err := h.workUC.CreateWork(c.Request.Context(), input)
+	if err != nil {
+		workError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, work)
+}
+
+// SearchWorks godoc
+// @Summary Поиск произведений
+// @Tags works
+// @Produce json
+// @Param title query string false "Название (подстрока или слова)"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: title (по умолчанию), author, year; \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.WorkResponse
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works [get]
+func (h *WorkHandler) SearchWorks(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	works, err := h.workUC.SearchWorks(c.Request.Context(), c.Query("title"), page)
+	if err != nil {
+		if !pageError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	setPageHeaders(c, works.Total, works.Next)
+	respond(c, http.StatusOK, works.Items)
+}
+
+// GetWork godoc
+// @Summary Получить произведение
+// @Description Издания произведения — GET /books/search?workId=...
+// @Tags works
+// @Produce json
+// @Param id path string true "ID произведения"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.WorkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works/{id} [get]
+func (h *WorkHandler) GetWork(c *gin.Context) {
+	work, err := h.workUC.GetWork(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		workError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, work)
+}
+
+// UpdateWork godoc
+// @Summary Изменить произведение
+// @Tags works
+// @Accept json
+// @Produce json
+// @Param id path string true "ID произведения"
+// @Param input body dto.UpdateWorkInput true "Обновляемые поля"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works/{id} [put]
+func (h *WorkHandler) UpdateWork(c *gin.Context) {
+	var input dto.UpdateWorkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid input"})
+		return
+	}
+	input.ID = c.Param("id")
+
+	if err := h.workUC.UpdateWork(c.Request.Context(), input); err != nil {
+		workError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "updated"})
+}
+
+// DeleteWork godoc
+// @Summary Удалить произведение
+// @Description Удаляется только произведение, на которое не ссылается ни одна книга
+// @Tags works
+// @Produce json
+// @Param id path string true "ID произведения"
+// @Success 200 {object} dto.StatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works/{id} [delete]
+func (h *WorkHandler) DeleteWork(c *gin.Context) {
+	if err := h.workUC.DeleteWork(c.Request.Context(), c.Param("id")); err != nil {
+		workError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatusResponse{Status: "deleted"})
+}
+
+// WorkBorrows godoc
+// @Summary История выдач произведения
+// @Description Выдачи всех изданий произведения, включая списанные
+// @Tags works
+// @Produce json
+// @Param id path string true "ID произведения"
+// @Param limit query int false "Размер страницы (1-200, по умолчанию 50)"
+// @Param after query string false "Курсор следующей страницы из заголовка Link"
+// @Param sort query string false "Сортировка: borrowedAt (по умолчанию); \"-\" в начале — по убыванию"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {array} dto.BorrowHistoryItem
+// @Header 200 {integer} X-Total-Count "Всего записей по фильтру"
+// @Header 200 {string} Link "Ссылка на следующую страницу (rel=\"next\")"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works/{id}/borrows [get]
+func (h *WorkHandler) WorkBorrows(c *gin.Context) {
+	page, ok := pageRequest(c)
+	if !ok {
+		return
+	}
+	borrows, err := h.workUC.WorkBorrows(c.Request.Context(), c.Param("id"), page)
+	if err != nil {
+		if !pageError(c, err) {
+			workError(c, err)
+		}
+		return
+	}
+	setPageHeaders(c, borrows.Total, borrows.Next)
+	respond(c, http.StatusOK, borrows.Items)
+}
+
+// WorkStats godoc
+// @Summary Статистика выдач произведения
+// @Description Выдачи, невозвращённые выдачи и читатели по каждому изданию и в целом по произведению.
+// @Description Читатель, бравший несколько изданий, в uniqueReaders считается один раз
+// @Tags works
+// @Produce json
+// @Param id path string true "ID произведения"
+// @Param fields query string false "Поля ответа через запятую (sparse fieldset)"
+// @Success 200 {object} dto.WorkStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /works/{id}/stats [get]
+func (h *WorkHandler) WorkStats(c *gin.Context) {
+	stats, err := h.workUC.WorkStats(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		workError(c, err)
+		return
+	}
+	respond(c, http.StatusOK, stats)
+}
+
+func workError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, customErr.ErrInvalidID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid ID"})
+	case errors.Is(err, customErr.ErrWorkTitleRequired):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, customErr.ErrWorkNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "work not found"})
+	case errors.Is(err, customErr.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "book not found"})
+	case errors.Is(err, customErr.ErrWorkInUse):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal error"})
+	}
+}
@@ -0,0 +1,97 @@
+// Package imaging — проверка загруженных изображений и миниатюры обложек
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // регистрация декодеров для image.Decode
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels — предел ширина×высота: маленький файл может разворачиваться в огромный растр
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupported = errors.New("unsupported image format")
+	ErrTooLarge    = errors.New("image dimensions are too large")
+)
+
+// Типы, которые принимаются как обложка
+var contentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Info — тип и размеры изображения
+type Info struct {
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Inspect определяет тип по содержимому (заголовок Content-Type клиента не учитывается)
+// и читает размеры без декодирования всего растра
+func Inspect(data []byte) (Info, error) {
+	contentType := http.DetectContentType(data)
+	if !contentTypes[contentType] {
+		return Info{}, fmt.Errorf("%w: %s", ErrUnsupported, contentType)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Info{}, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return Info{}, fmt.Errorf("%w: empty image", ErrUnsupported)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return Info{}, ErrTooLarge
+	}
+	return Info{ContentType: contentType, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Thumbnails декодирует изображение один раз и возвращает JPEG для каждой стороны из sides.
+// Миниатюра вписывается в квадрат side×side с сохранением пропорций и не увеличивается;
+// прозрачные области заливаются белым
+func Thumbnails(data []byte, sides map[string]int) (map[string][]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	res := make(map[string][]byte, len(sides))
+	for name, side := range sides {
+		w, h := fit(src.Bounds().Dx(), src.Bounds().Dy(), side)
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+			return nil, fmt.Errorf("encode %s: %w", name, err)
+		}
+		res[name] = buf.Bytes()
+	}
+	return res, nil
+}
+
+// fit — размеры w×h, вписанные в квадрат side×side
+func fit(w, h, side int) (int, int) {
+	if w <= side && h <= side {
+		return w, h
+	}
+	if w >= h {
+		return side, max(1, h*side/w)
+	}
+	return max(1, w*side/h), side
+}
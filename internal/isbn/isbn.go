@@ -0,0 +1,107 @@
+// Package isbn — проверка и нормализация ISBN-10/ISBN-13
+package isbn
+
+import (
+	"strings"
+
+	customErr "library-Mongo/internal/errors"
+)
+
+// Clean убирает дефисы и пробелы, "x" приводит к верхнему регистру
+func Clean(raw string) string {
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteRune('X')
+		case r == '-' || r == ' ':
+		default:
+			// Посторонний символ оставляем — Normalize его отвергнет
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Normalize проверяет контрольную цифру и возвращает ISBN-13 без дефисов.
+// ISBN-10 переводится в ISBN-13 с префиксом 978.
+func Normalize(raw string) (string, error) {
+	s := Clean(raw)
+	switch len(s) {
+	case 10:
+		if !valid10(s) {
+			return "", customErr.ErrInvalidISBN
+		}
+		return to13(s), nil
+	case 13:
+		if !valid13(s) {
+			return "", customErr.ErrInvalidISBN
+		}
+		return s, nil
+	}
+	return "", customErr.ErrInvalidISBN
+}
+
+// To10 — ISBN-10 для ISBN-13 с префиксом 978; для 979 его не существует
+func To10(isbn13 string) string {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") {
+		return ""
+	}
+	body := isbn13[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X"
+	}
+	return body + string(rune('0'+check))
+}
+
+func valid10(s string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			d = int(s[i] - '0')
+		case s[i] == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += d * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+func valid13(s string) bool {
+	sum := 0
+	for i := 0; i < 13; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+		d := int(s[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func to13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return body + string(rune('0'+(10-sum%10)%10))
+}
@@ -0,0 +1,100 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+
+	customErr "library-Mongo/internal/errors"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string // "" — ErrInvalidISBN
+	}{
+		{name: "ISBN-13", raw: "9780306406157", want: "9780306406157"},
+		{name: "ISBN-13 with hyphens", raw: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "ISBN-13 with spaces", raw: "978 0 306 40615 7", want: "9780306406157"},
+		{name: "ISBN-13 979", raw: "979-10-90636-07-1", want: "9791090636071"},
+		{name: "ISBN-10", raw: "0306406152", want: "9780306406157"},
+		{name: "ISBN-10 with hyphens", raw: "0-306-40615-2", want: "9780306406157"},
+		{name: "ISBN-10 check digit X", raw: "0-8044-2957-X", want: "9780804429573"},
+		{name: "ISBN-10 lower-case x", raw: "3 16 148410 x", want: "9783161484100"},
+		{name: "ISBN-10 check digit 1", raw: "0-19-853453-1", want: "9780198534532"},
+
+		{name: "ISBN-13 wrong check digit", raw: "978-0-306-40615-8", want: ""},
+		{name: "ISBN-10 wrong check digit", raw: "0-306-40615-3", want: ""},
+		{name: "ISBN-10 X not last", raw: "08044295X7", want: ""},
+		{name: "ISBN-10 digit instead of X", raw: "0-8044-2957-0", want: ""},
+		{name: "ISBN-13 with X", raw: "978080442957X", want: ""},
+		{name: "letters", raw: "978-0-306-4O615-7", want: ""},
+		{name: "too short", raw: "030640615", want: ""},
+		{name: "too long", raw: "97803064061570", want: ""},
+		{name: "empty", raw: "", want: ""},
+		{name: "only separators", raw: " - - ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.raw)
+			if tt.want == "" {
+				if !errors.Is(err, customErr.ErrInvalidISBN) {
+					t.Errorf("Normalize(%q) = %q, %v; want ErrInvalidISBN", tt.raw, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		isbn13 string
+		want   string
+	}{
+		{"9780306406157", "0306406152"},
+		{"9780804429573", "080442957X"},
+		{"9783161484100", "316148410X"},
+		{"9780198534532", "0198534531"},
+		// Для 979 ISBN-10 не существует
+		{"9791090636071", ""},
+		{"978030640615", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := To10(tt.isbn13); got != tt.want {
+			t.Errorf("To10(%q) = %q, want %q", tt.isbn13, got, tt.want)
+		}
+	}
+}
+
+// ISBN-10 -> ISBN-13 -> ISBN-10 возвращает исходный номер
+func TestRoundTrip(t *testing.T) {
+	for _, isbn10 := range []string{"0306406152", "080442957X", "316148410X", "0198534531", "0000000000"} {
+		isbn13, err := Normalize(isbn10)
+		if err != nil {
+			t.Fatalf("Normalize(%q): %v", isbn10, err)
+		}
+		if got := To10(isbn13); got != isbn10 {
+			t.Errorf("To10(Normalize(%q)) = %q", isbn10, got)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"978-0-306-40615-7", "9780306406157"},
+		{" 0 8044 2957 x ", "080442957X"},
+		{"ISBN 0306406152", "ISBN0306406152"},
+	}
+	for _, tt := range tests {
+		if got := Clean(tt.raw); got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Разделители ISO 2709
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+
+	leaderLen   = 24
+	dirEntryLen = 12
+)
+
+// ISO2709Reader читает записи двоичного MARC21 по одной
+type ISO2709Reader struct {
+	r *bufio.Reader
+}
+
+func NewISO2709Reader(r io.Reader) *ISO2709Reader {
+	return &ISO2709Reader{r: bufio.NewReader(r)}
+}
+
+// Next возвращает следующую запись или io.EOF. После ErrMalformed можно читать дальше:
+// длина записи берётся из маркера.
+func (rd *ISO2709Reader) Next() (*Record, error) {
+	// Между записями в файлах встречаются переводы строк
+	for {
+		b, err := rd.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != '\n' && b != '\r' {
+			_ = rd.r.UnreadByte()
+			break
+		}
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(rd.r, head); err != nil {
+		return nil, fmt.Errorf("%w: truncated leader: %w", ErrBadStream, err)
+	}
+	size, ok := digits(head)
+	if !ok || size < leaderLen+1 {
+		return nil, fmt.Errorf("%w: bad record length %q", ErrBadStream, head)
+	}
+	raw := make([]byte, size)
+	copy(raw, head)
+	if _, err := io.ReadFull(rd.r, raw[5:]); err != nil {
+		return nil, fmt.Errorf("%w: truncated record: %w", ErrBadStream, err)
+	}
+	return parseISO2709(raw)
+}
+
+func parseISO2709(raw []byte) (*Record, error) {
+	leader := raw[:leaderLen]
+	base, ok := digits(leader[12:17])
+	if !ok || base <= leaderLen || base > len(raw) {
+		return nil, fmt.Errorf("%w: bad base address", ErrMalformed)
+	}
+	// MARC-8 (пробел в позиции 9) не перекодируем: принимаем только то, что уже является UTF-8
+	if leader[9] != 'a' && !utf8.Valid(raw) {
+		return nil, fmt.Errorf("%w: MARC-8 encoding is not supported, convert the file to UTF-8", ErrMalformed)
+	}
+
+	rec := &Record{Leader: string(leader)}
+	directory := bytes.TrimSuffix(raw[leaderLen:base], []byte{fieldTerminator})
+	if len(directory)%dirEntryLen != 0 {
+		return nil, fmt.Errorf("%w: bad directory", ErrMalformed)
+	}
+	for i := 0; i < len(directory); i += dirEntryLen {
+		entry := directory[i : i+dirEntryLen]
+		tag := string(entry[:3])
+		length, ok1 := digits(entry[3:7])
+		start, ok2 := digits(entry[7:12])
+		if !ok1 || !ok2 || length < 1 || base+start+length > len(raw) {
+			return nil, fmt.Errorf("%w: bad directory entry for %s", ErrMalformed, tag)
+		}
+		data := bytes.TrimSuffix(raw[base+start:base+start+length], []byte{fieldTerminator})
+
+		if isControlTag(tag) {
+			rec.AddControl(tag, string(data))
+			continue
+		}
+		if len(data) < 2 {
+			return nil, fmt.Errorf("%w: field %s has no indicators", ErrMalformed, tag)
+		}
+		field := DataField{Tag: tag, Ind1: data[0], Ind2: data[1]}
+		for _, part := range bytes.Split(data[2:], []byte{subfieldDelimiter}) {
+			if len(part) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+		}
+		rec.Data = append(rec.Data, field)
+	}
+	return rec, nil
+}
+
+// digits разбирает числовое поле маркера или справочника: только цифры ASCII, без знака и пробелов.
+// strconv.Atoi пропустил бы "-001", и отрицательные смещения вывели бы срез за границы записи
+func digits(b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
@@ -0,0 +1,74 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// buildRecord собирает запись ISO 2709 из готовых записей справочника и области данных
+func buildRecord(directory []string, data string) []byte {
+	dir := ""
+	for _, e := range directory {
+		dir += e
+	}
+	dir += string(rune(fieldTerminator))
+	base := leaderLen + len(dir)
+	size := base + len(data) + 1
+	leader := fmt.Sprintf("%05dnam a22%05d   4500", size, base)
+	return []byte(leader + dir + data + string(rune(recordTerminator)))
+}
+
+func TestISO2709MalformedDirectory(t *testing.T) {
+	field := "00\x1faTitle\x1e" // 10 байт
+	valid := buildRecord([]string{"245001000000"}, field)
+
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{name: "negative length", entry: "245-01000000"},
+		{name: "negative start", entry: "2450010-0005"},
+		{name: "signed start", entry: "2450010+0005"},
+		{name: "zero length", entry: "245000000000"},
+		{name: "spaces", entry: "245 10000000"},
+		{name: "past end", entry: "245001000005"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := append(buildRecord([]string{tt.entry}, field), valid...)
+			rd := NewISO2709Reader(bytes.NewReader(stream))
+
+			if _, err := rd.Next(); !errors.Is(err, ErrMalformed) {
+				t.Fatalf("malformed entry %q: got %v, want ErrMalformed", tt.entry, err)
+			}
+			// Испорченная запись не мешает читать следующие
+			rec, err := rd.Next()
+			if err != nil {
+				t.Fatalf("next record: %v", err)
+			}
+			if len(rec.Data) != 1 || rec.Data[0].Tag != "245" {
+				t.Fatalf("next record: unexpected fields %+v", rec.Data)
+			}
+			if _, err := rd.Next(); err != io.EOF {
+				t.Fatalf("want io.EOF, got %v", err)
+			}
+		})
+	}
+}
+
+func TestISO2709MalformedLeader(t *testing.T) {
+	rec := buildRecord([]string{"245001000000"}, "00\x1faTitle\x1e")
+	copy(rec[12:17], "-0030")
+	if _, err := NewISO2709Reader(bytes.NewReader(rec)).Next(); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("negative base address: got %v, want ErrMalformed", err)
+	}
+
+	rec = buildRecord([]string{"245001000000"}, "00\x1faTitle\x1e")
+	copy(rec[:5], "-0050")
+	if _, err := NewISO2709Reader(bytes.NewReader(rec)).Next(); !errors.Is(err, ErrBadStream) {
+		t.Fatalf("negative record length: got %v, want ErrBadStream", err)
+	}
+}
@@ -0,0 +1,141 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Namespace — пространство имён MARCXML (MARC21 slim)
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName xml.Name          `xml:"record"`
+	Leader  string            `xml:"leader"`
+	Control []xmlControlField `xml:"controlfield"`
+	Data    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader читает элементы <record> из MARCXML — как внутри <collection>, так и одиночную запись
+type XMLReader struct {
+	dec *xml.Decoder
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{dec: xml.NewDecoder(r)}
+}
+
+// Next возвращает следующую запись или io.EOF; ошибка синтаксиса XML прерывает чтение (ErrBadStream)
+func (rd *XMLReader) Next() (*Record, error) {
+	for {
+		tok, err := rd.dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("%w: %w", ErrBadStream, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var x xmlRecord
+		if err := rd.dec.DecodeElement(&x, &start); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadStream, err)
+		}
+		rec := &Record{Leader: x.Leader}
+		for _, c := range x.Control {
+			rec.AddControl(c.Tag, c.Value)
+		}
+		for _, d := range x.Data {
+			field := DataField{Tag: d.Tag, Ind1: indicator(d.Ind1), Ind2: indicator(d.Ind2)}
+			for _, s := range d.Subfields {
+				if s.Code == "" {
+					continue
+				}
+				field.Subfields = append(field.Subfields, Subfield{Code: s.Code[0], Value: s.Value})
+			}
+			rec.Data = append(rec.Data, field)
+		}
+		return rec, nil
+	}
+}
+
+func indicator(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
+
+// XMLWriter пишет записи в <collection> по мере поступления. Заголовок выводится
+// с первой записью или при Close, так что до первой записи ответ ещё можно заменить ошибкой.
+type XMLWriter struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("  ", "  ")
+	return &XMLWriter{w: w, enc: enc}
+}
+
+func (wr *XMLWriter) start() error {
+	if wr.started {
+		return nil
+	}
+	wr.started = true
+	_, err := io.WriteString(wr.w, xml.Header+`<collection xmlns="`+Namespace+`">`+"\n")
+	return err
+}
+
+func (wr *XMLWriter) Write(rec *Record) error {
+	if err := wr.start(); err != nil {
+		return err
+	}
+	x := xmlRecord{Leader: rec.Leader}
+	for _, c := range rec.Control {
+		x.Control = append(x.Control, xmlControlField{Tag: c.Tag, Value: c.Value})
+	}
+	for _, d := range rec.Data {
+		field := xmlDataField{Tag: d.Tag, Ind1: string(d.Ind1), Ind2: string(d.Ind2)}
+		for _, s := range d.Subfields {
+			field.Subfields = append(field.Subfields, xmlSubfield{Code: string(s.Code), Value: s.Value})
+		}
+		x.Data = append(x.Data, field)
+	}
+	return wr.enc.Encode(x)
+}
+
+// Close закрывает <collection>; пустой экспорт — корректный пустой документ
+func (wr *XMLWriter) Close() error {
+	if err := wr.start(); err != nil {
+		return err
+	}
+	if err := wr.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(wr.w, "\n</collection>\n")
+	return err
+}
@@ -0,0 +1,167 @@
+// Package marc — чтение записей MARC21 (ISO 2709 и MARCXML) и запись в MARCXML
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrMalformed — запись не разбирается как MARC21; следующие записи читать можно
+	ErrMalformed = errors.New("malformed MARC record")
+	// ErrBadStream — поток повреждён (обрыв, неверная длина, синтаксис XML); чтение дальше невозможно
+	ErrBadStream = errors.New("unreadable MARC stream")
+)
+
+// Форматы входного потока
+const (
+	FormatISO2709 = "iso2709"
+	FormatXML     = "marcxml"
+)
+
+// Reader — источник записей; Next возвращает io.EOF после последней
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// NewReader открывает поток в формате format; пустой format — определить по первому символу
+// ("<" — MARCXML, иначе ISO 2709)
+func NewReader(r io.Reader, format string) (Reader, error) {
+	br := bufio.NewReader(r)
+	if format == "" {
+		format = detectFormat(br)
+	}
+	switch format {
+	case FormatXML:
+		return NewXMLReader(br), nil
+	case FormatISO2709:
+		return NewISO2709Reader(br), nil
+	}
+	return nil, fmt.Errorf("unsupported MARC format %q", format)
+}
+
+func detectFormat(br *bufio.Reader) string {
+	head, _ := br.Peek(512)
+	head = bytes.TrimPrefix(head, []byte("\xEF\xBB\xBF"))
+	if trimmed := bytes.TrimLeft(head, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '<' {
+		return FormatXML
+	}
+	return FormatISO2709
+}
+
+// DefaultLeader — маркер записи для экспорта: новая, текст, монография, UTF-8
+const DefaultLeader = "00000nam a2200000 a 4500"
+
+// Record — запись MARC21: маркер, управляющие поля (001-009) и поля данных
+type Record struct {
+	Leader  string
+	Control []ControlField
+	Data    []DataField
+}
+
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// ControlValue — значение первого управляющего поля с тегом tag
+func (r *Record) ControlValue(tag string) string {
+	for _, f := range r.Control {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Fields — поля данных с тегом tag в порядке записи
+func (r *Record) Fields(tag string) []DataField {
+	var res []DataField
+	for _, f := range r.Data {
+		if f.Tag == tag {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// AddControl добавляет управляющее поле
+func (r *Record) AddControl(tag, value string) {
+	r.Control = append(r.Control, ControlField{Tag: tag, Value: value})
+}
+
+// AddData добавляет поле данных; пары code/value с пустым значением пропускаются
+func (r *Record) AddData(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	var sf []Subfield
+	for _, s := range subfields {
+		if s.Value != "" {
+			sf = append(sf, s)
+		}
+	}
+	if len(sf) == 0 {
+		return
+	}
+	r.Data = append(r.Data, DataField{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: sf})
+}
+
+// Value — первое значение подполя code
+func (f DataField) Value(code byte) string {
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// Values — все значения подполя code
+func (f DataField) Values(code byte) []string {
+	var res []string
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			res = append(res, s.Value)
+		}
+	}
+	return res
+}
+
+// TrimPunct убирает пунктуацию ISBD на конце значения ("Война и мир /" -> "Война и мир")
+func TrimPunct(s string) string {
+	s = strings.TrimSpace(s)
+	for {
+		trimmed := strings.TrimRight(s, " /:;=,")
+		// Точку оставляем после инициала ("Толстой, Л. Н.")
+		if strings.HasSuffix(trimmed, ".") && !endsWithInitial(trimmed) {
+			trimmed = strings.TrimSuffix(trimmed, ".")
+		}
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
+
+func endsWithInitial(s string) bool {
+	word := s[strings.LastIndexAny(s, " ,")+1:]
+	return len([]rune(word)) == 2
+}
+
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
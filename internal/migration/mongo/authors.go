@@ -0,0 +1,101 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/domain"
+)
+
+// CreateAuthorsFromBooks заводит записи справочника авторов по строке author книг без участников
+// и делает автора участником книги в роли author. Одинаковые после нормализации имена сводятся
+// к одной записи. Повторный запуск безопасен: книги с участниками пропускаются.
+func CreateAuthorsFromBooks(ctx context.Context, db *mongo.Database) (int, error) {
+	books := db.Collection("books")
+	authors := db.Collection("authors")
+
+	filter := bson.M{
+		"author":       bson.M{"$nin": bson.A{nil, ""}},
+		"contributors": bson.M{"$in": bson.A{nil, bson.A{}}},
+	}
+	cursor, err := books.Find(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("CreateAuthorsFromBooks (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	linked := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			Author string             `bson:"author"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return linked, fmt.Errorf("CreateAuthorsFromBooks (decode): %w", err)
+		}
+		// Строка из одной пунктуации не даёт ключа — такую книгу оставляем без участников
+		name := strings.TrimSpace(doc.Author)
+		if domain.NameKey(name) == "" {
+			continue
+		}
+
+		contributor, err := authorContributor(ctx, authors, name)
+		if err != nil {
+			return linked, fmt.Errorf("CreateAuthorsFromBooks (author %s): %w", doc.ID.Hex(), err)
+		}
+
+		// Строку author не трогаем: она уже совпадает с написанием в книге
+		update := bson.M{"$set": bson.M{"contributors": []domain.Contributor{contributor}}}
+		if _, err := books.UpdateByID(ctx, doc.ID, update); err != nil {
+			return linked, fmt.Errorf("CreateAuthorsFromBooks (update %s): %w", doc.ID.Hex(), err)
+		}
+		linked++
+	}
+	if err := cursor.Err(); err != nil {
+		return linked, fmt.Errorf("CreateAuthorsFromBooks (cursor): %w", err)
+	}
+
+	log.Printf("CreateAuthorsFromBooks: linked %d books", linked)
+	return linked, nil
+}
+
+// authorContributor находит действующую запись по любому написанию имени или заводит новую
+func authorContributor(ctx context.Context, authors *mongo.Collection, name string) (domain.Contributor, error) {
+	key := domain.NameKey(name)
+
+	var found struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+	err := authors.FindOne(ctx, bson.M{"keys": key, "mergedInto": nil}).Decode(&found)
+	if err == nil {
+		return domain.Contributor{AuthorID: found.ID, Name: found.Name, Role: domain.RoleAuthor}, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Contributor{}, err
+	}
+
+	now := time.Now()
+	res, err := authors.InsertOne(ctx, bson.M{
+		"name":      name,
+		"keys":      bson.A{key},
+		"createdAt": now,
+		"updatedAt": now,
+	})
+	if err != nil {
+		return domain.Contributor{}, err
+	}
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return domain.Contributor{}, fmt.Errorf("inserted ID is not ObjectID")
+	}
+	return domain.Contributor{AuthorID: oid, Name: name, Role: domain.RoleAuthor}, nil
+}
@@ -0,0 +1,103 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/textsim"
+)
+
+// CreateGenresFromBooks заводит в рубрикаторе жанры по строке genre книг без ссылки на него
+// и привязывает к ним книги. Написания с одним textsim.Key ("фантастика", "Фантастика")
+// сводятся к одному жанру в корне рубрикатора; иерархию потом выстраивает библиотекарь.
+// Повторный запуск безопасен: книги со ссылкой на жанр пропускаются.
+func CreateGenresFromBooks(ctx context.Context, db *mongo.Database) (int, error) {
+	books := db.Collection("books")
+	genres := db.Collection("genres")
+
+	filter := bson.M{
+		"genre":   bson.M{"$nin": bson.A{nil, ""}},
+		"genreId": bson.M{"$exists": false},
+	}
+	cursor, err := books.Find(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("CreateGenresFromBooks (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	linked := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Genre string             `bson:"genre"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return linked, fmt.Errorf("CreateGenresFromBooks (decode): %w", err)
+		}
+		// Строка из одной пунктуации не даёт ключа — такая книга остаётся вне рубрикатора
+		label := strings.TrimSpace(doc.Genre)
+		if textsim.Key(label) == "" {
+			continue
+		}
+
+		id, label, err := genreByLabel(ctx, genres, label)
+		if err != nil {
+			return linked, fmt.Errorf("CreateGenresFromBooks (genre %s): %w", doc.ID.Hex(), err)
+		}
+
+		update := bson.M{"$set": bson.M{"genreId": id, "genre": label}}
+		if _, err := books.UpdateByID(ctx, doc.ID, update); err != nil {
+			return linked, fmt.Errorf("CreateGenresFromBooks (update %s): %w", doc.ID.Hex(), err)
+		}
+		linked++
+	}
+	if err := cursor.Err(); err != nil {
+		return linked, fmt.Errorf("CreateGenresFromBooks (cursor): %w", err)
+	}
+
+	log.Printf("CreateGenresFromBooks: linked %d books", linked)
+	return linked, nil
+}
+
+// genreByLabel находит жанр по названию или синониму либо заводит новый корневой жанр;
+// возвращает его ID и русское название
+func genreByLabel(ctx context.Context, genres *mongo.Collection, label string) (string, string, error) {
+	key := textsim.Key(label)
+
+	var found struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		LabelRU string             `bson:"labelRu"`
+	}
+	err := genres.FindOne(ctx, bson.M{"keys": key}).Decode(&found)
+	if err == nil {
+		return found.ID.Hex(), found.LabelRU, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return "", "", err
+	}
+
+	now := time.Now()
+	res, err := genres.InsertOne(ctx, bson.M{
+		"ancestors": bson.A{},
+		"labelRu":   label,
+		"keys":      bson.A{key},
+		"createdAt": now,
+		"updatedAt": now,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", "", fmt.Errorf("inserted ID is not ObjectID")
+	}
+	return oid.Hex(), label, nil
+}
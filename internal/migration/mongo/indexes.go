@@ -5,6 +5,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func CreateIndexes(db *mongo.Database) error {
@@ -12,7 +13,10 @@ func CreateIndexes(db *mongo.Database) error {
 
 	_, err := db.Collection("users").Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "fullName", Value: 1}}},
-		{Keys: bson.D{{Key: "phone", Value: 1}}},
+		// Телефон — логин: по нему находится ровно одна учётная запись
+		{Keys: bson.D{{Key: "phone", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "registeredAt", Value: 1}}},
+		{Keys: bson.D{{Key: "nameKeys", Value: 1}}},
 	})
 	if err != nil {
 		return err
@@ -25,6 +29,52 @@ func CreateIndexes(db *mongo.Database) error {
 			{Key: "author", Value: 1},
 			{Key: "title", Value: 1},
 		}},
+		{Keys: bson.D{{Key: "year", Value: 1}}},
+		// Книги без ISBN в индекс не попадают
+		{Keys: bson.D{{Key: "isbn13", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "isbn10", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "contributors.authorId", Value: 1}}},
+		{Keys: bson.D{{Key: "genreId", Value: 1}}},
+		{Keys: bson.D{{Key: "workId", Value: 1}}},
+		// Тома серии по порядку: ?seriesId=...&sort=volume и соседние тома на странице книги
+		{Keys: bson.D{
+			{Key: "series.seriesId", Value: 1},
+			{Key: "series.volume", Value: 1},
+		}},
+		// Ключи поиска (textsim.Latin): префиксные регулярные выражения ^... идут по индексу
+		{Keys: bson.D{{Key: "keys.title", Value: 1}}},
+		{Keys: bson.D{{Key: "keys.author", Value: 1}}},
+		// Полнотекстовый поиск: совпадение в названии весит больше, чем в авторе и жанре.
+		// languageOverride переименован, чтобы поле "language" в документе не меняло язык стемминга
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "author", Value: "text"},
+				{Key: "genre", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("books_text").
+				SetDefaultLanguage("russian").
+				SetLanguageOverride("textLanguage").
+				SetWeights(bson.D{
+					{Key: "title", Value: 10},
+					{Key: "author", Value: 5},
+					{Key: "genre", Value: 2},
+				}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("book_revisions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "bookId", Value: 1},
+				{Key: "number", Value: -1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
 	})
 	if err != nil {
 		return err
@@ -43,6 +93,138 @@ func CreateIndexes(db *mongo.Database) error {
 			{Key: "borrowedAt", Value: 1},
 			{Key: "clientId", Value: 1},
 		}},
+		{Keys: bson.D{
+			{Key: "itemId", Value: 1},
+			{Key: "returnedAt", Value: 1},
+		}},
+		// История и статистика выдач произведения по всем изданиям
+		{Keys: bson.D{
+			{Key: "bookId", Value: 1},
+			{Key: "borrowedAt", Value: 1},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("items").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "barcode", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{
+			{Key: "bookId", Value: 1},
+			{Key: "status", Value: 1},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("authors").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "name", Value: 1}}},
+		// Поиск по любому написанию имени
+		{Keys: bson.D{{Key: "keys", Value: 1}}},
+		{Keys: bson.D{{Key: "mergedInto", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("genres").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		// Одно написание — один жанр: иначе книга с таким жанром не знала бы, к какому узлу относится
+		{Keys: bson.D{{Key: "keys", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// Поиск по жанру захватывает потомков: все жанры, у которых он среди предков
+		{Keys: bson.D{{Key: "ancestors", Value: 1}}},
+		{Keys: bson.D{{Key: "parentId", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("works").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "keys", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("series").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "title", Value: 1}}},
+		{Keys: bson.D{{Key: "keys", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("sessions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "refreshHash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "usedHashes", Value: 1}}},
+		{Keys: bson.D{
+			{Key: "userId", Value: 1},
+			{Key: "revokedAt", Value: 1},
+		}},
+		// Истёкшие сессии удаляются самой Mongo
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Счётчики неудачных входов забываются по expireAt
+	_, err = db.Collection("login_attempts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expireAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("lockout_events").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(90 * 24 * 3600)},
+		{Keys: bson.D{
+			{Key: "value", Value: 1},
+			{Key: "createdAt", Value: -1},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("verification_codes").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "phone", Value: 1},
+			{Key: "purpose", Value: 1},
+			{Key: "createdAt", Value: -1},
+		}},
+		{Keys: bson.D{{Key: "ticketHash", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "expireAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("api_keys").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("audit_log").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "at", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "entityType", Value: 1},
+			{Key: "entityId", Value: 1},
+			{Key: "at", Value: -1},
+		}},
+		{Keys: bson.D{
+			{Key: "actor.userId", Value: 1},
+			{Key: "at", Value: -1},
+		}},
+		{Keys: bson.D{
+			{Key: "actor.apiKeyId", Value: 1},
+			{Key: "at", Value: -1},
+		}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "expireAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/domain"
+)
+
+// CreateItemsForBooks заводит по одному экземпляру каждой книге, у которой экземпляров ещё нет,
+// и привязывает к нему выдачи этой книги. Экземпляр книги с активной выдачей получает статус on_loan.
+// Повторный запуск безопасен: книги с экземплярами пропускаются.
+func CreateItemsForBooks(ctx context.Context, db *mongo.Database) (int, error) {
+	books := db.Collection("books")
+	items := db.Collection("items")
+	borrows := db.Collection("borrows")
+
+	cursor, err := books.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("CreateItemsForBooks (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	created := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return created, fmt.Errorf("CreateItemsForBooks (decode): %w", err)
+		}
+
+		n, err := items.CountDocuments(ctx, bson.M{"bookId": doc.ID})
+		if err != nil {
+			return created, fmt.Errorf("CreateItemsForBooks (count %s): %w", doc.ID.Hex(), err)
+		}
+		if n > 0 {
+			continue
+		}
+
+		active, err := borrows.CountDocuments(ctx, bson.M{"bookId": doc.ID, "returnedAt": nil})
+		if err != nil {
+			return created, fmt.Errorf("CreateItemsForBooks (borrows %s): %w", doc.ID.Hex(), err)
+		}
+		status := domain.ItemAvailable
+		if active > 0 {
+			status = domain.ItemOnLoan
+		}
+
+		res, err := items.InsertOne(ctx, bson.M{
+			"bookId":     doc.ID,
+			"barcode":    domain.DefaultBarcode(doc.ID.Hex(), 1),
+			"status":     status,
+			"location":   "",
+			"acquiredAt": doc.ID.Timestamp(),
+		})
+		if err != nil {
+			return created, fmt.Errorf("CreateItemsForBooks (insert %s): %w", doc.ID.Hex(), err)
+		}
+
+		// До появления экземпляров книга выдавалась целиком — все её выдачи относятся к этому экземпляру
+		_, err = borrows.UpdateMany(ctx,
+			bson.M{"bookId": doc.ID, "itemId": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"itemId": res.InsertedID}},
+		)
+		if err != nil {
+			return created, fmt.Errorf("CreateItemsForBooks (link borrows %s): %w", doc.ID.Hex(), err)
+		}
+		created++
+	}
+	if err := cursor.Err(); err != nil {
+		return created, fmt.Errorf("CreateItemsForBooks (cursor): %w", err)
+	}
+
+	log.Printf("CreateItemsForBooks: created %d items", created)
+	return created, nil
+}
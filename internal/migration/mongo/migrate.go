@@ -1,11 +1,48 @@
-// go run -tags migrate ./cmd/app
+// go run ./cmd/migrate
 
 package mongo
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/config"
 )
 
-func Migrate(db *mongo.Database) error {
-	return CreateIndexes(db)
+func Migrate(db *mongo.Database, cfg *config.Config) error {
+	// Уникальный индекс по телефону создаётся только без дубликатов
+	if _, err := ReportDuplicatePhones(context.TODO(), db); err != nil {
+		return err
+	}
+
+	if err := CreateIndexes(db); err != nil {
+		return err
+	}
+
+	if _, err := HashPlaintextPasswords(context.TODO(), db, cfg.BcryptCost); err != nil {
+		return err
+	}
+
+	if _, err := CreateItemsForBooks(context.TODO(), db); err != nil {
+		return err
+	}
+
+	if _, err := CreateAuthorsFromBooks(context.TODO(), db); err != nil {
+		return err
+	}
+
+	if _, err := CreateGenresFromBooks(context.TODO(), db); err != nil {
+		return err
+	}
+
+	if _, err := CreateRevisionsForBooks(context.TODO(), db); err != nil {
+		return err
+	}
+
+	if _, err := CreateSearchKeys(context.TODO(), db); err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/auth"
+)
+
+// HashPlaintextPasswords заменяет хэшем все пароли, ещё хранящиеся открытым текстом.
+// Повторный запуск безопасен: уже захэшированные записи не выбираются.
+func HashPlaintextPasswords(ctx context.Context, db *mongo.Database, cost int) (int, error) {
+	col := db.Collection("users")
+	hasher := auth.NewPasswordHasher(cost)
+
+	filter := bson.M{
+		"password": bson.M{
+			"$type": "string",
+			"$not":  primitive.Regex{Pattern: `^\$2[aby]\$`},
+		},
+	}
+	cursor, err := col.Find(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("HashPlaintextPasswords (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Password string             `bson:"password"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, fmt.Errorf("HashPlaintextPasswords (decode): %w", err)
+		}
+
+		hash, err := hasher.Hash(doc.Password)
+		if err != nil {
+			return updated, fmt.Errorf("HashPlaintextPasswords (hash %s): %w", doc.ID.Hex(), err)
+		}
+
+		// Условие на старое значение — не затираем пароль, сменённый во время миграции
+		_, err = col.UpdateOne(ctx,
+			bson.M{"_id": doc.ID, "password": doc.Password},
+			bson.M{"$set": bson.M{"password": hash}},
+		)
+		if err != nil {
+			return updated, fmt.Errorf("HashPlaintextPasswords (update %s): %w", doc.ID.Hex(), err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return updated, fmt.Errorf("HashPlaintextPasswords (cursor): %w", err)
+	}
+
+	log.Printf("HashPlaintextPasswords: hashed %d passwords", updated)
+	return updated, nil
+}
@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrDuplicatePhones — в users есть телефоны с несколькими учётными записями; уникальный индекс
+// по phone не создастся, пока библиотекарь не разведёт их вручную
+var ErrDuplicatePhones = errors.New("duplicate phones in users")
+
+// ReportDuplicatePhones печатает телефоны, на которые заведено несколько учётных записей
+// (вход по такому телефону выбирал случайную из них), и возвращает ErrDuplicatePhones.
+// Заодно удаляет прежний неуникальный индекс phone_1, чтобы CreateIndexes создал уникальный.
+// Повторный запуск безопасен
+func ReportDuplicatePhones(ctx context.Context, db *mongo.Database) (int, error) {
+	col := db.Collection("users")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$phone",
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("ReportDuplicatePhones (aggregate): %w", err)
+	}
+	var groups []struct {
+		Phone string               `bson:"_id"`
+		IDs   []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return 0, fmt.Errorf("ReportDuplicatePhones (decode): %w", err)
+	}
+	if len(groups) > 0 {
+		for _, g := range groups {
+			ids := make([]string, 0, len(g.IDs))
+			for _, id := range g.IDs {
+				ids = append(ids, id.Hex())
+			}
+			log.Printf("ReportDuplicatePhones: phone %q is used by users %s", g.Phone, strings.Join(ids, ", "))
+		}
+		return len(groups), fmt.Errorf("%w: %d phones, change or merge these accounts and rerun", ErrDuplicatePhones, len(groups))
+	}
+
+	specs, err := col.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ReportDuplicatePhones (indexes): %w", err)
+	}
+	for _, spec := range specs {
+		if spec.Name == "phone_1" && (spec.Unique == nil || !*spec.Unique) {
+			if _, err := col.Indexes().DropOne(ctx, spec.Name); err != nil {
+				return 0, fmt.Errorf("ReportDuplicatePhones (drop index): %w", err)
+			}
+			log.Printf("ReportDuplicatePhones: dropped non-unique index phone_1")
+		}
+	}
+	return 0, nil
+}
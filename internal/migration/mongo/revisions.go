@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/domain"
+)
+
+// CreateRevisionsForBooks записывает ревизию baseline с текущим состоянием каждой книги, у которой
+// ревизий ещё нет, — иначе первое же изменение такой книги нельзя было бы откатить.
+// Повторный запуск безопасен: книги с ревизиями пропускаются.
+func CreateRevisionsForBooks(ctx context.Context, db *mongo.Database) (int, error) {
+	books := db.Collection("books")
+	revisions := db.Collection("book_revisions")
+
+	cursor, err := books.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("CreateRevisionsForBooks (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	created := 0
+	for cursor.Next(ctx) {
+		var book domain.Book
+		if err := cursor.Decode(&book); err != nil {
+			return created, fmt.Errorf("CreateRevisionsForBooks (decode): %w", err)
+		}
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return created, fmt.Errorf("CreateRevisionsForBooks (decode): %w", err)
+		}
+
+		n, err := revisions.CountDocuments(ctx, bson.M{"bookId": doc.ID})
+		if err != nil {
+			return created, fmt.Errorf("CreateRevisionsForBooks (count %s): %w", doc.ID.Hex(), err)
+		}
+		if n > 0 {
+			continue
+		}
+
+		// Дата baseline — время заведения книги: точнее в документе ничего нет
+		_, err = revisions.InsertOne(ctx, domain.BookRevision{
+			BookID: doc.ID,
+			Number: 1,
+			Action: domain.RevisionBaseline,
+			Record: book.Record(),
+			At:     doc.ID.Timestamp(),
+		})
+		if err != nil {
+			return created, fmt.Errorf("CreateRevisionsForBooks (insert %s): %w", doc.ID.Hex(), err)
+		}
+		created++
+	}
+	if err := cursor.Err(); err != nil {
+		return created, fmt.Errorf("CreateRevisionsForBooks (cursor): %w", err)
+	}
+
+	log.Printf("CreateRevisionsForBooks: created %d revisions", created)
+	return created, nil
+}
@@ -0,0 +1,65 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"library-Mongo/internal/textsim"
+)
+
+// CreateSearchKeys записывает ключи поиска книгам (keys.title, keys.author) и пользователям (nameKeys),
+// сохранённым до их появления. Новые и изменённые документы получают ключи в репозиториях.
+// Повторный запуск безопасен: документы с ключами пропускаются.
+func CreateSearchKeys(ctx context.Context, db *mongo.Database) (int, error) {
+	books, err := fillKeys(ctx, db.Collection("books"), "keys", func(raw bson.Raw) bson.M {
+		title, _ := raw.Lookup("title").StringValueOK()
+		author, _ := raw.Lookup("author").StringValueOK()
+		return bson.M{"keys": bson.M{
+			"title":  textsim.SearchKeys(title),
+			"author": textsim.SearchKeys(author),
+		}}
+	})
+	if err != nil {
+		return books, fmt.Errorf("CreateSearchKeys (books): %w", err)
+	}
+
+	users, err := fillKeys(ctx, db.Collection("users"), "nameKeys", func(raw bson.Raw) bson.M {
+		name, _ := raw.Lookup("fullName").StringValueOK()
+		return bson.M{"nameKeys": textsim.SearchKeys(name)}
+	})
+	if err != nil {
+		return books + users, fmt.Errorf("CreateSearchKeys (users): %w", err)
+	}
+
+	log.Printf("CreateSearchKeys: updated %d books, %d users", books, users)
+	return books + users, nil
+}
+
+// fillKeys применяет set к каждому документу col без поля field
+func fillKeys(ctx context.Context, col *mongo.Collection, field string, set func(bson.Raw) bson.M) (int, error) {
+	cursor, err := col.Find(ctx, bson.M{field: bson.M{"$exists": false}})
+	if err != nil {
+		return 0, fmt.Errorf("find: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		id, ok := cursor.Current.Lookup("_id").ObjectIDOK()
+		if !ok {
+			continue
+		}
+		if _, err := col.UpdateByID(ctx, id, bson.M{"$set": set(cursor.Current)}); err != nil {
+			return updated, fmt.Errorf("update %s: %w", id.Hex(), err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return updated, fmt.Errorf("cursor: %w", err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogSender пишет сообщения в файл (или в лог, если путь пустой) —
+// для локальной разработки и тестов, коды видны без SMS-шлюза
+type LogSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLogSender(path string) *LogSender {
+	return &LogSender{path: path}
+}
+
+func (s *LogSender) Send(_ context.Context, msg Message) error {
+	line := fmt.Sprintf("%s to=%s text=%q\n", time.Now().Format(time.RFC3339), msg.To, msg.Text)
+	if s.path == "" {
+		log.Print("LogSender: ", line)
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("LogSender.Send: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("LogSender.Send: %w", err)
+	}
+	return nil
+}
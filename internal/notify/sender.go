@@ -0,0 +1,14 @@
+package notify
+
+import "context"
+
+// Message — одно уведомление пользователю
+type Message struct {
+	To   string // телефон получателя
+	Text string
+}
+
+// Sender доставляет одноразовые коды и прочие уведомления
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
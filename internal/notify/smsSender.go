@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SMSSender отправляет SMS через HTTP-шлюз: POST {"to": "...", "text": "..."}
+// с заголовком Authorization: Bearer <token>
+type SMSSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewSMSSender(url, token string) *SMSSender {
+	return &SMSSender{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SMSSender) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]string{"to": msg.To, "text": msg.Text})
+	if err != nil {
+		return fmt.Errorf("SMSSender.Send: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("SMSSender.Send: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("SMSSender.Send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SMSSender.Send: gateway responded %s", resp.Status)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
 	"library-Mongo/internal/domain"
 	"time"
 )
@@ -11,30 +12,245 @@ type (
 	BookRepository interface {
 		Create(ctx context.Context, b *domain.Book) error
 		Update(ctx context.Context, b *domain.Book) error
+		// SetCover — описание обложки (nil — обложки нет)
+		SetCover(ctx context.Context, id string, cover *domain.Cover) error
 		Delete(ctx context.Context, id string) error
+		// SetWithdrawn списывает книгу (w != nil) или возвращает её в каталог (w == nil);
+		// false — книга уже в этом состоянии
+		SetWithdrawn(ctx context.Context, id string, w *domain.Withdrawal) (bool, error)
+		// MarkMerged помечает книгу дубликатом target
+		MarkMerged(ctx context.Context, id, target primitive.ObjectID) error
 		GetByID(ctx context.Context, id string) (*domain.Book, error)
+		GetByISBN(ctx context.Context, isbn13 string) (*domain.Book, error)
 		Search(ctx context.Context, filter domain.BookFilter) ([]domain.Book, error)
+		// FindByTitle — книги с тем же названием без учёта регистра (поиск дубликатов)
+		FindByTitle(ctx context.Context, title string) ([]domain.Book, error)
+		// Each обходит все книги под фильтром курсором (экспорт)
+		Each(ctx context.Context, filter domain.BookFilter, fn func(domain.Book) error) error
+		// Search, Each, SearchPage и Facets не возвращают списанные книги, если фильтр не просит их явно,
+		// и никогда не возвращают влитые
+		// SearchPage — страница каталога; сортировки title, author, year, volume, relevance (только с Query).
+		// С GroupByWork — одно издание на произведение, Book.Editions — сколько их нашлось
+		SearchPage(ctx context.Context, filter domain.BookFilter, page domain.PageRequest) (domain.Page[domain.Book], error)
+		// Facets — распределение книг под фильтром по жанру, автору, десятилетию и доступности
+		Facets(ctx context.Context, filter domain.BookFilter) (domain.BookFacets, error)
+		// Count — число книг в каталоге без списанных и влитых
 		Count(ctx context.Context) (int64, error)
+		CountByAuthor(ctx context.Context, authorID primitive.ObjectID) (int64, error)
+		// RelinkAuthor переносит участие автора from на to (слияние) или обновляет имя (from == to)
+		RelinkAuthor(ctx context.Context, from, to primitive.ObjectID, name string) (int64, error)
+		// KeyVocabulary — ключи поиска поля title или author, начинающиеся с first (исправление опечаток)
+		KeyVocabulary(ctx context.Context, field, first string) ([]string, error)
+		// CountByGenre — книги каталога по ID жанра; "" — книги без ссылки на рубрикатор
+		CountByGenre(ctx context.Context) (map[string]int64, error)
+		// CountInGenre — все книги со ссылкой на жанр, включая списанные
+		CountInGenre(ctx context.Context, genreID string) (int64, error)
+		// RelinkGenre переносит книги жанра from на to с названием label (слияние) или обновляет название (from == to)
+		RelinkGenre(ctx context.Context, from, to, label string) (int64, error)
+		// CountInWork и CountInSeries — все книги произведения и серии, включая списанные
+		CountInWork(ctx context.Context, workID string) (int64, error)
+		CountInSeries(ctx context.Context, seriesID string) (int64, error)
+		// RenameSeries обновляет копию названия серии в её книгах
+		RenameSeries(ctx context.Context, seriesID, title string) (int64, error)
+	}
+
+	// BookRevisionRepository — история изменений библиографических записей; ревизии не меняются и не удаляются
+	BookRevisionRepository interface {
+		// Create присваивает ревизии следующий номер в пределах книги
+		Create(ctx context.Context, rev *domain.BookRevision) error
+		// GetByNumber — nil, если ревизии нет
+		GetByNumber(ctx context.Context, bookID primitive.ObjectID, number int) (*domain.BookRevision, error)
+		// ListPage — ревизии книги; сортировка number, по умолчанию от новых к старым
+		ListPage(ctx context.Context, bookID primitive.ObjectID, page domain.PageRequest) (domain.Page[domain.BookRevision], error)
+	}
+
+	// CoverStorage — файлы обложек: оригинал и миниатюры (variant — domain.CoverOriginal, CoverSmall, ...)
+	CoverStorage interface {
+		// Save заменяет файл варианта обложки книги
+		Save(ctx context.Context, bookID, variant string, data []byte) error
+		// Open — содержимое файла; customErr.ErrCoverNotFound, если его нет
+		Open(ctx context.Context, bookID, variant string) (io.ReadCloser, error)
+		// DeleteAll удаляет все файлы обложки книги
+		DeleteAll(ctx context.Context, bookID string) error
+	}
+
+	AuthorRepository interface {
+		Create(ctx context.Context, a *domain.Author) error
+		Update(ctx context.Context, a *domain.Author) error
+		GetByID(ctx context.Context, id string) (*domain.Author, error)
+		// FindByName — действующая запись с таким именем или вариантом написания
+		FindByName(ctx context.Context, name string) (*domain.Author, error)
+		SearchPage(ctx context.Context, name string, page domain.PageRequest) (domain.Page[domain.Author], error)
+		// MarkMerged помечает запись дубликатом target
+		MarkMerged(ctx context.Context, id, target primitive.ObjectID, now time.Time) error
+		Delete(ctx context.Context, id string) error
+	}
+
+	// GenreRepository — рубрикатор жанров; иерархия хранится путём предков (Ancestors)
+	GenreRepository interface {
+		// Create и Update возвращают ErrGenreLabelTaken, если название или синоним уже занят другим жанром
+		Create(ctx context.Context, g *domain.Genre) error
+		Update(ctx context.Context, g *domain.Genre) error
+		// Rebase заменяет у потомков id путь до id включительно на path
+		Rebase(ctx context.Context, id string, path []string, now time.Time) (int64, error)
+		// GetByID и FindByLabel возвращают nil, если жанра нет
+		GetByID(ctx context.Context, id string) (*domain.Genre, error)
+		// FindByLabel — жанр с таким названием или синонимом
+		FindByLabel(ctx context.Context, label string) (*domain.Genre, error)
+		All(ctx context.Context) ([]domain.Genre, error)
+		CountChildren(ctx context.Context, id string) (int64, error)
+		Delete(ctx context.Context, id string) error
+	}
+
+	// WorkRepository — произведения; издания ссылаются на них через Book.WorkID
+	WorkRepository interface {
+		Create(ctx context.Context, w *domain.Work) error
+		Update(ctx context.Context, w *domain.Work) error
+		// GetByID — nil, если произведения нет
+		GetByID(ctx context.Context, id string) (*domain.Work, error)
+		// SearchPage — по подстроке или словам названия; сортировки title, author, year
+		SearchPage(ctx context.Context, title string, page domain.PageRequest) (domain.Page[domain.Work], error)
+		Delete(ctx context.Context, id string) error
+	}
+
+	// SeriesRepository — серии и циклы; книги ссылаются на них через Book.Series
+	SeriesRepository interface {
+		Create(ctx context.Context, s *domain.Series) error
+		Update(ctx context.Context, s *domain.Series) error
+		// GetByID — nil, если серии нет
+		GetByID(ctx context.Context, id string) (*domain.Series, error)
+		SearchPage(ctx context.Context, title string, page domain.PageRequest) (domain.Page[domain.Series], error)
+		Delete(ctx context.Context, id string) error
+	}
+
+	ItemRepository interface {
+		Create(ctx context.Context, item *domain.Item) error
+		// Update записывает экземпляр вместе со статусом, если текущий статус — from;
+		// false — статус уже поменялся (например, экземпляр только что выдали)
+		Update(ctx context.Context, item *domain.Item, from string) (bool, error)
+		GetByID(ctx context.Context, id string) (*domain.Item, error)
+		GetByBarcode(ctx context.Context, barcode string) (*domain.Item, error)
+		FindAvailable(ctx context.Context, bookID primitive.ObjectID) (*domain.Item, error)
+		ListByBook(ctx context.Context, bookID primitive.ObjectID) ([]domain.Item, error)
+		// SetStatus атомарно меняет статус from -> to; false — экземпляр уже в другом статусе
+		SetStatus(ctx context.Context, id primitive.ObjectID, from, to string) (bool, error)
+		// Availability — число экземпляров и свободных по каждой книге (ключ — ID книги)
+		Availability(ctx context.Context, bookIDs []primitive.ObjectID) (map[string]domain.Availability, error)
+		Delete(ctx context.Context, id string) error
+		DeleteByBook(ctx context.Context, bookID primitive.ObjectID) error
+		// RelinkBook переносит экземпляры книги from на книгу to (слияние дубликатов)
+		RelinkBook(ctx context.Context, from, to primitive.ObjectID) (int64, error)
 	}
 
 	UserRepository interface {
 		GetByID(ctx context.Context, id string) (*domain.User, error)
-		Login(ctx context.Context, phone, password string) (*domain.User, error)
+		GetByPhone(ctx context.Context, phone string) (*domain.User, error)
 		Search(ctx context.Context, filter domain.UserFilter) ([]domain.User, error)
+		// SearchPage — страница пользователей; сортировки fullName, phone, registeredAt
+		SearchPage(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (domain.Page[domain.User], error)
+		// NameKeyVocabulary — ключи поиска ФИО, начинающиеся с first (исправление опечаток)
+		NameKeyVocabulary(ctx context.Context, first string) ([]string, error)
 		Create(ctx context.Context, u *domain.User) error
 		Update(ctx context.Context, u *domain.User) error
+		UpdatePassword(ctx context.Context, id, passwordHash string) error
+		MarkPhoneVerified(ctx context.Context, id string) error
 		Delete(ctx context.Context, id string) error
 		Count(ctx context.Context) (int64, error)
 	}
 
+	SessionRepository interface {
+		Create(ctx context.Context, s *domain.Session) error
+		GetByID(ctx context.Context, id string) (*domain.Session, error)
+		// GetByRefreshHash ищет сессию по текущему или уже использованному refresh-токену
+		GetByRefreshHash(ctx context.Context, hash string) (*domain.Session, error)
+		// Rotate меняет refresh-токен, если текущий всё ещё oldHash; false — токен уже обменян
+		Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt, now time.Time) (bool, error)
+		ListActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) ([]domain.Session, error)
+		Revoke(ctx context.Context, id, reason string, now time.Time) error
+		RevokeAllByUser(ctx context.Context, userID primitive.ObjectID, reason string, now time.Time) (int64, error)
+	}
+
+	LoginAttemptRepository interface {
+		Get(ctx context.Context, key string) (*domain.LoginAttempt, error)
+		// RegisterFailure увеличивает счётчик неудач и продлевает его жизнь до expireAt
+		RegisterFailure(ctx context.Context, key string, now, expireAt time.Time) (*domain.LoginAttempt, error)
+		Lock(ctx context.Context, key string, until, expireAt time.Time) error
+		Reset(ctx context.Context, key string) error
+		CreateEvent(ctx context.Context, e *domain.LockoutEvent) error
+		ListEvents(ctx context.Context, filter domain.LockoutEventFilter) ([]domain.LockoutEvent, error)
+	}
+
+	VerificationCodeRepository interface {
+		Create(ctx context.Context, c *domain.VerificationCode) error
+		// GetActive — последний неиспользованный и не истёкший код
+		GetActive(ctx context.Context, phone, purpose string, now time.Time) (*domain.VerificationCode, error)
+		GetLatest(ctx context.Context, phone, purpose string) (*domain.VerificationCode, error)
+		// IncrementAttempts атомарно засчитывает попытку ввода, пока их меньше max и код не погашен;
+		// false — попытки исчерпаны (параллельные запросы не получат лишних)
+		IncrementAttempts(ctx context.Context, id string, max int) (int, bool, error)
+		SetTicket(ctx context.Context, id, ticketHash string, expiresAt time.Time) error
+		GetByTicket(ctx context.Context, ticketHash string) (*domain.VerificationCode, error)
+		Consume(ctx context.Context, id string, now time.Time) error
+		// InvalidateAll гасит все прежние коды телефона с тем же назначением
+		InvalidateAll(ctx context.Context, phone, purpose string, now time.Time) error
+	}
+
+	TwoFactorRepository interface {
+		Get(ctx context.Context, userID primitive.ObjectID) (*domain.TwoFactor, error)
+		// SavePending начинает привязку; false — у пользователя уже включена 2FA
+		SavePending(ctx context.Context, tf *domain.TwoFactor) (bool, error)
+		// Enable подтверждает привязку; false — привязка не начата или уже подтверждена
+		Enable(ctx context.Context, userID primitive.ObjectID, recoveryHashes []string, step int64, now time.Time) (bool, error)
+		// UseStep фиксирует интервал принятого кода; false — код этого интервала уже использован
+		UseStep(ctx context.Context, userID primitive.ObjectID, step int64) (bool, error)
+		// UseRecoveryCode гасит код восстановления; false — кода нет или он уже использован
+		UseRecoveryCode(ctx context.Context, userID primitive.ObjectID, hash string) (bool, error)
+		Delete(ctx context.Context, userID primitive.ObjectID) (bool, error)
+	}
+
+	SettingsRepository interface {
+		GetSecurity(ctx context.Context) (domain.SecuritySettings, error)
+		SaveSecurity(ctx context.Context, s domain.SecuritySettings) error
+	}
+
+	APIKeyRepository interface {
+		Create(ctx context.Context, k *domain.APIKey) error
+		GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+		GetByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+		List(ctx context.Context, includeRevoked bool) ([]domain.APIKey, error)
+		// Rotate заменяет секрет действующего ключа; false — ключ не найден или отозван
+		Rotate(ctx context.Context, id, prefix, hash string, now time.Time) (bool, error)
+		Revoke(ctx context.Context, id string, now time.Time) (bool, error)
+		// Touch обновляет lastUsedAt, если прошлая отметка старше staleBefore или сменился IP
+		Touch(ctx context.Context, id, ip string, now, staleBefore time.Time) error
+	}
+
+	AuditRepository interface {
+		Create(ctx context.Context, e *domain.AuditEntry) error
+		List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+	}
+
 	BorrowRepository interface {
 		Create(ctx context.Context, b *domain.Borrow) error
-		Close(ctx context.Context, borrowID string, returnTime time.Time) error
+		// Close отмечает возврат; false — выдача уже закрыта (в том числе параллельным возвратом)
+		Close(ctx context.Context, borrowID string, returnTime time.Time) (bool, error)
 		GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Borrow, error)
 		GetByClientID(ctx context.Context, clientID primitive.ObjectID) ([]domain.Borrow, error)
 		GetOverdue(ctx context.Context, now time.Time) ([]domain.Borrow, error)
-		GetDailyStats(ctx context.Context, from, to time.Time) ([]domain.BorrowStat, error)
+		// Страничные варианты отчётов; сортировка borrowedAt
+		GetByClientIDPage(ctx context.Context, clientID primitive.ObjectID, page domain.PageRequest) (domain.Page[domain.Borrow], error)
+		GetOverduePage(ctx context.Context, now time.Time, page domain.PageRequest) (domain.Page[domain.Borrow], error)
+		CountOverdueByClients(ctx context.Context, now time.Time, clientIDs []primitive.ObjectID) (map[string]int, error)
+		// GetDailyStats — по всем выдачам или, если bookIDs != nil, по выдачам этих книг
+		GetDailyStats(ctx context.Context, from, to time.Time, bookIDs []primitive.ObjectID) ([]domain.BorrowStat, error)
+		// GetByBooksPage и StatsByBook — выдачи нескольких книг (все издания произведения)
+		GetByBooksPage(ctx context.Context, bookIDs []primitive.ObjectID, page domain.PageRequest) (domain.Page[domain.Borrow], error)
+		StatsByBook(ctx context.Context, bookIDs []primitive.ObjectID) ([]domain.WorkBorrowStat, error)
 		CountActive(ctx context.Context) (int64, error)
-		HasActiveBorrow(ctx context.Context, bookID primitive.ObjectID) (bool, error)
+		HasActiveBorrow(ctx context.Context, itemID primitive.ObjectID) (bool, error)
+		// CountActiveByBook — невозвращённые выдачи книги, включая выдачи без экземпляра
+		CountActiveByBook(ctx context.Context, bookID primitive.ObjectID) (int64, error)
+		// RelinkBook переносит все выдачи книги from на книгу to (слияние дубликатов)
+		RelinkBook(ctx context.Context, from, to primitive.ObjectID) (int64, error)
 	}
 )
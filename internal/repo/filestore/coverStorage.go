@@ -0,0 +1,87 @@
+// Package filestore — хранилища файлов на локальном диске
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	customErr "library-Mongo/internal/errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CoverStorageFS хранит обложки в каталоге dir: dir/<bookID>/<variant>
+type CoverStorageFS struct {
+	dir string
+}
+
+func NewCoverStorage(dir string) *CoverStorageFS {
+	return &CoverStorageFS{dir: dir}
+}
+
+// path не даёт выйти за пределы dir: ID книги и вариант приходят из запроса
+func (s *CoverStorageFS) path(bookID, variant string) (string, error) {
+	for _, part := range []string{bookID, variant} {
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, `/\`) {
+			return "", fmt.Errorf("invalid cover path %q", part)
+		}
+	}
+	return filepath.Join(s.dir, bookID, variant), nil
+}
+
+// Save пишет во временный файл и переименовывает: читатель не увидит наполовину записанную обложку
+func (s *CoverStorageFS) Save(ctx context.Context, bookID, variant string, data []byte) error {
+	path, err := s.path(bookID, variant)
+	if err != nil {
+		return fmt.Errorf("CoverStorageFS.Save: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("CoverStorageFS.Save: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), variant+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("CoverStorageFS.Save: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("CoverStorageFS.Save: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("CoverStorageFS.Save: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("CoverStorageFS.Save: %w", err)
+	}
+	return nil
+}
+
+func (s *CoverStorageFS) Open(ctx context.Context, bookID, variant string) (io.ReadCloser, error) {
+	path, err := s.path(bookID, variant)
+	if err != nil {
+		return nil, customErr.ErrCoverNotFound
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, customErr.ErrCoverNotFound
+		}
+		return nil, fmt.Errorf("CoverStorageFS.Open: %w", err)
+	}
+	return f, nil
+}
+
+func (s *CoverStorageFS) DeleteAll(ctx context.Context, bookID string) error {
+	path, err := s.path(bookID, "original")
+	if err != nil {
+		return fmt.Errorf("CoverStorageFS.DeleteAll: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("CoverStorageFS.DeleteAll: %w", err)
+	}
+	return nil
+}
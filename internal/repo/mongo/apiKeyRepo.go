@@ -0,0 +1,153 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type APIKeyRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewAPIKeyRepo(db *mongo.Database) *APIKeyRepoMongo {
+	return &APIKeyRepoMongo{
+		col: db.Collection("api_keys"),
+	}
+}
+
+func (r *APIKeyRepoMongo) Create(ctx context.Context, k *domain.APIKey) error {
+	doc := bson.M{
+		"name":      k.Name,
+		"prefix":    k.Prefix,
+		"hash":      k.Hash,
+		"scopes":    k.Scopes,
+		"createdAt": k.CreatedAt,
+		"createdBy": k.CreatedBy,
+	}
+	if len(k.AllowedIPs) > 0 {
+		doc["allowedIps"] = k.AllowedIPs
+	}
+	if k.ExpiresAt != nil {
+		doc["expiresAt"] = *k.ExpiresAt
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("APIKeyRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	k.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *APIKeyRepoMongo) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepoMongo.GetByID: %w", err)
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *APIKeyRepoMongo) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return r.findOne(ctx, bson.M{"hash": hash})
+}
+
+func (r *APIKeyRepoMongo) findOne(ctx context.Context, filter bson.M) (*domain.APIKey, error) {
+	var k domain.APIKey
+	err := r.col.FindOne(ctx, filter).Decode(&k)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("APIKeyRepoMongo.findOne: %w", err)
+	}
+	return &k, nil
+}
+
+func (r *APIKeyRepoMongo) List(ctx context.Context, includeRevoked bool) ([]domain.APIKey, error) {
+	filter := bson.M{}
+	if !includeRevoked {
+		filter["revokedAt"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepoMongo.List (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []domain.APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("APIKeyRepoMongo.List (decode): %w", err)
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepoMongo) Rotate(ctx context.Context, id, prefix, hash string, now time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("APIKeyRepoMongo.Rotate: %w", err)
+	}
+
+	filter := bson.M{"_id": objID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{
+		"prefix":    prefix,
+		"hash":      hash,
+		"rotatedAt": now,
+	}}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("APIKeyRepoMongo.Rotate: %w", err)
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (r *APIKeyRepoMongo) Revoke(ctx context.Context, id string, now time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("APIKeyRepoMongo.Revoke: %w", err)
+	}
+
+	filter := bson.M{"_id": objID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": now}}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("APIKeyRepoMongo.Revoke: %w", err)
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (r *APIKeyRepoMongo) Touch(ctx context.Context, id, ip string, now, staleBefore time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepoMongo.Touch: %w", err)
+	}
+
+	// Пишем не чаще раза в интервал, чтобы частые запросы киоска не нагружали БД
+	filter := bson.M{"_id": objID, "$or": bson.A{
+		bson.M{"lastUsedAt": bson.M{"$exists": false}},
+		bson.M{"lastUsedAt": bson.M{"$lt": staleBefore}},
+		bson.M{"lastUsedIp": bson.M{"$ne": ip}},
+	}}
+	update := bson.M{"$set": bson.M{"lastUsedAt": now, "lastUsedIp": ip}}
+
+	if _, err := r.col.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("APIKeyRepoMongo.Touch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+)
+
+type AuditRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewAuditRepo(db *mongo.Database) *AuditRepoMongo {
+	return &AuditRepoMongo{
+		col: db.Collection("audit_log"),
+	}
+}
+
+func (r *AuditRepoMongo) Create(ctx context.Context, e *domain.AuditEntry) error {
+	res, err := r.col.InsertOne(ctx, e)
+	if err != nil {
+		return fmt.Errorf("AuditRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("AuditRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	e.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *AuditRepoMongo) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	query := bson.M{}
+	if filter.ActorID != "" {
+		query["$or"] = bson.A{
+			bson.M{"actor.userId": filter.ActorID},
+			bson.M{"actor.apiKeyId": filter.ActorID},
+		}
+	}
+	if filter.EntityType != "" {
+		query["entityType"] = filter.EntityType
+	}
+	if filter.EntityID != "" {
+		query["entityId"] = filter.EntityID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	at := bson.M{}
+	if !filter.From.IsZero() {
+		at["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		at["$lt"] = filter.To
+	}
+	if len(at) > 0 {
+		query["at"] = at
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := r.col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("AuditRepoMongo.List (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []domain.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("AuditRepoMongo.List (decode): %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,151 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"library-Mongo/internal/domain"
+	"regexp"
+	"time"
+)
+
+type AuthorRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewAuthorRepo(db *mongo.Database) *AuthorRepoMongo {
+	return &AuthorRepoMongo{
+		col: db.Collection("authors"),
+	}
+}
+
+func (r *AuthorRepoMongo) Create(ctx context.Context, a *domain.Author) error {
+	doc := bson.M{
+		"name":      a.Name,
+		"keys":      a.Keys,
+		"createdAt": a.CreatedAt,
+		"updatedAt": a.UpdatedAt,
+	}
+	if len(a.Variants) > 0 {
+		doc["variants"] = a.Variants
+	}
+	if a.BirthYear != 0 {
+		doc["birthYear"] = a.BirthYear
+	}
+	if a.DeathYear != 0 {
+		doc["deathYear"] = a.DeathYear
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("AuthorRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("AuthorRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	a.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *AuthorRepoMongo) Update(ctx context.Context, a *domain.Author) error {
+	objID, err := primitive.ObjectIDFromHex(a.ID)
+	if err != nil {
+		return fmt.Errorf("AuthorRepoMongo.Update: %w", err)
+	}
+	update := bson.M{"$set": bson.M{
+		"name":      a.Name,
+		"variants":  a.Variants,
+		"keys":      a.Keys,
+		"birthYear": a.BirthYear,
+		"deathYear": a.DeathYear,
+		"updatedAt": a.UpdatedAt,
+	}}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("AuthorRepoMongo.Update: %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает запись как есть, в том числе влитую в другую (MergedInto)
+func (r *AuthorRepoMongo) GetByID(ctx context.Context, id string) (*domain.Author, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("AuthorRepoMongo.GetByID: %w", err)
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+// FindByName ищет действующую запись, у которой имя или один из вариантов совпадает по NameKey
+func (r *AuthorRepoMongo) FindByName(ctx context.Context, name string) (*domain.Author, error) {
+	return r.findOne(ctx, bson.M{
+		"keys":       domain.NameKey(name),
+		"mergedInto": bson.M{"$exists": false},
+	})
+}
+
+func (r *AuthorRepoMongo) findOne(ctx context.Context, filter bson.M) (*domain.Author, error) {
+	var a domain.Author
+	err := r.col.FindOne(ctx, filter).Decode(&a)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("AuthorRepoMongo.findOne: %w", err)
+	}
+	return &a, nil
+}
+
+// authorSorts — поля сортировки списка авторов (параметр sort)
+var authorSorts = pageSorts{
+	"name":      "name",
+	"birthYear": "birthYear",
+}
+
+// SearchPage — действующие (не влитые) записи, любое написание которых содержит name
+func (r *AuthorRepoMongo) SearchPage(ctx context.Context, name string, page domain.PageRequest) (domain.Page[domain.Author], error) {
+	query := bson.M{"mergedInto": bson.M{"$exists": false}}
+	if key := domain.NameKey(name); key != "" {
+		query["keys"] = bson.M{"$regex": regexp.QuoteMeta(key)}
+	}
+	res, err := findPage[domain.Author](ctx, r.col, query, page, authorSorts, "name", nil)
+	if err != nil {
+		return res, fmt.Errorf("AuthorRepoMongo.SearchPage: %w", err)
+	}
+	return res, nil
+}
+
+// MarkMerged помечает запись дубликатом target; записи, ранее влитые в неё, перенаправляются на target
+func (r *AuthorRepoMongo) MarkMerged(ctx context.Context, id, target primitive.ObjectID, now time.Time) error {
+	_, err := r.col.UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"mergedInto": target.Hex(),
+		"updatedAt":  now,
+	}})
+	if err != nil {
+		return fmt.Errorf("AuthorRepoMongo.MarkMerged: %w", err)
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"mergedInto": id.Hex()}, bson.M{"$set": bson.M{
+		"mergedInto": target.Hex(),
+		"updatedAt":  now,
+	}})
+	if err != nil {
+		return fmt.Errorf("AuthorRepoMongo.MarkMerged (redirects): %w", err)
+	}
+	return nil
+}
+
+func (r *AuthorRepoMongo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("AuthorRepoMongo.Delete: %w", err)
+	}
+	if _, err := r.col.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		return fmt.Errorf("AuthorRepoMongo.Delete: %w", err)
+	}
+	return nil
+}
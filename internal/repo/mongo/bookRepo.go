@@ -6,37 +6,64 @@ import (
 	"fmt"
 	"library-Mongo/internal/domain"
 	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/textsim"
+	"regexp"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type BookRepoMongo struct {
-	col *mongo.Collection
+	col    *mongo.Collection
+	genres *mongo.Collection // рубрикатор — для фильтра по жанру с поджанрами
 }
 
 func NewBookRepo(db *mongo.Database) *BookRepoMongo {
 	return &BookRepoMongo{
-		col: db.Collection("books"),
+		col:    db.Collection("books"),
+		genres: db.Collection("genres"),
 	}
 }
 
 func (r *BookRepoMongo) Create(ctx context.Context, b *domain.Book) error {
 	bookDoc := struct {
-		Title  string `bson:"title"`
-		Author string `bson:"author"`
-		Year   int    `bson:"year"`
-		Genre  string `bson:"genre"`
+		Title   string `bson:"title"`
+		Author  string `bson:"author"`
+		Year    int    `bson:"year"`
+		Genre   string `bson:"genre"`
+		GenreID string `bson:"genreId,omitempty"`
+		ISBN13  string `bson:"isbn13,omitempty"`
+		ISBN10  string `bson:"isbn10,omitempty"`
+
+		WorkID string              `bson:"workId,omitempty"`
+		Series *domain.SeriesEntry `bson:"series,omitempty"`
+
+		Contributors []domain.Contributor `bson:"contributors,omitempty"`
+		Keys         domain.BookKeys      `bson:"keys"`
 	}{
-		Title:  b.Title,
-		Author: b.Author,
-		Year:   b.Year,
-		Genre:  b.Genre,
+		Title:   b.Title,
+		Author:  b.Author,
+		Year:    b.Year,
+		Genre:   b.Genre,
+		GenreID: b.GenreID,
+		ISBN13:  b.ISBN13,
+		ISBN10:  b.ISBN10,
+
+		WorkID: b.WorkID,
+		Series: b.Series,
+
+		Contributors: b.Contributors,
+		Keys:         bookKeys(b.Title, b.Author),
 	}
 
 	res, err := r.col.InsertOne(ctx, bookDoc)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return customErr.ErrISBNTaken
+		}
 		return fmt.Errorf("BookRepoMongo.Create: %w", err)
 	}
 
@@ -55,22 +82,59 @@ func (r *BookRepoMongo) Update(ctx context.Context, b *domain.Book) error {
 		return fmt.Errorf("BookRepoMongo.Update: %w", err)
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"title":  b.Title,
-			"author": b.Author,
-			"year":   b.Year,
-			"genre":  b.Genre,
-		},
+	set := bson.M{
+		"title":        b.Title,
+		"author":       b.Author,
+		"year":         b.Year,
+		"genre":        b.Genre,
+		"contributors": b.Contributors,
+		"keys":         bookKeys(b.Title, b.Author),
+	}
+	// Пустой ISBN удаляется из документа, иначе книги без ISBN конфликтуют в уникальном индексе
+	unset := bson.M{}
+	for field, value := range map[string]string{"isbn13": b.ISBN13, "isbn10": b.ISBN10, "genreId": b.GenreID, "workId": b.WorkID} {
+		if value != "" {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+	if b.Series != nil {
+		set["series"] = b.Series
+	} else {
+		unset["series"] = ""
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
 
 	_, err = r.col.UpdateByID(ctx, objID, update)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return customErr.ErrISBNTaken
+		}
 		return fmt.Errorf("BookRepoMongo.Update: %w", err)
 	}
 	return nil
 }
 
+// SetCover записывает описание обложки; nil убирает обложку
+func (r *BookRepoMongo) SetCover(ctx context.Context, id string, cover *domain.Cover) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("BookRepoMongo.SetCover: %w", err)
+	}
+	update := bson.M{"$set": bson.M{"cover": cover}}
+	if cover == nil {
+		update = bson.M{"$unset": bson.M{"cover": ""}}
+	}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("BookRepoMongo.SetCover: %w", err)
+	}
+	return nil
+}
+
 func (r *BookRepoMongo) Delete(ctx context.Context, id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -84,6 +148,40 @@ func (r *BookRepoMongo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// SetWithdrawn меняет состояние только из противоположного: два параллельных списания не затрут
+// причину и дату друг друга
+func (r *BookRepoMongo) SetWithdrawn(ctx context.Context, id string, w *domain.Withdrawal) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("BookRepoMongo.SetWithdrawn: %w", err)
+	}
+	filter := bson.M{"_id": objID, "withdrawn": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"withdrawn": w}}
+	if w == nil {
+		filter["withdrawn"] = bson.M{"$exists": true}
+		update = bson.M{"$unset": bson.M{"withdrawn": ""}}
+	}
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("BookRepoMongo.SetWithdrawn: %w", err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// MarkMerged помечает книгу дубликатом target; книги, ранее влитые в неё, перенаправляются на target
+func (r *BookRepoMongo) MarkMerged(ctx context.Context, id, target primitive.ObjectID) error {
+	_, err := r.col.UpdateByID(ctx, id, bson.M{"$set": bson.M{"mergedInto": target.Hex()}})
+	if err != nil {
+		return fmt.Errorf("BookRepoMongo.MarkMerged: %w", err)
+	}
+	_, err = r.col.UpdateMany(ctx, bson.M{"mergedInto": id.Hex()}, bson.M{"$set": bson.M{"mergedInto": target.Hex()}})
+	if err != nil {
+		return fmt.Errorf("BookRepoMongo.MarkMerged (redirects): %w", err)
+	}
+	return nil
+}
+
+// GetByID возвращает книгу как есть, в том числе влитую в другую (MergedInto)
 func (r *BookRepoMongo) GetByID(ctx context.Context, id string) (*domain.Book, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -103,20 +201,35 @@ func (r *BookRepoMongo) GetByID(ctx context.Context, id string) (*domain.Book, e
 	return &doc, nil
 }
 
-func (r *BookRepoMongo) Search(ctx context.Context, filter domain.BookFilter) ([]domain.Book, error) {
-	query := bson.M{}
-
-	if filter.Title != "" {
-		query["title"] = bson.M{"$regex": filter.Title, "$options": "i"}
+// GetByISBN ищет книгу по нормализованному ISBN-13
+func (r *BookRepoMongo) GetByISBN(ctx context.Context, isbn13 string) (*domain.Book, error) {
+	var doc domain.Book
+	err := r.col.FindOne(ctx, bson.M{"isbn13": isbn13}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("BookRepoMongo.GetByISBN: %w", customErr.ErrBookNotFound)
+		}
+		return nil, fmt.Errorf("BookRepoMongo.GetByISBN: %w", err)
 	}
-	if filter.Author != "" {
-		query["author"] = bson.M{"$regex": filter.Author, "$options": "i"}
+	return &doc, nil
+}
+
+func (r *BookRepoMongo) Search(ctx context.Context, filter domain.BookFilter) ([]domain.Book, error) {
+	query, err := r.searchQuery(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("BookRepoMongo.Search: %w", err)
 	}
-	if len(filter.Genres) > 0 {
-		query["genre"] = bson.M{"$in": filter.Genres}
+	var sort bson.D
+	var projection bson.M
+
+	// Результаты полнотекстового поиска — по убыванию релевантности
+	if filter.Query != "" && !filter.QueryByKeys {
+		score := bson.M{"$meta": "textScore"}
+		projection = bson.M{"score": score}
+		sort = bson.D{{Key: "score", Value: score}}
 	}
 
-	cursor, err := r.col.Find(ctx, query)
+	cursor, err := r.findBooks(ctx, filter, query, sort, projection)
 	if err != nil {
 		return nil, fmt.Errorf("BookRepoMongo.Search: %w", err)
 	}
@@ -138,8 +251,517 @@ func (r *BookRepoMongo) Search(ctx context.Context, filter domain.BookFilter) ([
 	return books, nil
 }
 
+// findBooks открывает курсор по книгам под фильтром. Условие AvailableOnly — это $lookup по
+// экземплярам, поэтому такой фильтр выполняется агрегацией, а остальные — обычным Find
+func (r *BookRepoMongo) findBooks(ctx context.Context, filter domain.BookFilter, query bson.M, sort bson.D, projection bson.M) (*mongo.Cursor, error) {
+	if !filter.AvailableOnly {
+		opts := options.Find()
+		if projection != nil {
+			opts.SetProjection(projection)
+		}
+		if sort != nil {
+			opts.SetSort(sort)
+		}
+		return r.col.Find(ctx, query, opts)
+	}
+
+	pipeline := bson.A{bson.M{"$match": query}}
+	if projection != nil {
+		pipeline = append(pipeline, bson.M{"$addFields": projection})
+	}
+	// Сортировка до $lookup: он порядок не меняет, а $sort сразу за $match может взять индекс
+	if sort != nil {
+		pipeline = append(pipeline, bson.M{"$sort": sort})
+	}
+	pipeline = append(pipeline, availableStages...)
+	return r.col.Aggregate(ctx, pipeline)
+}
+
+// freeItemLookup кладёт в поле free один свободный экземпляр книги, если он есть
+var freeItemLookup = bson.M{"$lookup": bson.M{
+	"from": "items",
+	"let":  bson.M{"bookId": "$_id"},
+	"pipeline": bson.A{
+		bson.M{"$match": bson.M{
+			"$expr":  bson.M{"$eq": bson.A{"$bookId", "$$bookId"}},
+			"status": domain.ItemAvailable,
+		}},
+		bson.M{"$limit": 1},
+		bson.M{"$project": bson.M{"_id": 1}},
+	},
+	"as": "free",
+}}
+
+// availableStages оставляют книги, у которых есть свободный экземпляр. Список таких книг в фильтре
+// (_id: {$in: [...]}) рос бы вместе с каталогом и упёрся бы в предельный размер документа BSON
+var availableStages = bson.A{
+	freeItemLookup,
+	bson.M{"$match": bson.M{"free": bson.M{"$ne": bson.A{}}}},
+	bson.M{"$unset": "free"},
+}
+
+// FindByTitle — книги с точно таким названием без учёта регистра
+func (r *BookRepoMongo) FindByTitle(ctx context.Context, title string) ([]domain.Book, error) {
+	query := bson.M{
+		"title":      bson.M{"$regex": "^" + regexp.QuoteMeta(title) + "$", "$options": "i"},
+		"mergedInto": bson.M{"$exists": false},
+	}
+	cursor, err := r.col.Find(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("BookRepoMongo.FindByTitle: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var books []domain.Book
+	if err := cursor.All(ctx, &books); err != nil {
+		return nil, fmt.Errorf("BookRepoMongo.FindByTitle (decode): %w", err)
+	}
+	return books, nil
+}
+
+// Each вызывает fn для каждой книги под фильтром в порядке добавления, не загружая выборку в память.
+// Ошибка fn прерывает обход и возвращается как есть.
+func (r *BookRepoMongo) Each(ctx context.Context, filter domain.BookFilter, fn func(domain.Book) error) error {
+	query, err := r.searchQuery(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("BookRepoMongo.Each: %w", err)
+	}
+	cursor, err := r.findBooks(ctx, filter, query, bson.D{{Key: "_id", Value: 1}}, nil)
+	if err != nil {
+		return fmt.Errorf("BookRepoMongo.Each: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var book domain.Book
+		if err := cursor.Decode(&book); err != nil {
+			return fmt.Errorf("BookRepoMongo.Each (decode): %w", err)
+		}
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("BookRepoMongo.Each (cursor): %w", err)
+	}
+	return nil
+}
+
+// bookSorts — поля сортировки каталога (параметр sort)
+var bookSorts = pageSorts{
+	"title":     "title",
+	"author":    "author",
+	"year":      "year",
+	"volume":    "series.volume",
+	"relevance": scoreField,
+}
+
+// workGroup сворачивает выборку до одного издания на произведение; книги без произведения
+// остаются сами по себе. Editions — сколько изданий произведения нашлось
+var workGroup = bson.A{
+	bson.M{"$group": bson.M{
+		"_id":      bson.M{"$ifNull": bson.A{"$workId", "$_id"}},
+		"book":     bson.M{"$first": "$$ROOT"},
+		"editions": bson.M{"$sum": 1},
+	}},
+	bson.M{"$replaceRoot": bson.M{"newRoot": bson.M{"$mergeObjects": bson.A{"$book", bson.M{"editions": "$editions"}}}}},
+}
+
+// SearchPage — страница каталога; при полнотекстовом запросе по умолчанию сортирует по релевантности
+func (r *BookRepoMongo) SearchPage(ctx context.Context, filter domain.BookFilter, page domain.PageRequest) (domain.Page[domain.Book], error) {
+	defSort := "title"
+	var projection bson.M
+	if filter.Query != "" && !filter.QueryByKeys {
+		defSort = "relevance"
+		projection = bson.M{"score": bson.M{"$meta": "textScore"}}
+	} else if strings.TrimPrefix(page.Sort, "-") == "relevance" {
+		return domain.Page[domain.Book]{}, customErr.ErrInvalidSort
+	}
+
+	query, err := r.searchQuery(ctx, filter)
+	if err != nil {
+		return domain.Page[domain.Book]{}, fmt.Errorf("BookRepoMongo.SearchPage: %w", err)
+	}
+	if filter.AvailableOnly || filter.GroupByWork {
+		var stages bson.A
+		if filter.AvailableOnly {
+			stages = append(stages, availableStages...)
+		}
+		if filter.GroupByWork {
+			stages = append(stages, workGroup...)
+		}
+		res, err := aggregatePage[domain.Book](ctx, r.col, query, stages, page, bookSorts, defSort, projection)
+		if err != nil {
+			return res, fmt.Errorf("BookRepoMongo.SearchPage: %w", err)
+		}
+		return res, nil
+	}
+	res, err := findPage[domain.Book](ctx, r.col, query, page, bookSorts, defSort, projection)
+	if err != nil {
+		return res, fmt.Errorf("BookRepoMongo.SearchPage: %w", err)
+	}
+	return res, nil
+}
+
+// CountByAuthor — число книг, в которых участвует автор
+func (r *BookRepoMongo) CountByAuthor(ctx context.Context, authorID primitive.ObjectID) (int64, error) {
+	count, err := r.col.CountDocuments(ctx, bson.M{"contributors.authorId": authorID})
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.CountByAuthor: %w", err)
+	}
+	return count, nil
+}
+
+// RelinkAuthor заменяет участника from на to с именем name во всех книгах и пересобирает
+// поле author из участников в роли автора. from == to — переименование.
+func (r *BookRepoMongo) RelinkAuthor(ctx context.Context, from, to primitive.ObjectID, name string) (int64, error) {
+	pipeline := bson.A{
+		bson.M{"$set": bson.M{"contributors": bson.M{"$map": bson.M{
+			"input": "$contributors",
+			"as":    "c",
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$$c.authorId", from}},
+				bson.M{"$mergeObjects": bson.A{"$$c", bson.M{"authorId": to, "name": name}}},
+				"$$c",
+			}},
+		}}}},
+		bson.M{"$set": bson.M{"author": bson.M{"$reduce": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": "$contributors",
+				"cond":  bson.M{"$eq": bson.A{"$$this.role", domain.RoleAuthor}},
+			}},
+			"initialValue": "",
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$$value", ""}},
+				"$$this.name",
+				bson.M{"$concat": bson.A{"$$value", ", ", "$$this.name"}},
+			}},
+		}}}},
+	}
+	res, err := r.col.UpdateMany(ctx, bson.M{"contributors.authorId": from}, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.RelinkAuthor: %w", err)
+	}
+	if res.ModifiedCount > 0 {
+		if err := r.refreshAuthorKeys(ctx, to); err != nil {
+			return res.ModifiedCount, fmt.Errorf("BookRepoMongo.RelinkAuthor: %w", err)
+		}
+	}
+	return res.ModifiedCount, nil
+}
+
+// refreshAuthorKeys пересчитывает ключи автора у книг с участником authorID: поле author
+// пересобрано пайплайном, а транслитерацию в пайплайне не посчитать
+func (r *BookRepoMongo) refreshAuthorKeys(ctx context.Context, authorID primitive.ObjectID) error {
+	opts := options.Find().SetProjection(bson.M{"author": 1})
+	cursor, err := r.col.Find(ctx, bson.M{"contributors.authorId": authorID}, opts)
+	if err != nil {
+		return fmt.Errorf("author keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			Author string             `bson:"author"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("author keys (decode): %w", err)
+		}
+		update := bson.M{"$set": bson.M{"keys.author": textsim.SearchKeys(doc.Author)}}
+		if _, err := r.col.UpdateByID(ctx, doc.ID, update); err != nil {
+			return fmt.Errorf("author keys (update): %w", err)
+		}
+	}
+	return cursor.Err()
+}
+
+// bookKeys — ключи поиска книги по названию и автору
+func bookKeys(title, author string) domain.BookKeys {
+	return domain.BookKeys{Title: textsim.SearchKeys(title), Author: textsim.SearchKeys(author)}
+}
+
+// KeyVocabulary — ключи поля field ("title" или "author"), начинающиеся с first: словарь для исправления опечаток
+func (r *BookRepoMongo) KeyVocabulary(ctx context.Context, field, first string) ([]string, error) {
+	path := "keys." + field
+	values, err := r.col.Distinct(ctx, path, bson.M{
+		path:         keyPrefix(first),
+		"mergedInto": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("BookRepoMongo.KeyVocabulary: %w", err)
+	}
+	return vocabulary(values, first), nil
+}
+
+// facetLimit — сколько самых частых значений жанра и автора возвращать
+const facetLimit = 20
+
+// Facets считает фасеты по книгам, подходящим под фильтр, одной агрегацией $facet
+func (r *BookRepoMongo) Facets(ctx context.Context, filter domain.BookFilter) (domain.BookFacets, error) {
+	query, err := r.searchQuery(ctx, filter)
+	if err != nil {
+		return domain.BookFacets{}, fmt.Errorf("BookRepoMongo.Facets: %w", err)
+	}
+
+	countBy := func(expr any) bson.M {
+		return bson.M{"$group": bson.M{"_id": expr, "count": bson.M{"$sum": 1}}}
+	}
+	byCount := bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}
+
+	pipeline := bson.A{bson.M{"$match": query}}
+	if filter.AvailableOnly {
+		pipeline = append(pipeline, availableStages...)
+	}
+	pipeline = append(pipeline,
+		bson.M{"$facet": bson.M{
+			"genre":  bson.A{countBy("$genre"), byCount, bson.M{"$limit": facetLimit}},
+			"author": bson.A{countBy("$author"), byCount, bson.M{"$limit": facetLimit}},
+			"decade": bson.A{
+				bson.M{"$match": bson.M{"year": bson.M{"$gt": 0}}},
+				countBy(bson.M{"$multiply": bson.A{bson.M{"$floor": bson.M{"$divide": bson.A{"$year", 10}}}, 10}}),
+				bson.M{"$sort": bson.M{"_id": 1}},
+				bson.M{"$project": bson.M{"_id": bson.M{"$toString": "$_id"}, "count": 1}},
+			},
+			// Книга доступна, если хотя бы один её экземпляр свободен
+			"availability": bson.A{
+				freeItemLookup,
+				countBy(bson.M{"$cond": bson.A{
+					bson.M{"$gt": bson.A{bson.M{"$size": "$free"}, 0}},
+					domain.FacetAvailable,
+					domain.FacetUnavailable,
+				}}),
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+		}},
+	)
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return domain.BookFacets{}, fmt.Errorf("BookRepoMongo.Facets (aggregate): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var facets domain.BookFacets
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&facets); err != nil {
+			return domain.BookFacets{}, fmt.Errorf("BookRepoMongo.Facets (decode): %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return domain.BookFacets{}, fmt.Errorf("BookRepoMongo.Facets (cursor): %w", err)
+	}
+	return facets, nil
+}
+
+// searchQuery — фильтр каталога для Search, SearchPage и Facets
+func (r *BookRepoMongo) searchQuery(ctx context.Context, filter domain.BookFilter) (bson.M, error) {
+	// Влитые книги — только перенаправления, в выборки они не попадают
+	query := bson.M{"mergedInto": bson.M{"$exists": false}}
+
+	switch filter.Withdrawn {
+	case domain.WithdrawnExclude:
+		query["withdrawn"] = bson.M{"$exists": false}
+	case domain.WithdrawnOnly:
+		query["withdrawn"] = bson.M{"$exists": true}
+	}
+
+	var and bson.A
+	// Полнотекстовый поиск по индексу books_text (title, author, genre) с русским стеммингом.
+	// QueryByKeys — запасной путь: каждое слово запроса ищется в ключах названия или автора
+	if filter.Query != "" && filter.QueryByKeys {
+		for _, key := range textsim.SearchKeys(queryWords(filter.Query)) {
+			prefix := keyPrefix(key)
+			and = append(and, bson.M{"$or": bson.A{
+				bson.M{"keys.title": prefix},
+				bson.M{"keys.author": prefix},
+			}})
+		}
+	} else if filter.Query != "" {
+		query["$text"] = bson.M{"$search": filter.Query, "$language": "russian"}
+	}
+	// Название и автор — подстрока как есть или все слова по ключам (транслитерация, ё/е, регистр)
+	if filter.Title != "" {
+		and = append(and, textCondition("title", "keys.title", filter.Title))
+	}
+	if filter.Author != "" {
+		and = append(and, textCondition("author", "keys.author", filter.Author))
+	}
+	if len(filter.Genres) > 0 {
+		cond, err := r.genreCondition(ctx, filter.Genres)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, cond)
+	}
+	if len(and) > 0 {
+		query["$and"] = and
+	}
+	if filter.ISBN != "" {
+		// Фрагмент ISBN уже без дефисов (нормализуется в BookUsecase)
+		part := regexp.QuoteMeta(filter.ISBN)
+		query["$or"] = bson.A{
+			bson.M{"isbn13": bson.M{"$regex": part}},
+			bson.M{"isbn10": bson.M{"$regex": part}},
+		}
+	}
+	if filter.AuthorID != "" {
+		authorID, err := primitive.ObjectIDFromHex(filter.AuthorID)
+		if err != nil {
+			return nil, customErr.ErrInvalidID
+		}
+		query["contributors.authorId"] = authorID
+	}
+	if filter.WorkID != "" {
+		query["workId"] = filter.WorkID
+	}
+	if filter.SeriesID != "" {
+		query["series.seriesId"] = filter.SeriesID
+	}
+	if filter.Decade != 0 {
+		query["year"] = bson.M{"$gte": filter.Decade, "$lt": filter.Decade + 10}
+	}
+	// AvailableOnly — не условие фильтра, а стадии availableStages после него
+	return query, nil
+}
+
+// genreCondition — книги жанров values (ID или название/синоним из рубрикатора) и всех их поджанров.
+// Строка, которой нет в рубрикаторе, по-прежнему сравнивается с полем genre как есть
+func (r *BookRepoMongo) genreCondition(ctx context.Context, values []string) (bson.M, error) {
+	objIDs, keys := bson.A{}, bson.A{}
+	for _, v := range values {
+		if oid, err := primitive.ObjectIDFromHex(v); err == nil {
+			objIDs = append(objIDs, oid)
+		} else if key := textsim.Key(v); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	roots, err := r.genres.Distinct(ctx, "_id", bson.M{"$or": bson.A{
+		bson.M{"_id": bson.M{"$in": objIDs}},
+		bson.M{"keys": bson.M{"$in": keys}},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("genres: %w", err)
+	}
+	ids := bson.A{}
+	if len(roots) > 0 {
+		hexes := make(bson.A, 0, len(roots))
+		for _, id := range roots {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				hexes = append(hexes, oid.Hex())
+			}
+		}
+		descendants, err := r.genres.Distinct(ctx, "_id", bson.M{"ancestors": bson.M{"$in": hexes}})
+		if err != nil {
+			return nil, fmt.Errorf("subgenres: %w", err)
+		}
+		ids = append(ids, hexes...)
+		for _, id := range descendants {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				ids = append(ids, oid.Hex())
+			}
+		}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"genreId": bson.M{"$in": ids}},
+		bson.M{"genre": bson.M{"$in": values}},
+	}}, nil
+}
+
+// queryWords — слова полнотекстового запроса без исключений (-слово) и кавычек
+func queryWords(q string) string {
+	var words []string
+	for _, w := range strings.Fields(q) {
+		if !strings.HasPrefix(w, "-") {
+			words = append(words, strings.Trim(w, `"`))
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// CountByGenre — число книг каталога (без списанных и влитых) по ID жанра; ключ "" — книги вне рубрикатора
+func (r *BookRepoMongo) CountByGenre(ctx context.Context) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"withdrawn":  bson.M{"$exists": false},
+			"mergedInto": bson.M{"$exists": false},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$genreId", ""}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("BookRepoMongo.CountByGenre: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var buckets []domain.FacetBucket
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, fmt.Errorf("BookRepoMongo.CountByGenre (decode): %w", err)
+	}
+	counts := make(map[string]int64, len(buckets))
+	for _, b := range buckets {
+		counts[b.Value] = b.Count
+	}
+	return counts, nil
+}
+
+// CountInGenre — число книг со ссылкой на жанр, включая списанные и влитые
+func (r *BookRepoMongo) CountInGenre(ctx context.Context, genreID string) (int64, error) {
+	count, err := r.col.CountDocuments(ctx, bson.M{"genreId": genreID})
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.CountInGenre: %w", err)
+	}
+	return count, nil
+}
+
+// CountInWork — число изданий произведения, включая списанные
+func (r *BookRepoMongo) CountInWork(ctx context.Context, workID string) (int64, error) {
+	count, err := r.col.CountDocuments(ctx, bson.M{"workId": workID})
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.CountInWork: %w", err)
+	}
+	return count, nil
+}
+
+// CountInSeries — число книг серии, включая списанные
+func (r *BookRepoMongo) CountInSeries(ctx context.Context, seriesID string) (int64, error) {
+	count, err := r.col.CountDocuments(ctx, bson.M{"series.seriesId": seriesID})
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.CountInSeries: %w", err)
+	}
+	return count, nil
+}
+
+// RenameSeries записывает новое название серии во все её книги
+func (r *BookRepoMongo) RenameSeries(ctx context.Context, seriesID, title string) (int64, error) {
+	res, err := r.col.UpdateMany(ctx, bson.M{"series.seriesId": seriesID}, bson.M{"$set": bson.M{"series.title": title}})
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.RenameSeries: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+// RelinkGenre переносит книги жанра from на жанр to и записывает в них его название label.
+// from == to — переименование
+func (r *BookRepoMongo) RelinkGenre(ctx context.Context, from, to, label string) (int64, error) {
+	res, err := r.col.UpdateMany(ctx, bson.M{"genreId": from}, bson.M{"$set": bson.M{
+		"genreId": to,
+		"genre":   label,
+	}})
+	if err != nil {
+		return 0, fmt.Errorf("BookRepoMongo.RelinkGenre: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
 func (r *BookRepoMongo) Count(ctx context.Context) (int64, error) {
-	count, err := r.col.CountDocuments(ctx, bson.M{})
+	count, err := r.col.CountDocuments(ctx, bson.M{
+		"withdrawn":  bson.M{"$exists": false},
+		"mergedInto": bson.M{"$exists": false},
+	})
 	if err != nil {
 		return 0, fmt.Errorf("BookRepoMongo.Count: %w", err)
 	}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+)
+
+// createRevisionAttempts — сколько раз брать следующий номер, если его параллельно занял другой запрос
+const createRevisionAttempts = 3
+
+type BookRevisionRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewBookRevisionRepo(db *mongo.Database) *BookRevisionRepoMongo {
+	return &BookRevisionRepoMongo{
+		col: db.Collection("book_revisions"),
+	}
+}
+
+// Create присваивает ревизии следующий номер книги; уникальный индекс (bookId, number)
+// не даёт двум параллельным изменениям получить один номер
+func (r *BookRevisionRepoMongo) Create(ctx context.Context, rev *domain.BookRevision) error {
+	for i := 0; i < createRevisionAttempts; i++ {
+		last, err := r.lastNumber(ctx, rev.BookID)
+		if err != nil {
+			return fmt.Errorf("BookRevisionRepoMongo.Create: %w", err)
+		}
+		rev.Number = last + 1
+
+		doc := *rev
+		doc.ID = ""
+		res, err := r.col.InsertOne(ctx, doc)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("BookRevisionRepoMongo.Create: %w", err)
+		}
+
+		oid, ok := res.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return fmt.Errorf("BookRevisionRepoMongo.Create: inserted ID is not ObjectID")
+		}
+		rev.ID = oid.Hex()
+		return nil
+	}
+	return fmt.Errorf("BookRevisionRepoMongo.Create: revision number of book %s is contended", rev.BookID.Hex())
+}
+
+func (r *BookRevisionRepoMongo) lastNumber(ctx context.Context, bookID primitive.ObjectID) (int, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "number", Value: -1}}).
+		SetProjection(bson.M{"number": 1})
+	var doc struct {
+		Number int `bson:"number"`
+	}
+	err := r.col.FindOne(ctx, bson.M{"bookId": bookID}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return doc.Number, nil
+}
+
+// GetByNumber — nil, если у книги нет ревизии с таким номером
+func (r *BookRevisionRepoMongo) GetByNumber(ctx context.Context, bookID primitive.ObjectID, number int) (*domain.BookRevision, error) {
+	var rev domain.BookRevision
+	err := r.col.FindOne(ctx, bson.M{"bookId": bookID, "number": number}).Decode(&rev)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("BookRevisionRepoMongo.GetByNumber: %w", err)
+	}
+	return &rev, nil
+}
+
+// revisionSorts — поля сортировки ревизий (параметр sort)
+var revisionSorts = pageSorts{
+	"number": "number",
+}
+
+// ListPage — ревизии книги, по умолчанию от новых к старым
+func (r *BookRevisionRepoMongo) ListPage(ctx context.Context, bookID primitive.ObjectID, page domain.PageRequest) (domain.Page[domain.BookRevision], error) {
+	res, err := findPage[domain.BookRevision](ctx, r.col, bson.M{"bookId": bookID}, page, revisionSorts, "-number", nil)
+	if err != nil {
+		return res, fmt.Errorf("BookRevisionRepoMongo.ListPage: %w", err)
+	}
+	return res, nil
+}
@@ -24,7 +24,7 @@ func NewBorrowRepo(db *mongo.Database) *BorrowRepoMongo {
 
 func (r *BorrowRepoMongo) Create(ctx context.Context, b *domain.Borrow) error {
 	filter := bson.M{
-		"bookId":     b.BookID,
+		"itemId":     b.ItemID,
 		"returnedAt": bson.M{"$exists": false},
 	}
 
@@ -40,6 +40,7 @@ func (r *BorrowRepoMongo) Create(ctx context.Context, b *domain.Borrow) error {
 	doc := bson.M{
 		"clientId":   b.ClientID,
 		"bookId":     b.BookID,
+		"itemId":     b.ItemID,
 		"borrowedAt": b.BorrowedAt,
 	}
 	if b.ReturnedAt != nil {
@@ -60,21 +61,23 @@ func (r *BorrowRepoMongo) Create(ctx context.Context, b *domain.Borrow) error {
 	return nil
 }
 
-func (r *BorrowRepoMongo) Close(ctx context.Context, borrowID string, returnTime time.Time) error {
+// Close закрывает только открытую выдачу: из двух параллельных возвратов true получит один
+func (r *BorrowRepoMongo) Close(ctx context.Context, borrowID string, returnTime time.Time) (bool, error) {
 	objID, err := primitive.ObjectIDFromHex(borrowID)
 	if err != nil {
-		return fmt.Errorf("BorrowRepoMongo.Close (parse ID): %w", err)
+		return false, fmt.Errorf("BorrowRepoMongo.Close (parse ID): %w", err)
 	}
+	filter := bson.M{"_id": objID, "returnedAt": nil}
 	update := bson.M{
 		"$set": bson.M{
 			"returnedAt": returnTime,
 		},
 	}
-	_, err = r.col.UpdateByID(ctx, objID, update)
+	res, err := r.col.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return fmt.Errorf("BorrowRepoMongo.Close (update): %w", err)
+		return false, fmt.Errorf("BorrowRepoMongo.Close (update): %w", err)
 	}
-	return nil
+	return res.ModifiedCount == 1, nil
 }
 
 func (r *BorrowRepoMongo) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Borrow, error) {
@@ -107,13 +110,7 @@ func (r *BorrowRepoMongo) GetByClientID(ctx context.Context, clientID primitive.
 
 // Отчет №2 (Вернуть список просроченных книг)
 func (r *BorrowRepoMongo) GetOverdue(ctx context.Context, now time.Time) ([]domain.Borrow, error) {
-	limit := now.AddDate(0, 0, -21)
-	filter := bson.M{
-		"returnedAt": bson.M{"$eq": nil},
-		"borrowedAt": bson.M{"$lt": limit},
-	}
-
-	cursor, err := r.col.Find(ctx, filter)
+	cursor, err := r.col.Find(ctx, overdueFilter(now))
 	if err != nil {
 		return nil, fmt.Errorf("BorrowRepoMongo.GetOverdue (find): %w", err)
 	}
@@ -126,16 +123,120 @@ func (r *BorrowRepoMongo) GetOverdue(ctx context.Context, now time.Time) ([]doma
 	return results, nil
 }
 
-// Отчет №3 (Вернуть кол-во пришедших читателей по дням за период)
-func (r *BorrowRepoMongo) GetDailyStats(ctx context.Context, from, to time.Time) ([]domain.BorrowStat, error) {
+// borrowSorts — поля сортировки выдач (параметр sort)
+var borrowSorts = pageSorts{
+	"borrowedAt": "borrowedAt",
+}
+
+// GetByClientIDPage — страница истории выдач читателя
+func (r *BorrowRepoMongo) GetByClientIDPage(ctx context.Context, clientID primitive.ObjectID, page domain.PageRequest) (domain.Page[domain.Borrow], error) {
+	res, err := findPage[domain.Borrow](ctx, r.col, bson.M{"clientId": clientID}, page, borrowSorts, "borrowedAt", nil)
+	if err != nil {
+		return res, fmt.Errorf("BorrowRepoMongo.GetByClientIDPage: %w", err)
+	}
+	return res, nil
+}
+
+// GetByBooksPage — страница выдач любой из книг (история произведения по всем изданиям)
+func (r *BorrowRepoMongo) GetByBooksPage(ctx context.Context, bookIDs []primitive.ObjectID, page domain.PageRequest) (domain.Page[domain.Borrow], error) {
+	res, err := findPage[domain.Borrow](ctx, r.col, bson.M{"bookId": bson.M{"$in": bookIDs}}, page, borrowSorts, "borrowedAt", nil)
+	if err != nil {
+		return res, fmt.Errorf("BorrowRepoMongo.GetByBooksPage: %w", err)
+	}
+	return res, nil
+}
+
+// StatsByBook — число выдач, невозвращённых выдач и читателей по каждой из книг
+func (r *BorrowRepoMongo) StatsByBook(ctx context.Context, bookIDs []primitive.ObjectID) ([]domain.WorkBorrowStat, error) {
 	pipeline := mongo.Pipeline{
-		{{"$match", bson.M{
-			"borrowedAt": bson.M{
-				"$gte": from,
-				"$lte": to,
-			},
+		{{Key: "$match", Value: bson.M{"bookId": bson.M{"$in": bookIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$bookId",
+			"borrows": bson.M{"$sum": 1},
+			"active": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$returnedAt", nil}}, nil}}, 1, 0,
+			}}},
+			"readers": bson.M{"$addToSet": "$clientId"},
 		}}},
-		{{"$group", bson.M{
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("BorrowRepoMongo.StatsByBook (aggregate): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []domain.WorkBorrowStat
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("BorrowRepoMongo.StatsByBook (decode): %w", err)
+	}
+	return stats, nil
+}
+
+// GetOverduePage — страница отчёта о просрочках
+func (r *BorrowRepoMongo) GetOverduePage(ctx context.Context, now time.Time, page domain.PageRequest) (domain.Page[domain.Borrow], error) {
+	res, err := findPage[domain.Borrow](ctx, r.col, overdueFilter(now), page, borrowSorts, "borrowedAt", nil)
+	if err != nil {
+		return res, fmt.Errorf("BorrowRepoMongo.GetOverduePage: %w", err)
+	}
+	return res, nil
+}
+
+// CountOverdueByClients — число просроченных выдач у каждого из читателей (ключ — ID читателя)
+func (r *BorrowRepoMongo) CountOverdueByClients(ctx context.Context, now time.Time, clientIDs []primitive.ObjectID) (map[string]int, error) {
+	match := overdueFilter(now)
+	match["clientId"] = bson.M{"$in": clientIDs}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$clientId", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("BorrowRepoMongo.CountOverdueByClients (aggregate): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	res := make(map[string]int, len(clientIDs))
+	for cursor.Next(ctx) {
+		var row struct {
+			ClientID primitive.ObjectID `bson:"_id"`
+			Count    int                `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, fmt.Errorf("BorrowRepoMongo.CountOverdueByClients (decode): %w", err)
+		}
+		res[row.ClientID.Hex()] = row.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("BorrowRepoMongo.CountOverdueByClients (cursor): %w", err)
+	}
+	return res, nil
+}
+
+// overdueFilter — активные выдачи старше 21 дня
+func overdueFilter(now time.Time) bson.M {
+	return bson.M{
+		"returnedAt": bson.M{"$eq": nil},
+		"borrowedAt": bson.M{"$lt": now.AddDate(0, 0, -21)},
+	}
+}
+
+// Отчет №3 (Вернуть кол-во пришедших читателей по дням за период)
+// GetDailyStats — уникальные читатели по дням; bookIDs != nil — только выдачи этих книг
+func (r *BorrowRepoMongo) GetDailyStats(ctx context.Context, from, to time.Time, bookIDs []primitive.ObjectID) ([]domain.BorrowStat, error) {
+	match := bson.M{
+		"borrowedAt": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	if bookIDs != nil {
+		match["bookId"] = bson.M{"$in": bookIDs}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
 			"_id": bson.M{
 				"$dateToString": bson.M{
 					"format": "%Y-%m-%d",
@@ -144,12 +245,12 @@ func (r *BorrowRepoMongo) GetDailyStats(ctx context.Context, from, to time.Time)
 			},
 			"clients": bson.M{"$addToSet": "$clientId"},
 		}}},
-		{{"$project", bson.M{
+		{{Key: "$project", Value: bson.M{
 			"date":          "$_id",
 			"uniqueReaders": bson.M{"$size": "$clients"},
 			"_id":           0,
 		}}},
-		{{"$sort", bson.M{"date": 1}}},
+		{{Key: "$sort", Value: bson.M{"date": 1}}},
 	}
 
 	cursor, err := r.col.Aggregate(ctx, pipeline)
@@ -173,14 +274,31 @@ func (r *BorrowRepoMongo) CountActive(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-func (r *BorrowRepoMongo) HasActiveBorrow(ctx context.Context, bookID primitive.ObjectID) (bool, error) {
+func (r *BorrowRepoMongo) CountActiveByBook(ctx context.Context, bookID primitive.ObjectID) (int64, error) {
+	count, err := r.col.CountDocuments(ctx, bson.M{"bookId": bookID, "returnedAt": nil})
+	if err != nil {
+		return 0, fmt.Errorf("BorrowRepoMongo.CountActiveByBook: %w", err)
+	}
+	return count, nil
+}
+
+// RelinkBook переносит все выдачи книги from, в том числе закрытые, на книгу to
+func (r *BorrowRepoMongo) RelinkBook(ctx context.Context, from, to primitive.ObjectID) (int64, error) {
+	res, err := r.col.UpdateMany(ctx, bson.M{"bookId": from}, bson.M{"$set": bson.M{"bookId": to}})
+	if err != nil {
+		return 0, fmt.Errorf("BorrowRepoMongo.RelinkBook: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *BorrowRepoMongo) HasActiveBorrow(ctx context.Context, itemID primitive.ObjectID) (bool, error) {
 	filter := bson.M{
-		"bookId":     bookID,
+		"itemId":     itemID,
 		"returnedAt": bson.M{"$exists": false},
 	}
 	count, err := r.col.CountDocuments(ctx, filter)
 	if err != nil {
-		return false, fmt.Errorf("BorrowRepoMongo.IsBookCurrentlyBorrowed: %w", err)
+		return false, fmt.Errorf("BorrowRepoMongo.HasActiveBorrow: %w", err)
 	}
 	return count > 0, nil
 }
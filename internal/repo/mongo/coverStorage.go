@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
+	customErr "library-Mongo/internal/errors"
+)
+
+// CoverStorageGridFS хранит обложки в GridFS-бакете covers; имя файла — "<bookID>/<variant>"
+type CoverStorageGridFS struct {
+	db *mongo.Database
+}
+
+func NewCoverStorage(db *mongo.Database) *CoverStorageGridFS {
+	return &CoverStorageGridFS{db: db}
+}
+
+// bucket создаётся на каждую операцию: сроки чтения и записи задаются бакету, а не запросу
+func (s *CoverStorageGridFS) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	b, err := gridfs.NewBucket(s.db, options.GridFSBucket().SetName("covers"))
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = b.SetReadDeadline(deadline)
+		_ = b.SetWriteDeadline(deadline)
+	}
+	return b, nil
+}
+
+func coverFilename(bookID, variant string) string {
+	return bookID + "/" + variant
+}
+
+// Save загружает новый файл и только потом удаляет прежние с тем же именем
+func (s *CoverStorageGridFS) Save(ctx context.Context, bookID, variant string, data []byte) error {
+	b, err := s.bucket(ctx)
+	if err != nil {
+		return fmt.Errorf("CoverStorageGridFS.Save: %w", err)
+	}
+	filename := coverFilename(bookID, variant)
+	opts := options.GridFSUpload().SetMetadata(bson.M{"bookId": bookID, "variant": variant})
+	id, err := b.UploadFromStream(filename, bytes.NewReader(data), opts)
+	if err != nil {
+		return fmt.Errorf("CoverStorageGridFS.Save (upload): %w", err)
+	}
+
+	if err := s.deleteFiles(ctx, b, bson.M{"filename": filename, "_id": bson.M{"$ne": id}}); err != nil {
+		return fmt.Errorf("CoverStorageGridFS.Save: %w", err)
+	}
+	return nil
+}
+
+func (s *CoverStorageGridFS) Open(ctx context.Context, bookID, variant string) (io.ReadCloser, error) {
+	b, err := s.bucket(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("CoverStorageGridFS.Open: %w", err)
+	}
+	stream, err := b.OpenDownloadStreamByName(coverFilename(bookID, variant))
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, customErr.ErrCoverNotFound
+		}
+		return nil, fmt.Errorf("CoverStorageGridFS.Open: %w", err)
+	}
+	return stream, nil
+}
+
+func (s *CoverStorageGridFS) DeleteAll(ctx context.Context, bookID string) error {
+	b, err := s.bucket(ctx)
+	if err != nil {
+		return fmt.Errorf("CoverStorageGridFS.DeleteAll: %w", err)
+	}
+	if err := s.deleteFiles(ctx, b, bson.M{"metadata.bookId": bookID}); err != nil {
+		return fmt.Errorf("CoverStorageGridFS.DeleteAll: %w", err)
+	}
+	return nil
+}
+
+func (s *CoverStorageGridFS) deleteFiles(ctx context.Context, b *gridfs.Bucket, filter bson.M) error {
+	cursor, err := b.FindContext(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("find: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var file struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+		if err := b.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return fmt.Errorf("delete %s: %w", file.ID.Hex(), err)
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,178 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/textsim"
+	"time"
+)
+
+type GenreRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewGenreRepo(db *mongo.Database) *GenreRepoMongo {
+	return &GenreRepoMongo{
+		col: db.Collection("genres"),
+	}
+}
+
+func (r *GenreRepoMongo) Create(ctx context.Context, g *domain.Genre) error {
+	doc := bson.M{
+		"ancestors": g.Ancestors,
+		"labelRu":   g.LabelRU,
+		"keys":      g.Keys,
+		"createdAt": g.CreatedAt,
+		"updatedAt": g.UpdatedAt,
+	}
+	if g.ParentID != "" {
+		doc["parentId"] = g.ParentID
+	}
+	if g.LabelEN != "" {
+		doc["labelEn"] = g.LabelEN
+	}
+	if len(g.Synonyms) > 0 {
+		doc["synonyms"] = g.Synonyms
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		// Ключи уникальны: одно написание не может означать два жанра
+		if mongo.IsDuplicateKeyError(err) {
+			return customErr.ErrGenreLabelTaken
+		}
+		return fmt.Errorf("GenreRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("GenreRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	g.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *GenreRepoMongo) Update(ctx context.Context, g *domain.Genre) error {
+	objID, err := primitive.ObjectIDFromHex(g.ID)
+	if err != nil {
+		return fmt.Errorf("GenreRepoMongo.Update: %w", err)
+	}
+	set := bson.M{
+		"ancestors": g.Ancestors,
+		"labelRu":   g.LabelRU,
+		"labelEn":   g.LabelEN,
+		"synonyms":  g.Synonyms,
+		"keys":      g.Keys,
+		"updatedAt": g.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if g.ParentID != "" {
+		set["parentId"] = g.ParentID
+	} else {
+		update["$unset"] = bson.M{"parentId": ""}
+	}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return customErr.ErrGenreLabelTaken
+		}
+		return fmt.Errorf("GenreRepoMongo.Update: %w", err)
+	}
+	return nil
+}
+
+// Rebase переносит поддерево: у всех потомков id часть Ancestors до id включительно заменяется
+// на path, а parentId становится последним элементом нового пути. Возвращает число потомков
+func (r *GenreRepoMongo) Rebase(ctx context.Context, id string, path []string, now time.Time) (int64, error) {
+	pipeline := bson.A{
+		bson.M{"$set": bson.M{
+			"ancestors": bson.M{"$concatArrays": bson.A{
+				path,
+				bson.M{"$slice": bson.A{
+					"$ancestors",
+					bson.M{"$add": bson.A{bson.M{"$indexOfArray": bson.A{"$ancestors", id}}, 1}},
+					bson.M{"$add": bson.A{bson.M{"$size": "$ancestors"}, 1}},
+				}},
+			}},
+			"updatedAt": now,
+		}},
+		bson.M{"$set": bson.M{"parentId": bson.M{"$arrayElemAt": bson.A{"$ancestors", -1}}}},
+	}
+	res, err := r.col.UpdateMany(ctx, bson.M{"ancestors": id}, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("GenreRepoMongo.Rebase: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *GenreRepoMongo) GetByID(ctx context.Context, id string) (*domain.Genre, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("GenreRepoMongo.GetByID: %w", err)
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+// FindByLabel ищет жанр, у которого название или синоним совпадает с label по textsim.Key
+func (r *GenreRepoMongo) FindByLabel(ctx context.Context, label string) (*domain.Genre, error) {
+	key := textsim.Key(label)
+	if key == "" {
+		return nil, nil
+	}
+	return r.findOne(ctx, bson.M{"keys": key})
+}
+
+func (r *GenreRepoMongo) findOne(ctx context.Context, filter bson.M) (*domain.Genre, error) {
+	var g domain.Genre
+	err := r.col.FindOne(ctx, filter).Decode(&g)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GenreRepoMongo.findOne: %w", err)
+	}
+	return &g, nil
+}
+
+// All — весь рубрикатор по русским названиям; жанров сотни, не миллионы
+func (r *GenreRepoMongo) All(ctx context.Context) ([]domain.Genre, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "labelRu", Value: 1}})
+	cursor, err := r.col.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("GenreRepoMongo.All: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	genres := []domain.Genre{}
+	if err := cursor.All(ctx, &genres); err != nil {
+		return nil, fmt.Errorf("GenreRepoMongo.All (decode): %w", err)
+	}
+	return genres, nil
+}
+
+// CountChildren — число непосредственных поджанров
+func (r *GenreRepoMongo) CountChildren(ctx context.Context, id string) (int64, error) {
+	count, err := r.col.CountDocuments(ctx, bson.M{"parentId": id})
+	if err != nil {
+		return 0, fmt.Errorf("GenreRepoMongo.CountChildren: %w", err)
+	}
+	return count, nil
+}
+
+func (r *GenreRepoMongo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("GenreRepoMongo.Delete: %w", err)
+	}
+	if _, err := r.col.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		return fmt.Errorf("GenreRepoMongo.Delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+)
+
+type ItemRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewItemRepo(db *mongo.Database) *ItemRepoMongo {
+	return &ItemRepoMongo{
+		col: db.Collection("items"),
+	}
+}
+
+func (r *ItemRepoMongo) Create(ctx context.Context, item *domain.Item) error {
+	doc := bson.M{
+		"bookId":     item.BookID,
+		"barcode":    item.Barcode,
+		"status":     item.Status,
+		"location":   item.Location,
+		"acquiredAt": item.AcquiredAt,
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return customErr.ErrBarcodeTaken
+		}
+		return fmt.Errorf("ItemRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("ItemRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	item.ID = oid.Hex()
+
+	return nil
+}
+
+// Update меняет экземпляр одной записью при условии, что его статус всё ещё from:
+// изменение не проходит наполовину и не затирает параллельную выдачу
+func (r *ItemRepoMongo) Update(ctx context.Context, item *domain.Item, from string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(item.ID)
+	if err != nil {
+		return false, fmt.Errorf("ItemRepoMongo.Update: %w", err)
+	}
+	filter := bson.M{"_id": objID, "status": from}
+	update := bson.M{"$set": bson.M{
+		"barcode":    item.Barcode,
+		"status":     item.Status,
+		"location":   item.Location,
+		"acquiredAt": item.AcquiredAt,
+	}}
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, customErr.ErrBarcodeTaken
+		}
+		return false, fmt.Errorf("ItemRepoMongo.Update: %w", err)
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (r *ItemRepoMongo) GetByID(ctx context.Context, id string) (*domain.Item, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("ItemRepoMongo.GetByID: %w", err)
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *ItemRepoMongo) GetByBarcode(ctx context.Context, barcode string) (*domain.Item, error) {
+	return r.findOne(ctx, bson.M{"barcode": barcode})
+}
+
+// FindAvailable — любой свободный экземпляр книги
+func (r *ItemRepoMongo) FindAvailable(ctx context.Context, bookID primitive.ObjectID) (*domain.Item, error) {
+	return r.findOne(ctx, bson.M{"bookId": bookID, "status": domain.ItemAvailable})
+}
+
+func (r *ItemRepoMongo) findOne(ctx context.Context, filter bson.M) (*domain.Item, error) {
+	var item domain.Item
+	err := r.col.FindOne(ctx, filter).Decode(&item)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ItemRepoMongo.findOne: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *ItemRepoMongo) ListByBook(ctx context.Context, bookID primitive.ObjectID) ([]domain.Item, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "barcode", Value: 1}})
+	cursor, err := r.col.Find(ctx, bson.M{"bookId": bookID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("ItemRepoMongo.ListByBook (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var items []domain.Item
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("ItemRepoMongo.ListByBook (decode): %w", err)
+	}
+	return items, nil
+}
+
+// SetStatus атомарно переводит экземпляр из статуса from в to; false — статус уже другой
+func (r *ItemRepoMongo) SetStatus(ctx context.Context, id primitive.ObjectID, from, to string) (bool, error) {
+	filter := bson.M{"_id": id, "status": from}
+	update := bson.M{"$set": bson.M{"status": to}}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("ItemRepoMongo.SetStatus: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// Availability считает экземпляры по книгам одним агрегатом; книги без экземпляров в ответ не попадают
+func (r *ItemRepoMongo) Availability(ctx context.Context, bookIDs []primitive.ObjectID) (map[string]domain.Availability, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"bookId": bson.M{"$in": bookIDs},
+			"status": bson.M{"$ne": domain.ItemLost},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$bookId",
+			"total": bson.M{"$sum": 1},
+			"available": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", domain.ItemAvailable}}, 1, 0},
+			}},
+		}}},
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("ItemRepoMongo.Availability (aggregate): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	res := make(map[string]domain.Availability, len(bookIDs))
+	for cursor.Next(ctx) {
+		var row struct {
+			BookID    primitive.ObjectID `bson:"_id"`
+			Total     int                `bson:"total"`
+			Available int                `bson:"available"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, fmt.Errorf("ItemRepoMongo.Availability (decode): %w", err)
+		}
+		res[row.BookID.Hex()] = domain.Availability{BookID: row.BookID.Hex(), Total: row.Total, Available: row.Available}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("ItemRepoMongo.Availability (cursor): %w", err)
+	}
+	return res, nil
+}
+
+func (r *ItemRepoMongo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("ItemRepoMongo.Delete: %w", err)
+	}
+	if _, err := r.col.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		return fmt.Errorf("ItemRepoMongo.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *ItemRepoMongo) DeleteByBook(ctx context.Context, bookID primitive.ObjectID) error {
+	if _, err := r.col.DeleteMany(ctx, bson.M{"bookId": bookID}); err != nil {
+		return fmt.Errorf("ItemRepoMongo.DeleteByBook: %w", err)
+	}
+	return nil
+}
+
+// RelinkBook переносит все экземпляры книги from на книгу to
+func (r *ItemRepoMongo) RelinkBook(ctx context.Context, from, to primitive.ObjectID) (int64, error) {
+	res, err := r.col.UpdateMany(ctx, bson.M{"bookId": from}, bson.M{"$set": bson.M{"bookId": to}})
+	if err != nil {
+		return 0, fmt.Errorf("ItemRepoMongo.RelinkBook: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,119 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type LoginAttemptRepoMongo struct {
+	col    *mongo.Collection
+	events *mongo.Collection
+}
+
+func NewLoginAttemptRepo(db *mongo.Database) *LoginAttemptRepoMongo {
+	return &LoginAttemptRepoMongo{
+		col:    db.Collection("login_attempts"),
+		events: db.Collection("lockout_events"),
+	}
+}
+
+func (r *LoginAttemptRepoMongo) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	var a domain.LoginAttempt
+	err := r.col.FindOne(ctx, bson.M{"_id": key}).Decode(&a)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("LoginAttemptRepoMongo.Get: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *LoginAttemptRepoMongo) RegisterFailure(ctx context.Context, key string, now, expireAt time.Time) (*domain.LoginAttempt, error) {
+	update := bson.M{
+		"$inc": bson.M{"failures": 1},
+		"$set": bson.M{
+			"lastFailure": now,
+			"expireAt":    expireAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var a domain.LoginAttempt
+	if err := r.col.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&a); err != nil {
+		return nil, fmt.Errorf("LoginAttemptRepoMongo.RegisterFailure: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *LoginAttemptRepoMongo) Lock(ctx context.Context, key string, until, expireAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"lockedUntil": until,
+		"expireAt":    expireAt,
+	}}
+	if _, err := r.col.UpdateByID(ctx, key, update); err != nil {
+		return fmt.Errorf("LoginAttemptRepoMongo.Lock: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepoMongo) Reset(ctx context.Context, key string) error {
+	if _, err := r.col.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("LoginAttemptRepoMongo.Reset: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepoMongo) CreateEvent(ctx context.Context, e *domain.LockoutEvent) error {
+	doc := bson.M{
+		"kind":        e.Kind,
+		"value":       e.Value,
+		"failures":    e.Failures,
+		"ip":          e.IP,
+		"lockedUntil": e.LockedUntil,
+		"createdAt":   e.CreatedAt,
+	}
+
+	res, err := r.events.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("LoginAttemptRepoMongo.CreateEvent: %w", err)
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		e.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepoMongo) ListEvents(ctx context.Context, filter domain.LockoutEventFilter) ([]domain.LockoutEvent, error) {
+	query := bson.M{}
+	if !filter.Since.IsZero() {
+		query["createdAt"] = bson.M{"$gte": filter.Since}
+	}
+	if filter.Value != "" {
+		query["value"] = filter.Value
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := r.events.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("LoginAttemptRepoMongo.ListEvents (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []domain.LockoutEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("LoginAttemptRepoMongo.ListEvents (decode): %w", err)
+	}
+	return events, nil
+}
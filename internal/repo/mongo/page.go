@@ -0,0 +1,288 @@
+package mongo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+)
+
+// scoreField — сортировка по релевантности $text; курсор для неё хранит смещение,
+// потому что по textScore нельзя фильтровать
+const scoreField = "score"
+
+// pageSorts — допустимые значения параметра sort: имя -> поле документа
+type pageSorts map[string]string
+
+// resolve возвращает поле и направление сортировки; def — сортировка по умолчанию
+func (s pageSorts) resolve(sort, def string) (string, int, error) {
+	if sort == "" {
+		sort = def
+	}
+	dir := 1
+	if strings.HasPrefix(sort, "-") {
+		dir, sort = -1, sort[1:]
+	}
+	field, ok := s[sort]
+	if !ok {
+		return "", 0, customErr.ErrInvalidSort
+	}
+	return field, dir, nil
+}
+
+// pageCursor — позиция последней записи страницы: значение поля сортировки и _id
+// (для сортировки по релевантности — число уже выданных записей)
+type pageCursor struct {
+	Sort  string             `bson:"o"`
+	Value bson.RawValue      `bson:"v,omitempty"`
+	ID    primitive.ObjectID `bson:"id,omitempty"`
+	Skip  int64              `bson:"s,omitempty"`
+}
+
+func encodeCursor(c pageCursor) (string, error) {
+	raw, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor проверяет, что курсор выдан для той же сортировки
+func decodeCursor(s, sort string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, customErr.ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := bson.Unmarshal(raw, &c); err != nil || c.Sort != sort {
+		return pageCursor{}, customErr.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// findPage выбирает страницу коллекции по фильтру с keyset-пагинацией по (поле сортировки, _id).
+// Total считается по фильтру без учёта курсора.
+func findPage[T any](
+	ctx context.Context,
+	col *mongo.Collection,
+	filter bson.M,
+	page domain.PageRequest,
+	sorts pageSorts,
+	defSort string,
+	projection bson.M,
+) (domain.Page[T], error) {
+	field, dir, err := sorts.resolve(page.Sort, defSort)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+	sortKey := page.Sort
+	if sortKey == "" {
+		sortKey = defSort
+	}
+	limit := int64(page.Limit)
+	if limit <= 0 || limit > domain.MaxPageLimit {
+		limit = domain.DefaultPageLimit
+	}
+
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain.Page[T]{}, fmt.Errorf("count: %w", err)
+	}
+
+	query := filter
+	opts := options.Find().SetLimit(limit + 1)
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	var skip int64
+	if field == scoreField {
+		opts.SetSort(bson.D{{Key: scoreField, Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}})
+	} else {
+		opts.SetSort(bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}})
+	}
+
+	if page.After != "" {
+		cur, err := decodeCursor(page.After, sortKey)
+		if err != nil {
+			return domain.Page[T]{}, err
+		}
+		if field == scoreField {
+			skip = cur.Skip
+			opts.SetSkip(skip)
+		} else {
+			query = afterCursor(filter, field, dir, cur)
+		}
+	}
+
+	cursor, err := col.Find(ctx, query, opts)
+	if err != nil {
+		return domain.Page[T]{}, fmt.Errorf("find: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return domain.Page[T]{}, fmt.Errorf("decode: %w", err)
+	}
+	return pageOf[T](docs, total, limit, pageCursor{Sort: sortKey, Skip: skip}, field)
+}
+
+// aggregatePage — findPage для выборки, свёрнутой стадиями group (например, по произведению):
+// сортировка, курсор и Total относятся к документам после свёртки. До свёртки документы
+// сортируются так же, поэтому $first в группе — тот документ, что шёл бы первым в выдаче
+func aggregatePage[T any](
+	ctx context.Context,
+	col *mongo.Collection,
+	filter bson.M,
+	group bson.A,
+	page domain.PageRequest,
+	sorts pageSorts,
+	defSort string,
+	projection bson.M,
+) (domain.Page[T], error) {
+	field, dir, err := sorts.resolve(page.Sort, defSort)
+	if err != nil {
+		return domain.Page[T]{}, err
+	}
+	sortKey := page.Sort
+	if sortKey == "" {
+		sortKey = defSort
+	}
+	limit := int64(page.Limit)
+	if limit <= 0 || limit > domain.MaxPageLimit {
+		limit = domain.DefaultPageLimit
+	}
+
+	order := bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}}
+	if field == scoreField {
+		order = bson.D{{Key: scoreField, Value: -1}, {Key: "_id", Value: 1}}
+	}
+	grouped := bson.A{bson.M{"$match": filter}}
+	if projection != nil {
+		grouped = append(grouped, bson.M{"$addFields": projection})
+	}
+	grouped = append(grouped, bson.M{"$sort": order})
+	grouped = append(grouped, group...)
+
+	var counted []struct {
+		N int64 `bson:"n"`
+	}
+	countCursor, err := col.Aggregate(ctx, append(grouped[:len(grouped):len(grouped)], bson.M{"$count": "n"}))
+	if err != nil {
+		return domain.Page[T]{}, fmt.Errorf("count: %w", err)
+	}
+	if err := countCursor.All(ctx, &counted); err != nil {
+		return domain.Page[T]{}, fmt.Errorf("count: %w", err)
+	}
+	var total int64
+	if len(counted) > 0 {
+		total = counted[0].N
+	}
+
+	pipeline := append(grouped[:len(grouped):len(grouped)], bson.M{"$sort": order})
+	var skip int64
+	if page.After != "" {
+		cur, err := decodeCursor(page.After, sortKey)
+		if err != nil {
+			return domain.Page[T]{}, err
+		}
+		if field == scoreField {
+			skip = cur.Skip
+			pipeline = append(pipeline, bson.M{"$skip": skip})
+		} else {
+			pipeline = append(pipeline, bson.M{"$match": afterCursor(bson.M{}, field, dir, cur)})
+		}
+	}
+	pipeline = append(pipeline, bson.M{"$limit": limit + 1})
+
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return domain.Page[T]{}, fmt.Errorf("aggregate: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return domain.Page[T]{}, fmt.Errorf("decode: %w", err)
+	}
+	return pageOf[T](docs, total, limit, pageCursor{Sort: sortKey, Skip: skip}, field)
+}
+
+// pageOf декодирует страницу (limit+1 документ означает, что есть следующая) и выдаёт курсор
+// после её последней записи; cur — сортировка и смещение текущей страницы
+func pageOf[T any](docs []bson.Raw, total, limit int64, cur pageCursor, field string) (domain.Page[T], error) {
+	res := domain.Page[T]{Items: make([]T, 0, len(docs)), Total: total}
+	hasNext := int64(len(docs)) > limit
+	if hasNext {
+		docs = docs[:limit]
+	}
+	for _, doc := range docs {
+		var item T
+		if err := bson.Unmarshal(doc, &item); err != nil {
+			return domain.Page[T]{}, fmt.Errorf("decode: %w", err)
+		}
+		res.Items = append(res.Items, item)
+	}
+
+	if hasNext {
+		next := pageCursor{Sort: cur.Sort}
+		if field == scoreField {
+			next.Skip = cur.Skip + limit
+		} else {
+			last := docs[len(docs)-1]
+			next.Value = last.Lookup(strings.Split(field, ".")...)
+			if next.Value.Type == 0 {
+				next.Value = bson.RawValue{Type: bsontype.Null}
+			}
+			next.ID, _ = last.Lookup("_id").ObjectIDOK()
+		}
+		var err error
+		if res.Next, err = encodeCursor(next); err != nil {
+			return domain.Page[T]{}, fmt.Errorf("cursor: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// afterCursor добавляет к фильтру условие "после записи курсора" в порядке (field, _id).
+// Записи без поля сортировки (null) Mongo ставит раньше всех по возрастанию и позже всех по убыванию,
+// а {$gt: null} и {$lt: 5} их не находят (сравнение только внутри типа) — такие границы разобраны отдельно
+func afterCursor(filter bson.M, field string, dir int, cur pageCursor) bson.M {
+	op := "$gt"
+	if dir < 0 {
+		op = "$lt"
+	}
+	var or bson.A
+	if cur.Value.Type == bsontype.Null || cur.Value.Type == 0 {
+		or = bson.A{bson.M{field: nil, "_id": bson.M{op: cur.ID}}}
+		if dir > 0 {
+			or = append(or, bson.M{field: bson.M{"$ne": nil}})
+		}
+	} else {
+		or = bson.A{
+			bson.M{field: bson.M{op: cur.Value}},
+			bson.M{field: cur.Value, "_id": bson.M{op: cur.ID}},
+		}
+		if dir < 0 {
+			or = append(or, bson.M{field: nil})
+		}
+	}
+	cond := bson.M{"$or": or}
+
+	query := make(bson.M, len(filter)+1)
+	for k, v := range filter {
+		query[k] = v
+	}
+	and, _ := query["$and"].(bson.A)
+	query["$and"] = append(append(bson.A{}, and...), cond)
+	return query
+}
@@ -0,0 +1,199 @@
+package mongo
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Постраничный обход по полю, которое есть не у всех документов: каждая запись
+// должна попасть в выдачу ровно один раз и в порядке сортировки Mongo
+func TestAfterCursorMixedNullSortField(t *testing.T) {
+	years := []any{int32(2000), nil, int32(1990), missing{}, int32(2000), nil, int32(1980), missing{}, int32(1990)}
+	docs := make([]bson.M, 0, len(years))
+	for _, y := range years {
+		doc := bson.M{"_id": primitive.NewObjectID()}
+		if _, ok := y.(missing); !ok {
+			doc["year"] = y
+		}
+		docs = append(docs, doc)
+	}
+
+	for _, sortKey := range []string{"year", "-year"} {
+		for _, limit := range []int64{1, 2, 3, 4} {
+			field, dir, err := pageSorts{"year": "year"}.resolve(sortKey, "year")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := sortedDocs(docs, field, dir)
+
+			var got []bson.M
+			after := ""
+			for pages := 0; ; pages++ {
+				if pages > len(docs) {
+					t.Fatalf("sort=%s limit=%d: pagination does not terminate", sortKey, limit)
+				}
+				query := bson.M{}
+				if after != "" {
+					cur, err := decodeCursor(after, sortKey)
+					if err != nil {
+						t.Fatal(err)
+					}
+					query = afterCursor(query, field, dir, cur)
+				}
+				var raws []bson.Raw
+				for _, d := range sortedDocs(docs, field, dir) {
+					if int64(len(raws)) > limit {
+						break
+					}
+					if matches(d, query) {
+						raw, _ := bson.Marshal(d)
+						raws = append(raws, raw)
+					}
+				}
+				res, err := pageOf[bson.M](raws, int64(len(docs)), limit, pageCursor{Sort: sortKey}, field)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, res.Items...)
+				if res.Next == "" {
+					break
+				}
+				after = res.Next
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("sort=%s limit=%d: got %d documents, want %d", sortKey, limit, len(got), len(want))
+			}
+			for i := range want {
+				if got[i]["_id"] != want[i]["_id"] {
+					t.Fatalf("sort=%s limit=%d: document %d is %v, want %v", sortKey, limit, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+// missing — документ без поля year (в отличие от year: null)
+type missing struct{}
+
+// sortedDocs упорядочивает документы как Mongo: null и отсутствующее поле меньше любого числа,
+// при равенстве — по _id в том же направлении
+func sortedDocs(docs []bson.M, field string, dir int) []bson.M {
+	res := append([]bson.M(nil), docs...)
+	sort.SliceStable(res, func(i, j int) bool {
+		c := compareSort(res[i][field], res[j][field])
+		if c == 0 {
+			c = compareIDs(res[i]["_id"], res[j]["_id"])
+		}
+		return c*dir < 0
+	})
+	return res
+}
+
+func compareSort(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return int(a.(int32)) - int(b.(int32))
+}
+
+func compareIDs(a, b any) int {
+	x, y := a.(primitive.ObjectID), b.(primitive.ObjectID)
+	return bytes.Compare(x[:], y[:])
+}
+
+// matches — подмножество языка запросов Mongo, которое порождает afterCursor:
+// $and, $or, равенство, $gt, $lt, $ne; сравнение только внутри одного типа
+func matches(doc bson.M, filter bson.M) bool {
+	for key, cond := range filter {
+		switch key {
+		case "$and":
+			for _, c := range cond.(bson.A) {
+				if !matches(doc, c.(bson.M)) {
+					return false
+				}
+			}
+		case "$or":
+			found := false
+			for _, c := range cond.(bson.A) {
+				found = found || matches(doc, c.(bson.M))
+			}
+			if !found {
+				return false
+			}
+		default:
+			if !matchField(doc[key], cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchField(value, cond any) bool {
+	ops, ok := cond.(bson.M)
+	if !ok {
+		return equalValue(value, plain(cond))
+	}
+	for op, arg := range ops {
+		arg = plain(arg)
+		switch op {
+		case "$ne":
+			if equalValue(value, arg) {
+				return false
+			}
+		case "$gt", "$lt":
+			c, ok := compareSameType(value, arg)
+			if !ok || (op == "$gt" && c <= 0) || (op == "$lt" && c >= 0) {
+				return false
+			}
+		default:
+			panic("unsupported operator " + op)
+		}
+	}
+	return true
+}
+
+func equalValue(value, arg any) bool {
+	if arg == nil || value == nil {
+		return arg == nil && value == nil
+	}
+	c, ok := compareSameType(value, arg)
+	return ok && c == 0
+}
+
+func compareSameType(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case int32:
+		y, ok := b.(int32)
+		return int(x) - int(y), ok
+	case primitive.ObjectID:
+		y, ok := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:]), ok
+	}
+	return 0, false
+}
+
+// plain переводит значение курсора (bson.RawValue) в обычное Go-значение
+func plain(v any) any {
+	rv, ok := v.(bson.RawValue)
+	if !ok {
+		return v
+	}
+	switch rv.Type {
+	case 0, bson.TypeNull:
+		return nil
+	case bson.TypeInt32:
+		return rv.Int32()
+	}
+	panic("unsupported cursor value " + rv.Type.String())
+}
@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"library-Mongo/internal/textsim"
+)
+
+// keyPrefix — условие "ключ начинается с key"; якорь ^ позволяет Mongo пройти по индексу ключей
+func keyPrefix(key string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(key)}
+}
+
+// textCondition — поле содержит text как подстроку (без учёта регистра) или каждое слово text
+// начинает один из ключей keysField. Ввод пользователя экранируется: регулярное выражение
+// из запроса может надолго занять Mongo
+func textCondition(field, keysField, text string) bson.M {
+	substring := bson.M{field: bson.M{"$regex": regexp.QuoteMeta(text), "$options": "i"}}
+	keys := textsim.SearchKeys(text)
+	if len(keys) == 0 {
+		return substring
+	}
+	prefixes := make(bson.A, 0, len(keys))
+	for _, key := range keys {
+		prefixes = append(prefixes, keyPrefix(key))
+	}
+	return bson.M{"$or": bson.A{substring, bson.M{keysField: bson.M{"$all": prefixes}}}}
+}
+
+// vocabulary — строковые значения Distinct, начинающиеся с first, по алфавиту. Distinct по массиву
+// возвращает все ключи подошедших документов, а не только совпавшие
+func vocabulary(values []any, first string) []string {
+	var words []string
+	for _, v := range values {
+		if s, ok := v.(string); ok && strings.HasPrefix(s, first) {
+			words = append(words, s)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
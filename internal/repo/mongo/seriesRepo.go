@@ -0,0 +1,103 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"library-Mongo/internal/domain"
+)
+
+type SeriesRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewSeriesRepo(db *mongo.Database) *SeriesRepoMongo {
+	return &SeriesRepoMongo{
+		col: db.Collection("series"),
+	}
+}
+
+func (r *SeriesRepoMongo) Create(ctx context.Context, s *domain.Series) error {
+	res, err := r.col.InsertOne(ctx, bson.M{
+		"title":     s.Title,
+		"keys":      s.Keys,
+		"createdAt": s.CreatedAt,
+		"updatedAt": s.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("SeriesRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("SeriesRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	s.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *SeriesRepoMongo) Update(ctx context.Context, s *domain.Series) error {
+	objID, err := primitive.ObjectIDFromHex(s.ID)
+	if err != nil {
+		return fmt.Errorf("SeriesRepoMongo.Update: %w", err)
+	}
+	update := bson.M{"$set": bson.M{
+		"title":     s.Title,
+		"keys":      s.Keys,
+		"updatedAt": s.UpdatedAt,
+	}}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("SeriesRepoMongo.Update: %w", err)
+	}
+	return nil
+}
+
+// GetByID — nil, если серии нет
+func (r *SeriesRepoMongo) GetByID(ctx context.Context, id string) (*domain.Series, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("SeriesRepoMongo.GetByID: %w", err)
+	}
+	var s domain.Series
+	err = r.col.FindOne(ctx, bson.M{"_id": objID}).Decode(&s)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("SeriesRepoMongo.GetByID: %w", err)
+	}
+	return &s, nil
+}
+
+// seriesSorts — поля сортировки списка серий (параметр sort)
+var seriesSorts = pageSorts{
+	"title": "title",
+}
+
+// SearchPage — серии, название которых содержит title
+func (r *SeriesRepoMongo) SearchPage(ctx context.Context, title string, page domain.PageRequest) (domain.Page[domain.Series], error) {
+	query := bson.M{}
+	if title != "" {
+		query = textCondition("title", "keys", title)
+	}
+	res, err := findPage[domain.Series](ctx, r.col, query, page, seriesSorts, "title", nil)
+	if err != nil {
+		return res, fmt.Errorf("SeriesRepoMongo.SearchPage: %w", err)
+	}
+	return res, nil
+}
+
+func (r *SeriesRepoMongo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("SeriesRepoMongo.Delete: %w", err)
+	}
+	if _, err := r.col.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		return fmt.Errorf("SeriesRepoMongo.Delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type SessionRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewSessionRepo(db *mongo.Database) *SessionRepoMongo {
+	return &SessionRepoMongo{
+		col: db.Collection("sessions"),
+	}
+}
+
+func (r *SessionRepoMongo) Create(ctx context.Context, s *domain.Session) error {
+	doc := bson.M{
+		"userId":      s.UserID,
+		"refreshHash": s.RefreshHash,
+		"usedHashes":  bson.A{},
+		"userAgent":   s.UserAgent,
+		"ip":          s.IP,
+		"createdAt":   s.CreatedAt,
+		"lastUsedAt":  s.LastUsedAt,
+		"expiresAt":   s.ExpiresAt,
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("SessionRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("SessionRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	s.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *SessionRepoMongo) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("SessionRepoMongo.GetByID: %w", err)
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *SessionRepoMongo) GetByRefreshHash(ctx context.Context, hash string) (*domain.Session, error) {
+	return r.findOne(ctx, bson.M{"$or": bson.A{
+		bson.M{"refreshHash": hash},
+		bson.M{"usedHashes": hash},
+	}})
+}
+
+func (r *SessionRepoMongo) findOne(ctx context.Context, filter bson.M) (*domain.Session, error) {
+	var s domain.Session
+	err := r.col.FindOne(ctx, filter).Decode(&s)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("SessionRepoMongo.findOne: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SessionRepoMongo) Rotate(ctx context.Context, id, oldHash, newHash string, expiresAt, now time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("SessionRepoMongo.Rotate: %w", err)
+	}
+
+	// Условие на текущий хэш делает обмен атомарным: второй параллельный обмен не пройдёт
+	filter := bson.M{
+		"_id":         objID,
+		"refreshHash": oldHash,
+		"revokedAt":   bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"refreshHash": newHash,
+			"lastUsedAt":  now,
+			"expiresAt":   expiresAt,
+		},
+		"$push": bson.M{"usedHashes": oldHash},
+	}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("SessionRepoMongo.Rotate: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *SessionRepoMongo) ListActiveByUser(ctx context.Context, userID primitive.ObjectID, now time.Time) ([]domain.Session, error) {
+	filter := bson.M{
+		"userId":    userID,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "lastUsedAt", Value: -1}})
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("SessionRepoMongo.ListActiveByUser (find): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []domain.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("SessionRepoMongo.ListActiveByUser (decode): %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepoMongo) Revoke(ctx context.Context, id, reason string, now time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("SessionRepoMongo.Revoke: %w", err)
+	}
+
+	filter := bson.M{"_id": objID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": now, "revokeReason": reason}}
+	if _, err := r.col.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("SessionRepoMongo.Revoke: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionRepoMongo) RevokeAllByUser(ctx context.Context, userID primitive.ObjectID, reason string, now time.Time) (int64, error) {
+	filter := bson.M{"userId": userID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": now, "revokeReason": reason}}
+
+	res, err := r.col.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("SessionRepoMongo.RevokeAllByUser: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
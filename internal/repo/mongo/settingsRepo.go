@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+)
+
+// securityKey — _id документа с настройками безопасности
+const securityKey = "security"
+
+type SettingsRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewSettingsRepo(db *mongo.Database) *SettingsRepoMongo {
+	return &SettingsRepoMongo{
+		col: db.Collection("settings"),
+	}
+}
+
+// GetSecurity возвращает пустые настройки, если администратор их ещё не задавал
+func (r *SettingsRepoMongo) GetSecurity(ctx context.Context) (domain.SecuritySettings, error) {
+	var s domain.SecuritySettings
+	err := r.col.FindOne(ctx, bson.M{"_id": securityKey}).Decode(&s)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.SecuritySettings{}, fmt.Errorf("SettingsRepoMongo.GetSecurity: %w", err)
+	}
+	return s, nil
+}
+
+func (r *SettingsRepoMongo) SaveSecurity(ctx context.Context, s domain.SecuritySettings) error {
+	update := bson.M{"$set": bson.M{
+		"twoFactorRoles": s.TwoFactorRoles,
+		"updatedAt":      s.UpdatedAt,
+		"updatedBy":      s.UpdatedBy,
+	}}
+	_, err := r.col.UpdateByID(ctx, securityKey, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("SettingsRepoMongo.SaveSecurity: %w", err)
+	}
+	return nil
+}
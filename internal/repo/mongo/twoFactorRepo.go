@@ -0,0 +1,104 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type TwoFactorRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewTwoFactorRepo(db *mongo.Database) *TwoFactorRepoMongo {
+	return &TwoFactorRepoMongo{
+		col: db.Collection("two_factor"),
+	}
+}
+
+func (r *TwoFactorRepoMongo) Get(ctx context.Context, userID primitive.ObjectID) (*domain.TwoFactor, error) {
+	var tf domain.TwoFactor
+	err := r.col.FindOne(ctx, bson.M{"_id": userID}).Decode(&tf)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("TwoFactorRepoMongo.Get: %w", err)
+	}
+	return &tf, nil
+}
+
+// SavePending начинает (или перезапускает) привязку; подтверждённый аутентификатор не трогает
+func (r *TwoFactorRepoMongo) SavePending(ctx context.Context, tf *domain.TwoFactor) (bool, error) {
+	filter := bson.M{"_id": tf.UserID, "enabled": bson.M{"$ne": true}}
+	update := bson.M{"$set": bson.M{
+		"secret":        tf.Secret,
+		"enabled":       false,
+		"recoveryCodes": bson.A{},
+		"lastStep":      int64(0),
+		"createdAt":     tf.CreatedAt,
+	}}
+
+	_, err := r.col.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		// Upsert при уже включённой 2FA упирается в уникальный _id
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("TwoFactorRepoMongo.SavePending: %w", err)
+	}
+	return true, nil
+}
+
+func (r *TwoFactorRepoMongo) Enable(ctx context.Context, userID primitive.ObjectID, recoveryHashes []string, step int64, now time.Time) (bool, error) {
+	filter := bson.M{"_id": userID, "enabled": false}
+	update := bson.M{"$set": bson.M{
+		"enabled":       true,
+		"recoveryCodes": recoveryHashes,
+		"lastStep":      step,
+		"enabledAt":     now,
+	}}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("TwoFactorRepoMongo.Enable: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *TwoFactorRepoMongo) UseStep(ctx context.Context, userID primitive.ObjectID, step int64) (bool, error) {
+	// Код принимается только из интервала позже последнего использованного
+	filter := bson.M{"_id": userID, "lastStep": bson.M{"$lt": step}}
+	update := bson.M{"$set": bson.M{"lastStep": step}}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("TwoFactorRepoMongo.UseStep: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *TwoFactorRepoMongo) UseRecoveryCode(ctx context.Context, userID primitive.ObjectID, hash string) (bool, error) {
+	filter := bson.M{"_id": userID, "enabled": true, "recoveryCodes": hash}
+	update := bson.M{"$pull": bson.M{"recoveryCodes": hash}}
+
+	res, err := r.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("TwoFactorRepoMongo.UseRecoveryCode: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *TwoFactorRepoMongo) Delete(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	res, err := r.col.DeleteOne(ctx, bson.M{"_id": userID})
+	if err != nil {
+		return false, fmt.Errorf("TwoFactorRepoMongo.Delete: %w", err)
+	}
+	return res.DeletedCount == 1, nil
+}
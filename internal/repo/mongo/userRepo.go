@@ -3,7 +3,11 @@ package mongo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/textsim"
+	"regexp"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -35,12 +39,10 @@ func (r *UserRepoMongo) GetByID(ctx context.Context, id string) (*domain.User, e
 	return &user, err
 }
 
-func (r *UserRepoMongo) Login(ctx context.Context, phone, password string) (*domain.User, error) {
+// GetByPhone ищет пользователя по телефону; пароль проверяется в usecase
+func (r *UserRepoMongo) GetByPhone(ctx context.Context, phone string) (*domain.User, error) {
 	var user domain.User
-	err := r.col.FindOne(ctx, bson.M{
-		"phone":    phone,
-		"password": password,
-	}).Decode(&user)
+	err := r.col.FindOne(ctx, bson.M{"phone": phone}).Decode(&user)
 
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, nil
@@ -52,30 +54,7 @@ func (r *UserRepoMongo) Login(ctx context.Context, phone, password string) (*dom
 }
 
 func (r *UserRepoMongo) Search(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
-	query := bson.M{}
-
-	if filter.FullNameContains != "" || filter.Phone != "" || filter.Role != "" {
-		var orConditions []bson.M
-
-		if filter.FullNameContains != "" {
-			orConditions = append(orConditions, bson.M{"fullName": bson.M{"$regex": filter.FullNameContains, "$options": "i"}})
-		}
-		if filter.Phone != "" {
-			orConditions = append(orConditions, bson.M{"phone": bson.M{"$regex": filter.Phone, "$options": "i"}})
-		}
-		if filter.Role != "" {
-			orConditions = append(orConditions, bson.M{"role": bson.M{"$regex": filter.Role, "$options": "i"}})
-		}
-
-		if len(orConditions) > 0 {
-			query["$or"] = orConditions
-		}
-
-		// отдельно оставляем фильтрацию по активности
-		if filter.OnlyActive != nil {
-			query["isActive"] = *filter.OnlyActive
-		}
-	}
+	query := userSearchQuery(filter)
 
 	cursor, err := r.col.Find(ctx, query)
 	if err != nil {
@@ -110,10 +89,18 @@ func (r *UserRepoMongo) Create(ctx context.Context, u *domain.User) error {
 		"role":         u.Role,
 		"registeredAt": u.RegisteredAt,
 		"isActive":     u.IsActive,
+		"nameKeys":     textsim.SearchKeys(u.FullName),
+	}
+	if u.PendingVerification {
+		doc["pendingVerification"] = true
 	}
 
 	res, err := r.col.InsertOne(ctx, doc)
 	if err != nil {
+		// Телефон уникален: проверка в usecase не спасает от двух одновременных регистраций
+		if mongo.IsDuplicateKeyError(err) {
+			return customErr.ErrPhoneTaken
+		}
 		return err
 	}
 	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
@@ -135,9 +122,36 @@ func (r *UserRepoMongo) Update(ctx context.Context, u *domain.User) error {
 			"password": u.Password,
 			"role":     u.Role,
 			"isActive": u.IsActive,
+			"nameKeys": textsim.SearchKeys(u.FullName),
 		},
 	}
+	if u.PendingVerification {
+		update["$set"].(bson.M)["pendingVerification"] = true
+	} else {
+		update["$unset"] = bson.M{"pendingVerification": ""}
+	}
 	_, err = r.col.UpdateByID(ctx, objID, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return customErr.ErrPhoneTaken
+	}
+	return err
+}
+
+func (r *UserRepoMongo) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.col.UpdateByID(ctx, objID, bson.M{"$set": bson.M{"password": passwordHash}})
+	return err
+}
+
+func (r *UserRepoMongo) MarkPhoneVerified(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.col.UpdateByID(ctx, objID, bson.M{"$unset": bson.M{"pendingVerification": ""}})
 	return err
 }
 
@@ -154,6 +168,59 @@ func (r *UserRepoMongo) Count(ctx context.Context) (int64, error) {
 	return r.col.CountDocuments(ctx, bson.M{})
 }
 
+// userSorts — поля сортировки списка пользователей (параметр sort)
+var userSorts = pageSorts{
+	"fullName":     "fullName",
+	"phone":        "phone",
+	"registeredAt": "registeredAt",
+}
+
+func (r *UserRepoMongo) SearchPage(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (domain.Page[domain.User], error) {
+	res, err := findPage[domain.User](ctx, r.col, userSearchQuery(filter), page, userSorts, "fullName", nil)
+	if err != nil {
+		return res, fmt.Errorf("UserRepoMongo.SearchPage: %w", err)
+	}
+	return res, nil
+}
+
+// NameKeyVocabulary — ключи ФИО, начинающиеся с first: словарь для исправления опечаток
+func (r *UserRepoMongo) NameKeyVocabulary(ctx context.Context, first string) ([]string, error) {
+	values, err := r.col.Distinct(ctx, "nameKeys", bson.M{"nameKeys": keyPrefix(first)})
+	if err != nil {
+		return nil, fmt.Errorf("UserRepoMongo.NameKeyVocabulary: %w", err)
+	}
+	return vocabulary(values, first), nil
+}
+
+// userSearchQuery — фильтр пользователей для Search и SearchPage
+func userSearchQuery(filter domain.UserFilter) bson.M {
+	query := bson.M{}
+
+	if filter.FullNameContains != "" || filter.Phone != "" || filter.Role != "" {
+		var orConditions []bson.M
+
+		if filter.FullNameContains != "" {
+			orConditions = append(orConditions, textCondition("fullName", "nameKeys", filter.FullNameContains))
+		}
+		if filter.Phone != "" {
+			orConditions = append(orConditions, bson.M{"phone": bson.M{"$regex": regexp.QuoteMeta(filter.Phone), "$options": "i"}})
+		}
+		if filter.Role != "" {
+			orConditions = append(orConditions, bson.M{"role": bson.M{"$regex": regexp.QuoteMeta(filter.Role), "$options": "i"}})
+		}
+
+		if len(orConditions) > 0 {
+			query["$or"] = orConditions
+		}
+
+		// отдельно оставляем фильтрацию по активности
+		if filter.OnlyActive != nil {
+			query["isActive"] = *filter.OnlyActive
+		}
+	}
+	return query
+}
+
 // Вспомогательная функция для попытки извлечь ObjectID
 func userIDFromUser(u domain.User) (primitive.ObjectID, bool) {
 	id, err := primitive.ObjectIDFromHex(u.ID)
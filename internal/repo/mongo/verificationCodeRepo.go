@@ -0,0 +1,150 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type VerificationCodeRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewVerificationCodeRepo(db *mongo.Database) *VerificationCodeRepoMongo {
+	return &VerificationCodeRepoMongo{
+		col: db.Collection("verification_codes"),
+	}
+}
+
+func (r *VerificationCodeRepoMongo) Create(ctx context.Context, c *domain.VerificationCode) error {
+	doc := bson.M{
+		"phone":     c.Phone,
+		"purpose":   c.Purpose,
+		"codeHash":  c.CodeHash,
+		"attempts":  0,
+		"createdAt": c.CreatedAt,
+		"expiresAt": c.ExpiresAt,
+		"expireAt":  c.ExpireAt,
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("VerificationCodeRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("VerificationCodeRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	c.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *VerificationCodeRepoMongo) GetActive(ctx context.Context, phone, purpose string, now time.Time) (*domain.VerificationCode, error) {
+	filter := bson.M{
+		"phone":      phone,
+		"purpose":    purpose,
+		"consumedAt": bson.M{"$exists": false},
+		"expiresAt":  bson.M{"$gt": now},
+	}
+	return r.findOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+}
+
+func (r *VerificationCodeRepoMongo) GetLatest(ctx context.Context, phone, purpose string) (*domain.VerificationCode, error) {
+	filter := bson.M{"phone": phone, "purpose": purpose}
+	return r.findOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+}
+
+func (r *VerificationCodeRepoMongo) GetByTicket(ctx context.Context, ticketHash string) (*domain.VerificationCode, error) {
+	return r.findOne(ctx, bson.M{"ticketHash": ticketHash})
+}
+
+func (r *VerificationCodeRepoMongo) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*domain.VerificationCode, error) {
+	var c domain.VerificationCode
+	err := r.col.FindOne(ctx, filter, opts...).Decode(&c)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("VerificationCodeRepoMongo.findOne: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *VerificationCodeRepoMongo) IncrementAttempts(ctx context.Context, id string, max int) (int, bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, false, fmt.Errorf("VerificationCodeRepoMongo.IncrementAttempts: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":        objID,
+		"attempts":   bson.M{"$lt": max},
+		"consumedAt": bson.M{"$exists": false},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var c domain.VerificationCode
+	err = r.col.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&c)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("VerificationCodeRepoMongo.IncrementAttempts: %w", err)
+	}
+	return c.Attempts, true, nil
+}
+
+func (r *VerificationCodeRepoMongo) SetTicket(ctx context.Context, id, ticketHash string, expiresAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("VerificationCodeRepoMongo.SetTicket: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{
+		"ticketHash":      ticketHash,
+		"ticketExpiresAt": expiresAt,
+	}}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("VerificationCodeRepoMongo.SetTicket: %w", err)
+	}
+	return nil
+}
+
+func (r *VerificationCodeRepoMongo) Consume(ctx context.Context, id string, now time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("VerificationCodeRepoMongo.Consume: %w", err)
+	}
+
+	update := bson.M{
+		"$set":   bson.M{"consumedAt": now},
+		"$unset": bson.M{"ticketHash": ""},
+	}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("VerificationCodeRepoMongo.Consume: %w", err)
+	}
+	return nil
+}
+
+func (r *VerificationCodeRepoMongo) InvalidateAll(ctx context.Context, phone, purpose string, now time.Time) error {
+	filter := bson.M{
+		"phone":      phone,
+		"purpose":    purpose,
+		"consumedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set":   bson.M{"consumedAt": now},
+		"$unset": bson.M{"ticketHash": ""},
+	}
+	if _, err := r.col.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("VerificationCodeRepoMongo.InvalidateAll: %w", err)
+	}
+	return nil
+}
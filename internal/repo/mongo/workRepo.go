@@ -0,0 +1,115 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"library-Mongo/internal/domain"
+)
+
+type WorkRepoMongo struct {
+	col *mongo.Collection
+}
+
+func NewWorkRepo(db *mongo.Database) *WorkRepoMongo {
+	return &WorkRepoMongo{
+		col: db.Collection("works"),
+	}
+}
+
+func (r *WorkRepoMongo) Create(ctx context.Context, w *domain.Work) error {
+	doc := bson.M{
+		"title":     w.Title,
+		"keys":      w.Keys,
+		"createdAt": w.CreatedAt,
+		"updatedAt": w.UpdatedAt,
+	}
+	if w.Author != "" {
+		doc["author"] = w.Author
+	}
+	if w.Year != 0 {
+		doc["year"] = w.Year
+	}
+
+	res, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("WorkRepoMongo.Create: %w", err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return fmt.Errorf("WorkRepoMongo.Create: inserted ID is not ObjectID")
+	}
+	w.ID = oid.Hex()
+
+	return nil
+}
+
+func (r *WorkRepoMongo) Update(ctx context.Context, w *domain.Work) error {
+	objID, err := primitive.ObjectIDFromHex(w.ID)
+	if err != nil {
+		return fmt.Errorf("WorkRepoMongo.Update: %w", err)
+	}
+	update := bson.M{"$set": bson.M{
+		"title":     w.Title,
+		"author":    w.Author,
+		"year":      w.Year,
+		"keys":      w.Keys,
+		"updatedAt": w.UpdatedAt,
+	}}
+	if _, err := r.col.UpdateByID(ctx, objID, update); err != nil {
+		return fmt.Errorf("WorkRepoMongo.Update: %w", err)
+	}
+	return nil
+}
+
+// GetByID — nil, если произведения нет
+func (r *WorkRepoMongo) GetByID(ctx context.Context, id string) (*domain.Work, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("WorkRepoMongo.GetByID: %w", err)
+	}
+	var w domain.Work
+	err = r.col.FindOne(ctx, bson.M{"_id": objID}).Decode(&w)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("WorkRepoMongo.GetByID: %w", err)
+	}
+	return &w, nil
+}
+
+// workSorts — поля сортировки списка произведений (параметр sort)
+var workSorts = pageSorts{
+	"title":  "title",
+	"author": "author",
+	"year":   "year",
+}
+
+// SearchPage — произведения, название которых содержит title (с транслитерацией, как у книг)
+func (r *WorkRepoMongo) SearchPage(ctx context.Context, title string, page domain.PageRequest) (domain.Page[domain.Work], error) {
+	query := bson.M{}
+	if title != "" {
+		query = textCondition("title", "keys", title)
+	}
+	res, err := findPage[domain.Work](ctx, r.col, query, page, workSorts, "title", nil)
+	if err != nil {
+		return res, fmt.Errorf("WorkRepoMongo.SearchPage: %w", err)
+	}
+	return res, nil
+}
+
+func (r *WorkRepoMongo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("WorkRepoMongo.Delete: %w", err)
+	}
+	if _, err := r.col.DeleteOne(ctx, bson.M{"_id": objID}); err != nil {
+		return fmt.Errorf("WorkRepoMongo.Delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Package sheet — чтение и запись таблиц CSV и XLSX построчно
+package sheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Форматы таблиц
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnreadable — содержимое не разбирается как таблица заявленного формата
+var ErrUnreadable = errors.New("unreadable spreadsheet")
+
+var (
+	utf8BOM  = []byte("\xEF\xBB\xBF")
+	zipMagic = []byte("PK\x03\x04")
+)
+
+// IsFormat — поддерживается ли формат
+func IsFormat(format string) bool {
+	return format == FormatCSV || format == FormatXLSX
+}
+
+// Read читает все строки первого листа; пустой format — XLSX по сигнатуре zip, иначе CSV.
+// Разделитель CSV — запятая или точка с запятой (так сохраняет Excel в русской локали).
+func Read(r io.Reader, format string) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if format == "" {
+		format = FormatCSV
+		if head, _ := br.Peek(len(zipMagic)); bytes.Equal(head, zipMagic) {
+			format = FormatXLSX
+		}
+	}
+	var rows [][]string
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(br)
+	case FormatXLSX:
+		rows, err = readXLSX(br)
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet format %q", format)
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			row[i] = unescapeCell(v)
+		}
+	}
+	return rows, err
+}
+
+// formulaPrefix — символы, с которых табличный редактор начинает формулу; апостроф
+// экранируется сам, чтобы unescapeCell не сняла его с собственного значения ячейки
+const formulaPrefix = "=+-@\t\r'"
+
+// escapeCell защищает от CSV-инъекции: значение, похожее на формулу, выгружается с апострофом
+// впереди и открывается как текст ("=HYPERLINK(...)" в названии книги не выполнится)
+func escapeCell(v string) string {
+	if v != "" && strings.ContainsRune(formulaPrefix, rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// unescapeCell снимает апостроф, добавленный escapeCell, — выгрузка загружается обратно без изменений
+func unescapeCell(v string) string {
+	if len(v) > 1 && v[0] == '\'' && strings.ContainsRune(formulaPrefix, rune(v[1])) {
+		return v[1:]
+	}
+	return v
+}
+
+func readCSV(br *bufio.Reader) ([][]string, error) {
+	if head, _ := br.Peek(len(utf8BOM)); bytes.Equal(head, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	cr := csv.NewReader(br)
+	cr.Comma = detectComma(br)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreadable, err)
+	}
+	return rows, nil
+}
+
+// detectComma — по строке заголовка: точка с запятой, если её там больше, чем запятых
+func detectComma(br *bufio.Reader) rune {
+	head, _ := br.Peek(4096)
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	if bytes.Count(head, []byte(";")) > bytes.Count(head, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreadable, err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreadable, err)
+	}
+	return rows, nil
+}
+
+// Writer пишет таблицу построчно; Close обязателен
+type Writer interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// NewWriter — запись в формате format. CSV уходит в w сразу (с BOM, чтобы Excel узнал UTF-8),
+// XLSX собирается потоково и выводится целиком при Close.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: w, cw: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter(f.GetSheetName(0))
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxWriter{w: w, f: f, sw: sw}, nil
+	}
+	return nil, fmt.Errorf("unsupported spreadsheet format %q", format)
+}
+
+type csvWriter struct {
+	w       io.Writer
+	cw      *csv.Writer
+	started bool
+}
+
+func (c *csvWriter) WriteRow(cells []string) error {
+	if !c.started {
+		c.started = true
+		if _, err := c.w.Write(utf8BOM); err != nil {
+			return err
+		}
+	}
+	escaped := make([]string, len(cells))
+	for i, v := range cells {
+		escaped[i] = escapeCell(v)
+	}
+	return c.cw.Write(escaped)
+}
+
+func (c *csvWriter) Close() error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+type xlsxWriter struct {
+	w   io.Writer
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	row int
+}
+
+func (x *xlsxWriter) WriteRow(cells []string) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(cells))
+	for i, v := range cells {
+		values[i] = escapeCell(v)
+	}
+	return x.sw.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.f.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.f.Write(x.w)
+}
+
+// Cell — значение ячейки без пробелов по краям; строки XLSX бывают короче заголовка
+func Cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
@@ -0,0 +1,102 @@
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var formulaRows = [][]string{
+	{"title", "author", "year"},
+	{"=HYPERLINK(\"http://evil\",\"click\")", "+7 (900) 000-00-00", "-1"},
+	{"@SUM(A1:A2)", "\tTab", "\rCR"},
+	{"'quoted", "'=already", "''"},
+	{"'", "O'Brien", "Мастер и Маргарита"},
+	{"", "-", "1984"},
+}
+
+// Значения, похожие на формулу, выгружаются текстом, а загрузка возвращает их без изменений
+func TestWriteReadRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range formulaRows {
+				if err := w.WriteRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := Read(&buf, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != len(formulaRows) {
+				t.Fatalf("read %d rows, want %d", len(rows), len(formulaRows))
+			}
+			for i := range formulaRows {
+				// XLSX не хранит пустые ячейки в конце строки
+				got := rows[i]
+				for len(got) < len(formulaRows[i]) {
+					got = append(got, "")
+				}
+				if !slices.Equal(got, formulaRows[i]) {
+					t.Errorf("row %d = %q, want %q", i, got, formulaRows[i])
+				}
+			}
+		})
+	}
+}
+
+// В самом файле такие значения начинаются с апострофа
+func TestCSVExportEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, FormatCSV)
+	for _, row := range formulaRows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(buf.Bytes(), utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"title", "author", "year"},
+		{`'=HYPERLINK("http://evil","click")`, "'+7 (900) 000-00-00", "'-1"},
+		{"'@SUM(A1:A2)", "'\tTab", "'\rCR"},
+		{"''quoted", "''=already", "'''"},
+		{"''", "O'Brien", "Мастер и Маргарита"},
+		{"", "'-", "1984"},
+	}
+	for i := range want {
+		if !slices.Equal(raw[i], want[i]) {
+			t.Errorf("CSV row %d = %q, want %q", i, raw[i], want[i])
+		}
+	}
+}
+
+// Апостроф перед обычным текстом (например, из ручной правки файла) остаётся как есть
+func TestReadKeepsPlainApostrophe(t *testing.T) {
+	rows, err := Read(strings.NewReader("title\n'Hello\n'=x\n"), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"title"}, {"'Hello"}, {"=x"}}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
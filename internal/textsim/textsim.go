@@ -0,0 +1,164 @@
+// Package textsim — нормализация строк каталога и их нечёткое сравнение
+package textsim
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold — нижний регистр без диакритики: "Ёжик" -> "ежик", "Brontë" -> "bronte".
+// Вместе с ё сворачивается и й ("Толстои" совпадает с "Толстой")
+func Fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Words — слова Fold(s): буквы и цифры, пунктуация разделяет слова
+func Words(s string) []string {
+	return strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Key — ключ сравнения: Fold, без пунктуации, слова через один пробел.
+// "Мастер  и Маргарита." и "мастер и маргарита" дают один ключ
+func Key(s string) string {
+	return strings.Join(Words(s), " ")
+}
+
+// SortedKey — Key со словами по алфавиту: порядок слов не важен ("Булгаков Михаил" и "Михаил Булгаков")
+func SortedKey(s string) string {
+	words := Words(s)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// cyrillic — транслитерация кириллицы после Fold (й и ё к этому моменту уже и и е)
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+	'щ': "sch", 'ъ': "", 'ы': "i", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+	'і': "i", 'ї': "i", 'є': "e", 'ґ': "g", 'ў': "u",
+}
+
+// latinLoose сводит разные системы латинской транслитерации к одной:
+// Tolstoy/Tolstoj/Tolstoi, Chekhov/Chehov, Dostoyevsky/Dostoevskii
+var latinLoose = strings.NewReplacer(
+	"shch", "sch",
+	"kh", "h",
+	"ph", "f",
+	"ck", "k",
+	"tz", "ts",
+	"y", "i",
+	"j", "i",
+	"w", "v",
+	"x", "ks",
+	"q", "k",
+)
+
+// Latin — ключ слова для поиска без оглядки на алфавит: кириллица транслитерируется, латиница
+// приводится к той же свободной схеме, повторы букв схлопываются. "Толстой", "Tolstoy" и "tolstoj"
+// дают "tolstoi". Latin(Latin(w)) == Latin(w), так что ключ можно искать как обычное слово
+func Latin(word string) string {
+	var b strings.Builder
+	for _, r := range Fold(word) {
+		if lat, ok := cyrillic[r]; ok {
+			b.WriteString(lat)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	s := b.String()
+	for {
+		next := strings.ReplaceAll(latinLoose.Replace(s), "ie", "e")
+		next = squeeze(next)
+		if next == s {
+			return s
+		}
+		s = next
+	}
+}
+
+// squeeze схлопывает подряд идущие одинаковые буквы: "anna" -> "ana", "evgenii" -> "evgeni"
+func squeeze(s string) string {
+	var b strings.Builder
+	var prev rune = -1
+	for _, r := range s {
+		if r != prev {
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// SearchKeys — Latin всех слов строк без повторов: ключи, которые хранятся в документе для поиска
+func SearchKeys(texts ...string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, t := range texts {
+		for _, w := range Words(t) {
+			if k := Latin(w); k != "" && !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// Distance — расстояние Левенштейна между строками в символах (не байтах)
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// MaxTypos — сколько опечаток допускается в слове: короткие слова почти без права на ошибку
+func MaxTypos(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Similarity — 1 для одинаковых строк, 0 для совсем разных: 1 - Distance / длина большей строки
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	n := max(len([]rune(a)), len([]rune(b)))
+	return 1 - float64(Distance(a, b))/float64(n)
+}
@@ -0,0 +1,179 @@
+package textsim
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Ёжик", "ежик"},
+		{"Толстой", "толстои"},
+		{"Brontë", "bronte"},
+		{"Café Crème", "cafe creme"},
+		{"ÄÖÜ", "aou"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		in, want, sorted string
+	}{
+		{"Мастер  и Маргарита.", "мастер и маргарита", "и маргарита мастер"},
+		{"мастер и маргарита", "мастер и маргарита", "и маргарита мастер"},
+		{"Булгаков, Михаил", "булгаков михаил", "булгаков михаил"},
+		{"Михаил Булгаков", "михаил булгаков", "булгаков михаил"},
+		{"«Ёжик» в тумане!", "ежик в тумане", "в ежик тумане"},
+		{"451° по Фаренгейту", "451 по фаренгеиту", "451 по фаренгеиту"},
+		{" ... ", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.in); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := SortedKey(tt.in); got != tt.sorted {
+			t.Errorf("SortedKey(%q) = %q, want %q", tt.in, got, tt.sorted)
+		}
+	}
+}
+
+// Написания одного слова в разных алфавитах и системах транслитерации дают один ключ
+func TestLatin(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"Толстой", "Толстои", "tolstoy", "Tolstoj", "TOLSTOI"}, "tolstoi"},
+		{[]string{"Ёжик", "ежик", "ezhik"}, "ezhik"},
+		{[]string{"Чехов", "Chekhov", "Chehov"}, "chehov"},
+		{[]string{"Достоевский", "Dostoyevsky", "Dostoevskii", "Dostoevskij"}, "dostoevski"},
+		{[]string{"Щедрин", "Shchedrin", "Schedrin"}, "schedrin"},
+		{[]string{"Анна", "Anna"}, "ana"},
+		{[]string{"Цветаева", "Tsvetaeva", "Tzvetaeva"}, "tsvetaeva"},
+		{[]string{"Юрий", "Yurii", "Iurii"}, "iuri"},
+		{[]string{"Дюма", "Diuma"}, "diuma"},
+	}
+	for _, tt := range tests {
+		for _, w := range tt.words {
+			got := Latin(w)
+			if got != tt.want {
+				t.Errorf("Latin(%q) = %q, want %q", w, got, tt.want)
+			}
+			if again := Latin(got); again != got {
+				t.Errorf("Latin(Latin(%q)) = %q, want %q", w, again, got)
+			}
+		}
+	}
+}
+
+// Примеры из запроса на поиск: ключ слова запроса должен быть среди ключей документа
+func TestSearchKeysFindsTransliteratedQueries(t *testing.T) {
+	tests := []struct {
+		doc   string
+		query string
+	}{
+		{"Толстой", "tolstoy"},
+		{"Толстой", "Толстои"},
+		{"Лев Толстой", "TOLSTOJ"},
+		{"Ёжик в тумане", "ежик"},
+		{"Ёжик в тумане", "Ёжик"},
+		{"Ёжик в тумане", "tumane"},
+		{"Chekhov", "Чехов"},
+	}
+	for _, tt := range tests {
+		keys := SearchKeys(tt.doc)
+		for _, w := range Words(tt.query) {
+			if !slices.Contains(keys, Latin(w)) {
+				t.Errorf("query %q does not find %q: key %q not in %v", tt.query, tt.doc, Latin(w), keys)
+			}
+		}
+	}
+}
+
+func TestSearchKeys(t *testing.T) {
+	got := SearchKeys("Лев Толстой", "Tolstoy, Leo", "", "Война и мир")
+	want := []string{"lev", "tolstoi", "leo", "voina", "i", "mir"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SearchKeys = %v, want %v", got, want)
+	}
+	if got := SearchKeys(); got != nil {
+		t.Errorf("SearchKeys() = %v, want nil", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abc", "abc", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		// Перестановка соседних букв — две правки (обычный Левенштейн, не Дамерау)
+		{"ab", "ba", 2},
+		{"tolstoi", "tolsoti", 2},
+		// Многобайтовые руны считаются одним символом
+		{"ежик", "ёжик", 1},
+		{"толстой", "толстои", 1},
+		{"мир", "", 3},
+		{"日本語", "日本", 1},
+		{"a😀b", "ab", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestMaxTypos(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"", 0},
+		{"мир", 0},
+		{"ежик", 1},
+		{"тумане", 1},
+		{"толстой", 2},
+	}
+	for _, tt := range tests {
+		if got := MaxTypos(tt.word); got != tt.want {
+			t.Errorf("MaxTypos(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "", 0},
+		{"abc", "xyz", 0},
+		{"ежик", "ёжик", 0.75},
+		{"ab", "ba", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
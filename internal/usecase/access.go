@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"library-Mongo/internal/auth"
+	customErr "library-Mongo/internal/errors"
+)
+
+// checkOwner пропускает сотрудников и читателя, обращающегося к своим данным
+func checkOwner(ctx context.Context, ownerID string) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !auth.CanAccessOwn(p, ownerID) {
+		return customErr.ErrNotOwner
+	}
+	return nil
+}
+
+// callerRole — роль вызывающего, пустая строка для анонимного запроса
+func callerRole(ctx context.Context) string {
+	p, _ := auth.PrincipalFromContext(ctx)
+	return p.Role
+}
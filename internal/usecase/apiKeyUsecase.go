@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"library-Mongo/internal/auth"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// apiKeyTouchInterval — точность отметки последнего использования ключа
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyUsecase struct {
+	apiKeyRepo repo.APIKeyRepository
+}
+
+func NewAPIKeyUsecase(apiKeyRepo repo.APIKeyRepository) *APIKeyUsecase {
+	return &APIKeyUsecase{apiKeyRepo: apiKeyRepo}
+}
+
+// CreateAPIKey (admin) выпускает ключ; полный ключ возвращается один раз
+func (uc *APIKeyUsecase) CreateAPIKey(ctx context.Context, input dto.CreateAPIKeyInput) (dto.APIKeyCreatedResponse, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(input.Scopes) == 0 {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("CreateAPIKey: name and scopes required")
+	}
+	scopes := make([]string, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		if !auth.IsValidScope(scope) {
+			return dto.APIKeyCreatedResponse{}, customErr.ErrInvalidScope
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if _, err := auth.ParseAllowList(input.AllowedIPs); err != nil {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("CreateAPIKey: invalid allowed IP: %w", err)
+	}
+	now := time.Now()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("CreateAPIKey: expiresAt must be in the future")
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("CreateAPIKey: %w", err)
+	}
+
+	p, _ := auth.PrincipalFromContext(ctx)
+	apiKey := domain.APIKey{
+		Name:       name,
+		Prefix:     prefix,
+		Hash:       hash,
+		Scopes:     scopes,
+		AllowedIPs: input.AllowedIPs,
+		ExpiresAt:  input.ExpiresAt,
+		CreatedAt:  now,
+		CreatedBy:  p.UserID,
+	}
+	if err := uc.apiKeyRepo.Create(ctx, &apiKey); err != nil {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("CreateAPIKey: %w", err)
+	}
+
+	return dto.APIKeyCreatedResponse{APIKeyResponse: dto.NewAPIKeyResponse(apiKey, now), Key: key}, nil
+}
+
+func (uc *APIKeyUsecase) ListAPIKeys(ctx context.Context, includeRevoked bool) ([]dto.APIKeyResponse, error) {
+	keys, err := uc.apiKeyRepo.List(ctx, includeRevoked)
+	if err != nil {
+		return nil, fmt.Errorf("ListAPIKeys: %w", err)
+	}
+	return dto.NewAPIKeyResponses(keys, time.Now()), nil
+}
+
+func (uc *APIKeyUsecase) GetAPIKey(ctx context.Context, id string) (dto.APIKeyResponse, error) {
+	k, err := uc.getByID(ctx, id)
+	if err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+	return dto.NewAPIKeyResponse(*k, time.Now()), nil
+}
+
+// RotateAPIKey заменяет секрет, сохраняя области и ограничения; старый ключ сразу перестаёт действовать
+func (uc *APIKeyUsecase) RotateAPIKey(ctx context.Context, id string) (dto.APIKeyCreatedResponse, error) {
+	k, err := uc.getByID(ctx, id)
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("RotateAPIKey: %w", err)
+	}
+	now := time.Now()
+	ok, err := uc.apiKeyRepo.Rotate(ctx, id, prefix, hash, now)
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, fmt.Errorf("RotateAPIKey: %w", err)
+	}
+	if !ok {
+		return dto.APIKeyCreatedResponse{}, customErr.ErrAPIKeyNotFound
+	}
+
+	k.Prefix, k.Hash, k.RotatedAt = prefix, hash, &now
+	return dto.APIKeyCreatedResponse{APIKeyResponse: dto.NewAPIKeyResponse(*k, now), Key: key}, nil
+}
+
+func (uc *APIKeyUsecase) RevokeAPIKey(ctx context.Context, id string) error {
+	if _, err := uc.getByID(ctx, id); err != nil {
+		return err
+	}
+	ok, err := uc.apiKeyRepo.Revoke(ctx, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("RevokeAPIKey: %w", err)
+	}
+	if !ok {
+		return customErr.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey проверяет ключ из запроса и возвращает principal с его областями
+func (uc *APIKeyUsecase) AuthenticateAPIKey(ctx context.Context, key, ip string) (auth.Principal, error) {
+	if !auth.LooksLikeAPIKey(key) {
+		return auth.Principal{}, customErr.ErrInvalidAPIKey
+	}
+
+	k, err := uc.apiKeyRepo.GetByHash(ctx, auth.HashOpaqueToken(key))
+	if err != nil {
+		return auth.Principal{}, fmt.Errorf("AuthenticateAPIKey: %w", err)
+	}
+	now := time.Now()
+	if k == nil || !k.Active(now) {
+		return auth.Principal{}, customErr.ErrInvalidAPIKey
+	}
+	if !auth.IPAllowed(k.AllowedIPs, ip) {
+		return auth.Principal{}, customErr.ErrAPIKeyIPDenied
+	}
+
+	if err := uc.apiKeyRepo.Touch(ctx, k.ID, ip, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		log.Printf("AuthenticateAPIKey: %v", err)
+	}
+
+	return auth.Principal{APIKeyID: k.ID, Scopes: k.Scopes}, nil
+}
+
+func (uc *APIKeyUsecase) getByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	if id == "" {
+		return nil, customErr.ErrInvalidID
+	}
+	k, err := uc.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	if k == nil {
+		return nil, customErr.ErrAPIKeyNotFound
+	}
+	return k, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"library-Mongo/internal/audit"
+	"library-Mongo/internal/auth"
+	"library-Mongo/internal/domain"
+	"library-Mongo/internal/repo"
+	"log"
+	"time"
+)
+
+// defaultAuditLimit — размер выборки журнала, если limit не задан
+const defaultAuditLimit = 100
+
+type AuditUsecase struct {
+	auditRepo repo.AuditRepository
+	retention time.Duration
+}
+
+func NewAuditUsecase(auditRepo repo.AuditRepository, retention time.Duration) *AuditUsecase {
+	return &AuditUsecase{auditRepo: auditRepo, retention: retention}
+}
+
+// Record пишет запись об изменении: кто (из principal), откуда (из request-id middleware)
+// и что изменилось. Изменение уже сохранено, поэтому ошибка записи только логируется.
+func (uc *AuditUsecase) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		log.Printf("Audit %s %s: %v", action, entityID, err)
+	}
+
+	p, _ := auth.PrincipalFromContext(ctx)
+	meta := audit.RequestFromContext(ctx)
+	now := time.Now()
+	entry := domain.AuditEntry{
+		Actor: domain.AuditActor{
+			UserID:   p.UserID,
+			Role:     p.Role,
+			APIKeyID: p.APIKeyID,
+		},
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		At:         now,
+		IP:         meta.IP,
+		UserAgent:  meta.UserAgent,
+		RequestID:  meta.RequestID,
+		ExpireAt:   now.Add(uc.retention),
+	}
+	if err := uc.auditRepo.Create(ctx, &entry); err != nil {
+		log.Printf("Audit %s %s: %v", action, entityID, err)
+	}
+}
+
+func (uc *AuditUsecase) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	entries, err := uc.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ListAuditEntries: %w", err)
+	}
+	return entries, nil
+}
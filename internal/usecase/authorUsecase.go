@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"strings"
+	"time"
+)
+
+type AuthorUsecase struct {
+	authorRepo repo.AuthorRepository
+	bookRepo   repo.BookRepository
+	audit      AuditRecorder
+}
+
+func NewAuthorUsecase(authorRepo repo.AuthorRepository, bookRepo repo.BookRepository, audit AuditRecorder) *AuthorUsecase {
+	return &AuthorUsecase{authorRepo: authorRepo, bookRepo: bookRepo, audit: audit}
+}
+
+func (uc *AuthorUsecase) CreateAuthor(ctx context.Context, input dto.CreateAuthorInput) (dto.AuthorResponse, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return dto.AuthorResponse{}, fmt.Errorf("CreateAuthor: name required")
+	}
+
+	now := time.Now()
+	author := domain.Author{
+		Name:      name,
+		Variants:  cleanVariants(name, input.Variants),
+		BirthYear: input.BirthYear,
+		DeathYear: input.DeathYear,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	author.Keys = domain.AuthorKeys(author)
+
+	if err := uc.authorRepo.Create(ctx, &author); err != nil {
+		return dto.AuthorResponse{}, fmt.Errorf("CreateAuthor: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditAuthorCreate, domain.AuditEntityAuthor, author.ID, nil, author)
+
+	return dto.NewAuthorResponse(author), nil
+}
+
+// UpdateAuthor меняет запись; новое каноническое имя переписывается во всех книгах автора
+func (uc *AuthorUsecase) UpdateAuthor(ctx context.Context, input dto.UpdateAuthorInput) error {
+	author, err := uc.getActive(ctx, input.ID)
+	if err != nil {
+		return err
+	}
+	before := *author
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return fmt.Errorf("UpdateAuthor: name required")
+		}
+		author.Name = name
+	}
+	if input.Variants != nil {
+		author.Variants = *input.Variants
+	} else if author.Name != before.Name {
+		// Прежнее каноническое имя остаётся известным написанием
+		author.Variants = append(author.Variants, before.Name)
+	}
+	author.Variants = cleanVariants(author.Name, author.Variants)
+	if input.BirthYear != nil {
+		author.BirthYear = *input.BirthYear
+	}
+	if input.DeathYear != nil {
+		author.DeathYear = *input.DeathYear
+	}
+	author.Keys = domain.AuthorKeys(*author)
+	author.UpdatedAt = time.Now()
+
+	if err := uc.authorRepo.Update(ctx, author); err != nil {
+		return fmt.Errorf("UpdateAuthor: %w", err)
+	}
+	if author.Name != before.Name {
+		objID, _ := primitive.ObjectIDFromHex(author.ID)
+		if _, err := uc.bookRepo.RelinkAuthor(ctx, objID, objID, author.Name); err != nil {
+			return fmt.Errorf("UpdateAuthor: rename in books: %w", err)
+		}
+	}
+	uc.audit.Record(ctx, domain.AuditAuthorUpdate, domain.AuditEntityAuthor, author.ID, before, *author)
+	return nil
+}
+
+// DeleteAuthor удаляет запись, на которую не ссылается ни одна книга
+func (uc *AuthorUsecase) DeleteAuthor(ctx context.Context, id string) error {
+	author, err := uc.getActive(ctx, id)
+	if err != nil {
+		return err
+	}
+	objID, _ := primitive.ObjectIDFromHex(author.ID)
+	n, err := uc.bookRepo.CountByAuthor(ctx, objID)
+	if err != nil {
+		return fmt.Errorf("DeleteAuthor: %w", err)
+	}
+	if n > 0 {
+		return customErr.ErrAuthorInUse
+	}
+	if err := uc.authorRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("DeleteAuthor: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditAuthorDelete, domain.AuditEntityAuthor, id, *author, nil)
+	return nil
+}
+
+// GetAuthor по ID влитой записи возвращает запись, в которую её влили
+func (uc *AuthorUsecase) GetAuthor(ctx context.Context, id string) (dto.AuthorResponse, error) {
+	author, err := loadAuthor(ctx, uc.authorRepo, id)
+	if err != nil {
+		return dto.AuthorResponse{}, err
+	}
+	return dto.NewAuthorResponse(*author), nil
+}
+
+func (uc *AuthorUsecase) SearchAuthors(ctx context.Context, name string, page domain.PageRequest) (domain.Page[dto.AuthorResponse], error) {
+	authors, err := uc.authorRepo.SearchPage(ctx, name, page)
+	if err != nil {
+		return domain.Page[dto.AuthorResponse]{}, fmt.Errorf("SearchAuthors: %w", err)
+	}
+	return domain.Page[dto.AuthorResponse]{
+		Items: dto.NewAuthorResponses(authors.Items),
+		Total: authors.Total,
+		Next:  authors.Next,
+	}, nil
+}
+
+// MergeAuthors вливает дубликат в запись targetID: написания дубликата становятся вариантами,
+// книги перепривязываются, а дубликат остаётся перенаправлением на target
+func (uc *AuthorUsecase) MergeAuthors(ctx context.Context, targetID, duplicateID string) (dto.MergeAuthorsResponse, error) {
+	if targetID == duplicateID {
+		return dto.MergeAuthorsResponse{}, customErr.ErrMergeSelf
+	}
+	target, err := uc.getActive(ctx, targetID)
+	if err != nil {
+		return dto.MergeAuthorsResponse{}, err
+	}
+	duplicate, err := uc.getActive(ctx, duplicateID)
+	if err != nil {
+		return dto.MergeAuthorsResponse{}, err
+	}
+	before := *target
+
+	target.Variants = cleanVariants(target.Name, append(append(target.Variants, duplicate.Name), duplicate.Variants...))
+	if target.BirthYear == 0 {
+		target.BirthYear = duplicate.BirthYear
+	}
+	if target.DeathYear == 0 {
+		target.DeathYear = duplicate.DeathYear
+	}
+	target.Keys = domain.AuthorKeys(*target)
+	now := time.Now()
+	target.UpdatedAt = now
+
+	if err := uc.authorRepo.Update(ctx, target); err != nil {
+		return dto.MergeAuthorsResponse{}, fmt.Errorf("MergeAuthors: %w", err)
+	}
+	targetObjID, _ := primitive.ObjectIDFromHex(target.ID)
+	duplicateObjID, _ := primitive.ObjectIDFromHex(duplicate.ID)
+	books, err := uc.bookRepo.RelinkAuthor(ctx, duplicateObjID, targetObjID, target.Name)
+	if err != nil {
+		return dto.MergeAuthorsResponse{}, fmt.Errorf("MergeAuthors: relink books: %w", err)
+	}
+	if err := uc.authorRepo.MarkMerged(ctx, duplicateObjID, targetObjID, now); err != nil {
+		return dto.MergeAuthorsResponse{}, fmt.Errorf("MergeAuthors: %w", err)
+	}
+
+	merged := *duplicate
+	merged.MergedInto = target.ID
+	uc.audit.Record(ctx, domain.AuditAuthorMerge, domain.AuditEntityAuthor, duplicate.ID, *duplicate, merged)
+	uc.audit.Record(ctx, domain.AuditAuthorUpdate, domain.AuditEntityAuthor, target.ID, before, *target)
+
+	return dto.MergeAuthorsResponse{Author: dto.NewAuthorResponse(*target), BooksUpdated: books}, nil
+}
+
+// getActive — запись для изменения; влитая запись считается отсутствующей
+func (uc *AuthorUsecase) getActive(ctx context.Context, id string) (*domain.Author, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	author, err := uc.authorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getAuthor: %w", err)
+	}
+	if author == nil || author.MergedInto != "" {
+		return nil, customErr.ErrAuthorNotFound
+	}
+	return author, nil
+}
+
+// loadAuthor загружает автора, переходя от влитой записи к той, в которую её влили
+// (цепочек нет: MarkMerged перенаправляет прежние дубликаты сразу на новую запись)
+func loadAuthor(ctx context.Context, authorRepo repo.AuthorRepository, id string) (*domain.Author, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	author, err := authorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loadAuthor: %w", err)
+	}
+	if author != nil && author.MergedInto != "" {
+		author, err = authorRepo.GetByID(ctx, author.MergedInto)
+		if err != nil {
+			return nil, fmt.Errorf("loadAuthor: %w", err)
+		}
+	}
+	if author == nil {
+		return nil, customErr.ErrAuthorNotFound
+	}
+	return author, nil
+}
+
+// cleanVariants убирает пустые написания, повторы и совпадающие с каноническим именем
+func cleanVariants(name string, variants []string) []string {
+	seen := map[string]bool{domain.NameKey(name): true}
+	var res []string
+	for _, v := range variants {
+		v = strings.TrimSpace(v)
+		key := domain.NameKey(v)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, v)
+	}
+	return res
+}
@@ -2,23 +2,101 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/audit"
+	"library-Mongo/internal/auth"
 	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/isbn"
 	"library-Mongo/internal/repo"
 	"library-Mongo/internal/usecase/dto"
+	"log"
+	"strings"
+	"time"
 )
 
 type BookUsecase struct {
-	bookRepo repo.BookRepository
+	bookRepo   repo.BookRepository
+	itemRepo   repo.ItemRepository
+	authorRepo repo.AuthorRepository
+	genreRepo  repo.GenreRepository
+	workRepo   repo.WorkRepository
+	seriesRepo repo.SeriesRepository
+	borrowRepo repo.BorrowRepository
+	revisions  repo.BookRevisionRepository
+	audit      AuditRecorder
 }
 
-func NewBookUsecase(bookRepo repo.BookRepository) *BookUsecase {
-	return &BookUsecase{bookRepo: bookRepo}
+func NewBookUsecase(
+	bookRepo repo.BookRepository,
+	itemRepo repo.ItemRepository,
+	authorRepo repo.AuthorRepository,
+	genreRepo repo.GenreRepository,
+	workRepo repo.WorkRepository,
+	seriesRepo repo.SeriesRepository,
+	borrowRepo repo.BorrowRepository,
+	revisions repo.BookRevisionRepository,
+	audit AuditRecorder,
+) *BookUsecase {
+	return &BookUsecase{
+		bookRepo:   bookRepo,
+		itemRepo:   itemRepo,
+		authorRepo: authorRepo,
+		genreRepo:  genreRepo,
+		workRepo:   workRepo,
+		seriesRepo: seriesRepo,
+		borrowRepo: borrowRepo,
+		revisions:  revisions,
+		audit:      audit,
+	}
+}
+
+func (uc *BookUsecase) CreateBook(ctx context.Context, input dto.CreateBookInput) (dto.BookResponse, error) {
+	book, err := uc.newBook(ctx, input)
+	if err != nil {
+		return dto.BookResponse{}, fmt.Errorf("CreateBook: %w", err)
+	}
+
+	if err := uc.bookRepo.Create(ctx, &book); err != nil {
+		return dto.BookResponse{}, fmt.Errorf("CreateBook: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditBookCreate, domain.AuditEntityBook, book.ID, nil, book)
+	uc.recordRevision(ctx, book, domain.RevisionCreate, 0)
+
+	bookObjID, _ := primitive.ObjectIDFromHex(book.ID)
+	now := time.Now()
+	for n := 1; n <= input.Copies; n++ {
+		item := domain.Item{
+			BookID:     bookObjID,
+			Barcode:    domain.DefaultBarcode(book.ID, n),
+			Status:     domain.ItemAvailable,
+			AcquiredAt: now,
+		}
+		if err := uc.itemRepo.Create(ctx, &item); err != nil {
+			return dto.BookResponse{}, fmt.Errorf("CreateBook: create item: %w", err)
+		}
+		uc.audit.Record(ctx, domain.AuditItemCreate, domain.AuditEntityItem, item.ID, nil, item)
+	}
+
+	return dto.NewBookResponse(book, domain.Availability{Total: input.Copies, Available: input.Copies}), nil
 }
 
-func (uc *BookUsecase) CreateBook(ctx context.Context, input dto.CreateBookInput) (domain.Book, error) {
-	if input.Title == "" || input.Author == "" || input.Genre == "" {
-		return domain.Book{}, fmt.Errorf("CreateBook: missing required fields")
+// newBook проверяет ввод по правилам CreateBook и собирает книгу, ничего не сохраняя
+func (uc *BookUsecase) newBook(ctx context.Context, input dto.CreateBookInput) (domain.Book, error) {
+	contributors, err := uc.resolveContributors(ctx, input.Contributors)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if authors := domain.PrimaryAuthors(contributors); authors != "" {
+		input.Author = authors
+	}
+	if input.Title == "" || input.Author == "" || (input.Genre == "" && input.GenreID == "") {
+		return domain.Book{}, fmt.Errorf("missing required fields")
+	}
+	if input.Copies < 0 {
+		return domain.Book{}, fmt.Errorf("copies must not be negative")
 	}
 
 	book := domain.Book{
@@ -26,25 +104,153 @@ func (uc *BookUsecase) CreateBook(ctx context.Context, input dto.CreateBookInput
 		Author: input.Author,
 		Year:   input.Year,
 		Genre:  input.Genre,
+
+		Contributors: contributors,
+	}
+	if err := setISBN(&book, input.ISBN); err != nil {
+		return domain.Book{}, err
+	}
+	if err := uc.classify(ctx, &book, input.GenreID); err != nil {
+		return domain.Book{}, err
 	}
+	if err := uc.setWork(ctx, &book, input.WorkID); err != nil {
+		return domain.Book{}, err
+	}
+	if err := uc.setSeries(ctx, &book, input.SeriesID, input.Volume); err != nil {
+		return domain.Book{}, err
+	}
+	return book, nil
+}
 
-	if err := uc.bookRepo.Create(ctx, &book); err != nil {
-		return domain.Book{}, fmt.Errorf("CreateBook: %w", err)
+// setWork делает книгу изданием произведения workID; пустая строка отвязывает её
+func (uc *BookUsecase) setWork(ctx context.Context, b *domain.Book, workID string) error {
+	b.WorkID = ""
+	if workID == "" {
+		return nil
+	}
+	work, err := loadWork(ctx, uc.workRepo, workID)
+	if err != nil {
+		return err
 	}
+	b.WorkID = work.ID
+	return nil
+}
 
-	return book, nil
+// setSeries ставит книгу томом volume серии seriesID; пустая строка убирает книгу из серии
+func (uc *BookUsecase) setSeries(ctx context.Context, b *domain.Book, seriesID string, volume int) error {
+	if volume < 0 {
+		return customErr.ErrInvalidVolume
+	}
+	b.Series = nil
+	if seriesID == "" {
+		return nil
+	}
+	series, err := loadSeries(ctx, uc.seriesRepo, seriesID)
+	if err != nil {
+		return err
+	}
+	b.Series = &domain.SeriesEntry{SeriesID: series.ID, Title: series.Title, Volume: volume}
+	return nil
+}
+
+// classify относит книгу к жанру рубрикатора genreID, а без него — к жанру, у которого Genre
+// совпадает с названием или синонимом. Книга получает русское название жанра; жанр, которого
+// нет в рубрикаторе, остаётся строкой без ссылки
+func (uc *BookUsecase) classify(ctx context.Context, b *domain.Book, genreID string) error {
+	var genre *domain.Genre
+	var err error
+	if genreID != "" {
+		genre, err = loadGenre(ctx, uc.genreRepo, genreID)
+	} else {
+		genre, err = uc.genreRepo.FindByLabel(ctx, b.Genre)
+	}
+	if err != nil {
+		return err
+	}
+	b.GenreID = ""
+	if genre != nil {
+		b.GenreID, b.Genre = genre.ID, genre.LabelRU
+	}
+	return nil
+}
+
+// ValidateBook — проверки CreateBook без сохранения (dry run импорта), включая занятость ISBN
+func (uc *BookUsecase) ValidateBook(ctx context.Context, input dto.CreateBookInput) error {
+	book, err := uc.newBook(ctx, input)
+	if err != nil {
+		return err
+	}
+	return uc.checkISBNFree(ctx, book)
 }
 
 func (uc *BookUsecase) UpdateBook(ctx context.Context, input dto.UpdateBookInput) error {
+	existing, before, err := uc.applyUpdate(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	// Сохранить изменения
+	if err := uc.bookRepo.Update(ctx, existing); err != nil {
+		return fmt.Errorf("UpdateBook: %w", err)
+	}
+
+	if input.RevertOf > 0 {
+		uc.audit.Record(ctx, domain.AuditBookRevert, domain.AuditEntityBook, existing.ID, before, *existing)
+		uc.recordRevision(ctx, *existing, domain.RevisionRevert, input.RevertOf)
+		return nil
+	}
+	uc.audit.Record(ctx, domain.AuditBookUpdate, domain.AuditEntityBook, existing.ID, before, *existing)
+	// PUT без фактических изменений не плодит одинаковых ревизий
+	if changes, err := audit.Diff(before.Record(), existing.Record()); err != nil || len(changes) > 0 {
+		uc.recordRevision(ctx, *existing, domain.RevisionUpdate, 0)
+	}
+
+	return nil
+}
+
+// recordRevision сохраняет ревизию с состоянием книги после изменения. Изменение уже сохранено,
+// поэтому ошибка только логируется: следующая ревизия всё равно содержит запись целиком
+func (uc *BookUsecase) recordRevision(ctx context.Context, b domain.Book, action string, revertOf int) {
+	bookID, _ := primitive.ObjectIDFromHex(b.ID)
+	p, _ := auth.PrincipalFromContext(ctx)
+	rev := domain.BookRevision{
+		BookID:   bookID,
+		Action:   action,
+		RevertOf: revertOf,
+		Record:   b.Record(),
+		Actor: domain.AuditActor{
+			UserID:   p.UserID,
+			Role:     p.Role,
+			APIKeyID: p.APIKeyID,
+		},
+		At: time.Now(),
+	}
+	if err := uc.revisions.Create(ctx, &rev); err != nil {
+		log.Printf("Revision %s %s: %v", action, b.ID, err)
+	}
+}
+
+// ValidateBookUpdate — проверки UpdateBook без сохранения, включая занятость ISBN другой книгой
+func (uc *BookUsecase) ValidateBookUpdate(ctx context.Context, input dto.UpdateBookInput) error {
+	book, _, err := uc.applyUpdate(ctx, input)
+	if err != nil {
+		return err
+	}
+	return uc.checkISBNFree(ctx, *book)
+}
+
+// applyUpdate загружает книгу и применяет к ней переданные поля; возвращает и прежнее состояние
+func (uc *BookUsecase) applyUpdate(ctx context.Context, input dto.UpdateBookInput) (*domain.Book, domain.Book, error) {
 	if input.ID == "" {
-		return fmt.Errorf("UpdateBook: missing ID")
+		return nil, domain.Book{}, fmt.Errorf("UpdateBook: missing ID")
 	}
 
 	// Получить текущую книгу из репо
-	existing, err := uc.bookRepo.GetByID(ctx, input.ID)
+	existing, err := loadBook(ctx, uc.bookRepo, input.ID)
 	if err != nil {
-		return fmt.Errorf("UpdateBook: failed to load existing book: %w", err)
+		return nil, domain.Book{}, fmt.Errorf("UpdateBook: failed to load existing book: %w", err)
 	}
+	before := *existing
 
 	// Обновить только те поля, которые переданы
 	if input.Title != nil {
@@ -59,46 +265,488 @@ func (uc *BookUsecase) UpdateBook(ctx context.Context, input dto.UpdateBookInput
 	if input.Genre != nil {
 		existing.Genre = *input.Genre
 	}
-
-	// Сохранить изменения
-	if err := uc.bookRepo.Update(ctx, existing); err != nil {
-		return fmt.Errorf("UpdateBook: %w", err)
+	// Ссылка на рубрикатор пересматривается, только если жанр передан
+	if input.GenreID != nil || input.Genre != nil {
+		genreID := ""
+		if input.GenreID != nil {
+			genreID = *input.GenreID
+		}
+		if err := uc.classify(ctx, existing, genreID); err != nil {
+			return nil, before, err
+		}
+	}
+	if input.ISBN != nil {
+		if err := setISBN(existing, *input.ISBN); err != nil {
+			return nil, before, err
+		}
+	}
+	if input.WorkID != nil {
+		if err := uc.setWork(ctx, existing, *input.WorkID); err != nil {
+			return nil, before, err
+		}
 	}
+	// Номер тома без серии меняет том в текущей серии книги
+	if input.SeriesID != nil || input.Volume != nil {
+		seriesID, volume := "", 0
+		if existing.Series != nil {
+			seriesID, volume = existing.Series.SeriesID, existing.Series.Volume
+		}
+		if input.SeriesID != nil {
+			seriesID = *input.SeriesID
+		}
+		if input.Volume != nil {
+			volume = *input.Volume
+		}
+		if err := uc.setSeries(ctx, existing, seriesID, volume); err != nil {
+			return nil, before, err
+		}
+	}
+	if input.Contributors != nil {
+		contributors, err := uc.resolveContributors(ctx, *input.Contributors)
+		if err != nil {
+			return nil, before, err
+		}
+		existing.Contributors = contributors
+		if authors := domain.PrimaryAuthors(contributors); authors != "" {
+			existing.Author = authors
+		}
+	}
+	return existing, before, nil
+}
 
+// checkISBNFree — ErrISBNTaken, если ISBN книги уже записан за другой книгой
+func (uc *BookUsecase) checkISBNFree(ctx context.Context, b domain.Book) error {
+	if b.ISBN13 == "" {
+		return nil
+	}
+	other, err := uc.bookRepo.GetByISBN(ctx, b.ISBN13)
+	if errors.Is(err, customErr.ErrBookNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if other.ID != b.ID {
+		return customErr.ErrISBNTaken
+	}
 	return nil
 }
 
-func (uc *BookUsecase) DeleteBook(ctx context.Context, id string) error {
-	if id == "" {
-		return fmt.Errorf("DeleteBook: missing ID")
+// DeleteBook списывает книгу: она пропадает из поиска, но документ, экземпляры и обложка остаются
+// для истории выдач и отчётов. Книгу на руках у читателя списать нельзя
+func (uc *BookUsecase) DeleteBook(ctx context.Context, id, reason string) error {
+	existing, err := loadBook(ctx, uc.bookRepo, id)
+	if err != nil {
+		return fmt.Errorf("DeleteBook: %w", err)
+	}
+	id = existing.ID
+	bookObjID, _ := primitive.ObjectIDFromHex(id)
+	if existing.Withdrawn != nil {
+		return customErr.ErrBookWithdrawn
 	}
 
-	if err := uc.bookRepo.Delete(ctx, id); err != nil {
+	loans, err := uc.activeLoans(ctx, bookObjID)
+	if err != nil {
 		return fmt.Errorf("DeleteBook: %w", err)
 	}
+	if loans > 0 {
+		return fmt.Errorf("%w: %d", customErr.ErrBookHasLoans, loans)
+	}
 
+	withdrawal := &domain.Withdrawal{Reason: strings.TrimSpace(reason), At: time.Now()}
+	ok, err := uc.bookRepo.SetWithdrawn(ctx, id, withdrawal)
+	if err != nil {
+		return fmt.Errorf("DeleteBook: %w", err)
+	}
+	if !ok {
+		return customErr.ErrBookWithdrawn
+	}
+
+	// Выдача, начатая до списания, могла занять экземпляр между проверкой и списанием.
+	// Выдача после занятия экземпляра сама перечитывает книгу, поэтому повторный подсчёт
+	// после списания ловит все оставшиеся случаи — тогда списание откатывается
+	loans, err = uc.activeLoans(ctx, bookObjID)
+	if err == nil && loans > 0 {
+		err = fmt.Errorf("%w: %d", customErr.ErrBookHasLoans, loans)
+	}
+	if err != nil {
+		if _, rbErr := uc.bookRepo.SetWithdrawn(ctx, id, nil); rbErr != nil {
+			log.Printf("DeleteBook: undo withdrawal of %s: %v", id, rbErr)
+		}
+		return fmt.Errorf("DeleteBook: %w", err)
+	}
+
+	withdrawn := *existing
+	withdrawn.Withdrawn = withdrawal
+	uc.audit.Record(ctx, domain.AuditBookWithdraw, domain.AuditEntityBook, id, *existing, withdrawn)
+	return nil
+}
+
+// activeLoans — книги на руках: невозвращённые выдачи, включая выдачи без экземпляра, или экземпляры
+// в on_loan, если их больше — такой экземпляр уже занят выдачей, которая ещё не записана
+func (uc *BookUsecase) activeLoans(ctx context.Context, bookID primitive.ObjectID) (int64, error) {
+	loans, err := uc.borrowRepo.CountActiveByBook(ctx, bookID)
+	if err != nil {
+		return 0, err
+	}
+	items, err := uc.itemRepo.ListByBook(ctx, bookID)
+	if err != nil {
+		return 0, err
+	}
+	var onLoan int64
+	for _, item := range items {
+		if item.Status == domain.ItemOnLoan {
+			onLoan++
+		}
+	}
+	return max(loans, onLoan), nil
+}
+
+// RestoreBook возвращает списанную книгу в каталог
+func (uc *BookUsecase) RestoreBook(ctx context.Context, id string) error {
+	existing, err := loadBook(ctx, uc.bookRepo, id)
+	if err != nil {
+		return fmt.Errorf("RestoreBook: %w", err)
+	}
+	id = existing.ID
+	ok, err := uc.bookRepo.SetWithdrawn(ctx, id, nil)
+	if err != nil {
+		return fmt.Errorf("RestoreBook: %w", err)
+	}
+	if !ok {
+		return customErr.ErrBookNotWithdrawn
+	}
+
+	restored := *existing
+	restored.Withdrawn = nil
+	uc.audit.Record(ctx, domain.AuditBookRestore, domain.AuditEntityBook, id, *existing, restored)
 	return nil
 }
 
-func (uc *BookUsecase) GetBookByID(ctx context.Context, id string) (domain.Book, error) {
+func (uc *BookUsecase) GetBookByID(ctx context.Context, id string) (dto.BookResponse, error) {
 	if id == "" {
-		return domain.Book{}, fmt.Errorf("GetBookByID: missing ID")
+		return dto.BookResponse{}, fmt.Errorf("GetBookByID: missing ID")
+	}
+
+	book, err := loadBook(ctx, uc.bookRepo, id)
+	if err != nil {
+		return dto.BookResponse{}, fmt.Errorf("GetBookByID: %w", err)
+	}
+
+	availability, err := uc.availability(ctx, []domain.Book{*book})
+	if err != nil {
+		return dto.BookResponse{}, fmt.Errorf("GetBookByID: %w", err)
+	}
+	return dto.NewBookResponse(*book, availability[book.ID]), nil
+}
+
+// RelatedBooks — другие издания произведения книги и соседние тома её серии
+func (uc *BookUsecase) RelatedBooks(ctx context.Context, id string) (dto.RelatedBooksResponse, error) {
+	book, err := loadBook(ctx, uc.bookRepo, id)
+	if err != nil {
+		return dto.RelatedBooksResponse{}, fmt.Errorf("RelatedBooks: %w", err)
+	}
+
+	res := dto.RelatedBooksResponse{Editions: []dto.BookResponse{}}
+	var editions []domain.Book
+	if book.WorkID != "" {
+		work, err := uc.workRepo.GetByID(ctx, book.WorkID)
+		if err != nil {
+			return dto.RelatedBooksResponse{}, fmt.Errorf("RelatedBooks: %w", err)
+		}
+		if work != nil {
+			w := dto.NewWorkResponse(*work)
+			res.Work = &w
+		}
+		all, err := uc.bookRepo.Search(ctx, domain.BookFilter{WorkID: book.WorkID})
+		if err != nil {
+			return dto.RelatedBooksResponse{}, fmt.Errorf("RelatedBooks: %w", err)
+		}
+		for _, b := range all {
+			if b.ID != book.ID {
+				editions = append(editions, b)
+			}
+		}
+	}
+
+	var previous, next *domain.Book
+	if book.Series != nil {
+		res.Series = book.Series
+		if book.Series.Volume > 0 {
+			volumes, err := uc.bookRepo.Search(ctx, domain.BookFilter{SeriesID: book.Series.SeriesID})
+			if err != nil {
+				return dto.RelatedBooksResponse{}, fmt.Errorf("RelatedBooks: %w", err)
+			}
+			previous, next = neighbourVolumes(volumes, book.Series.Volume)
+		}
+	}
+
+	shown := editions
+	for _, b := range []*domain.Book{previous, next} {
+		if b != nil {
+			shown = append(shown, *b)
+		}
+	}
+	availability, err := uc.availability(ctx, shown)
+	if err != nil {
+		return dto.RelatedBooksResponse{}, fmt.Errorf("RelatedBooks: %w", err)
+	}
+	res.Editions = dto.NewBookResponses(editions, availability)
+	if previous != nil {
+		r := dto.NewBookResponse(*previous, availability[previous.ID])
+		res.Previous = &r
+	}
+	if next != nil {
+		r := dto.NewBookResponse(*next, availability[next.ID])
+		res.Next = &r
+	}
+	return res, nil
+}
+
+// neighbourVolumes — ближайшие тома до и после volume. Из нескольких изданий одного тома
+// берётся первое по ID, то есть раньше всех заведённое в каталог
+func neighbourVolumes(books []domain.Book, volume int) (previous, next *domain.Book) {
+	for i := range books {
+		b := &books[i]
+		v := b.Series.Volume
+		switch {
+		case v == 0 || v == volume:
+			continue
+		case v < volume:
+			if previous == nil || v > previous.Series.Volume || v == previous.Series.Volume && b.ID < previous.ID {
+				previous = b
+			}
+		default:
+			if next == nil || v < next.Series.Volume || v == next.Series.Volume && b.ID < next.ID {
+				next = b
+			}
+		}
+	}
+	return previous, next
+}
+
+// loadBook загружает книгу, переходя от влитого дубликата к книге, в которую его влили
+// (цепочек нет: MarkMerged перенаправляет прежние дубликаты сразу на новую книгу)
+func loadBook(ctx context.Context, bookRepo repo.BookRepository, id string) (*domain.Book, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	book, err := bookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loadBook: %w", err)
+	}
+	if book.MergedInto != "" {
+		book, err = bookRepo.GetByID(ctx, book.MergedInto)
+		if err != nil {
+			return nil, fmt.Errorf("loadBook: %w", err)
+		}
+	}
+	return book, nil
+}
+
+// resolveContributors подставляет канонические имена авторов; ссылка на влитую запись
+// заменяется записью, в которую её влили
+func (uc *BookUsecase) resolveContributors(ctx context.Context, inputs []dto.ContributorInput) ([]domain.Contributor, error) {
+	contributors := make([]domain.Contributor, 0, len(inputs))
+	for _, in := range inputs {
+		role := in.Role
+		if role == "" {
+			role = domain.RoleAuthor
+		}
+		if !domain.IsValidContributorRole(role) {
+			return nil, customErr.ErrInvalidContributor
+		}
+		author, err := loadAuthor(ctx, uc.authorRepo, in.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		authorID, _ := primitive.ObjectIDFromHex(author.ID)
+		contributors = append(contributors, domain.Contributor{AuthorID: authorID, Name: author.Name, Role: role})
+	}
+	return contributors, nil
+}
+
+// setISBN проверяет контрольную цифру и сохраняет ISBN в обеих формах; пустая строка удаляет ISBN
+func setISBN(b *domain.Book, raw string) error {
+	if raw == "" {
+		b.ISBN13, b.ISBN10 = "", ""
+		return nil
 	}
+	isbn13, err := isbn.Normalize(raw)
+	if err != nil {
+		return err
+	}
+	b.ISBN13, b.ISBN10 = isbn13, isbn.To10(isbn13)
+	return nil
+}
 
-	book, err := uc.bookRepo.GetByID(ctx, id)
+func (uc *BookUsecase) GetBookByISBN(ctx context.Context, raw string) (dto.BookResponse, error) {
+	isbn13, err := isbn.Normalize(raw)
+	if err != nil {
+		return dto.BookResponse{}, err
+	}
+	book, err := uc.bookRepo.GetByISBN(ctx, isbn13)
 	if err != nil {
-		return domain.Book{}, fmt.Errorf("GetBookByID: %w", err)
+		return dto.BookResponse{}, fmt.Errorf("GetBookByISBN: %w", err)
+	}
+	// ISBN мог остаться за влитым дубликатом
+	if book.MergedInto != "" {
+		if book, err = loadBook(ctx, uc.bookRepo, book.MergedInto); err != nil {
+			return dto.BookResponse{}, fmt.Errorf("GetBookByISBN: %w", err)
+		}
 	}
 
-	return *book, nil
+	availability, err := uc.availability(ctx, []domain.Book{*book})
+	if err != nil {
+		return dto.BookResponse{}, fmt.Errorf("GetBookByISBN: %w", err)
+	}
+	return dto.NewBookResponse(*book, availability[book.ID]), nil
 }
 
-func (uc *BookUsecase) SearchBooks(ctx context.Context, filter domain.BookFilter) ([]domain.Book, error) {
-	books, err := uc.bookRepo.Search(ctx, filter)
+func (uc *BookUsecase) SearchBooks(ctx context.Context, filter domain.BookFilter, page domain.PageRequest, withFacets bool) (dto.BookSearchResult, error) {
+	filter.ISBN = isbnQuery(filter.ISBN)
+	books, filter, suggestion, err := uc.searchPage(ctx, filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("SearchBooks: %w", err)
+		return dto.BookSearchResult{}, fmt.Errorf("SearchBooks: %w", err)
+	}
+	availability, err := uc.availability(ctx, books.Items)
+	if err != nil {
+		return dto.BookSearchResult{}, fmt.Errorf("SearchBooks: %w", err)
+	}
+	res := dto.BookSearchResult{
+		Items:      dto.NewBookResponses(books.Items, availability),
+		Total:      books.Total,
+		Next:       books.Next,
+		DidYouMean: suggestion,
+	}
+
+	if withFacets {
+		facets, err := uc.bookRepo.Facets(ctx, filter)
+		if err != nil {
+			return dto.BookSearchResult{}, fmt.Errorf("SearchBooks: %w", err)
+		}
+		res.Facets = &facets
+	}
+	return res, nil
+}
+
+// searchPage — страница каталога. Если первая страница пуста, слова q ищутся по ключам
+// (транслитерация, ё/е), а затем слова q, title и author с опечатками заменяются ближайшими ключами
+// каталога. Возвращает фильтр, по которому нашлись книги, и подсказку, если запрос исправлен
+func (uc *BookUsecase) searchPage(ctx context.Context, filter domain.BookFilter, page domain.PageRequest) (domain.Page[domain.Book], domain.BookFilter, dto.SearchSuggestion, error) {
+	books, err := uc.bookRepo.SearchPage(ctx, filter, page)
+	if page.After != "" {
+		// Курсор мог выдать поиск по ключам: по $text тогда ничего нет или сортировка курсора другая
+		if filter.Query != "" && (errors.Is(err, customErr.ErrInvalidCursor) || err == nil && books.Total == 0) {
+			filter.QueryByKeys = true
+			books, err = uc.bookRepo.SearchPage(ctx, filter, keysPage(page))
+		}
+		return books, filter, nil, err
+	}
+	if err != nil || books.Total > 0 {
+		return books, filter, nil, err
+	}
+	if filter.Query != "" {
+		filter.QueryByKeys = true
+		page = keysPage(page)
+		books, err = uc.bookRepo.SearchPage(ctx, filter, page)
+		if err != nil || books.Total > 0 {
+			return books, filter, nil, err
+		}
+	}
+
+	fixed := filter
+	params := []struct {
+		name  string
+		text  *string
+		vocab vocabularyFunc
+	}{
+		{"q", &fixed.Query, uc.catalogVocabulary("title", "author")},
+		{"title", &fixed.Title, uc.catalogVocabulary("title")},
+		{"author", &fixed.Author, uc.catalogVocabulary("author")},
+	}
+	allFixes := map[string]map[string]string{}
+	for _, p := range params {
+		if *p.text == "" {
+			continue
+		}
+		fixes, err := correctKeys(ctx, *p.text, p.vocab)
+		if err != nil {
+			return books, filter, nil, err
+		}
+		if len(fixes) > 0 {
+			allFixes[p.name] = fixes
+			*p.text = rewriteWords(*p.text, fixes, sameKey)
+		}
+	}
+	if len(allFixes) == 0 {
+		return books, filter, nil, nil
+	}
+
+	fixedBooks, err := uc.bookRepo.SearchPage(ctx, fixed, page)
+	if err != nil || fixedBooks.Total == 0 {
+		return books, filter, nil, err
+	}
+	var sources []string
+	for _, b := range fixedBooks.Items {
+		sources = append(sources, b.Title, b.Author)
+	}
+	original := map[string]string{"q": filter.Query, "title": filter.Title, "author": filter.Author}
+	suggestion := dto.SearchSuggestion{}
+	for name, fixes := range allFixes {
+		suggestion[name] = rewriteWords(original[name], fixes, func(fix string) string {
+			return displayWord(fix, sources)
+		})
+	}
+	return fixedBooks, fixed, suggestion, nil
+}
+
+// keysPage — страница для поиска q по ключам: без $text нет релевантности, порядок по умолчанию
+func keysPage(page domain.PageRequest) domain.PageRequest {
+	if strings.TrimPrefix(page.Sort, "-") == "relevance" {
+		page.Sort = ""
+	}
+	return page
+}
+
+// catalogVocabulary — словарь ключей из полей книг fields
+func (uc *BookUsecase) catalogVocabulary(fields ...string) vocabularyFunc {
+	return func(ctx context.Context, first string) ([]string, error) {
+		var words []string
+		for _, field := range fields {
+			w, err := uc.bookRepo.KeyVocabulary(ctx, field, first)
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, w...)
+		}
+		return words, nil
+	}
+}
+
+// isbnQuery — значение фильтра ISBN: полный ISBN-10 ищется по его ISBN-13, фрагмент — как есть без дефисов
+func isbnQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if isbn13, err := isbn.Normalize(raw); err == nil {
+		return isbn13
+	}
+	return isbn.Clean(raw)
+}
+
+// availability — доступность экземпляров для списка книг одним запросом
+func (uc *BookUsecase) availability(ctx context.Context, books []domain.Book) (map[string]domain.Availability, error) {
+	ids := make([]primitive.ObjectID, 0, len(books))
+	for _, b := range books {
+		if oid, err := primitive.ObjectIDFromHex(b.ID); err == nil {
+			ids = append(ids, oid)
+		}
+	}
+	if len(ids) == 0 {
+		return map[string]domain.Availability{}, nil
 	}
-	return books, nil
+	return uc.itemRepo.Availability(ctx, ids)
 }
 
 func (uc *BookUsecase) CountBooks(ctx context.Context) (int64, error) {
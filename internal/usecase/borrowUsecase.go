@@ -2,35 +2,48 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/auth"
 	"library-Mongo/internal/domain"
 	customErr "library-Mongo/internal/errors"
 	"library-Mongo/internal/repo"
 	"library-Mongo/internal/usecase/dto"
-	"sort"
+	"log"
 	"time"
 )
 
 type BorrowUsecase struct {
 	borrowRepo repo.BorrowRepository
 	bookRepo   repo.BookRepository
+	itemRepo   repo.ItemRepository
 	userRepo   repo.UserRepository
+	audit      AuditRecorder
 }
 
 func NewBorrowUsecase(
 	borrowRepo repo.BorrowRepository,
 	bookRepo repo.BookRepository,
+	itemRepo repo.ItemRepository,
 	userRepo repo.UserRepository,
+	audit AuditRecorder,
 ) *BorrowUsecase {
 	return &BorrowUsecase{
 		borrowRepo: borrowRepo,
 		bookRepo:   bookRepo,
+		itemRepo:   itemRepo,
 		userRepo:   userRepo,
+		audit:      audit,
 	}
 }
 
-func (uc *BorrowUsecase) GetBorrowHistory(ctx context.Context, userID string) (dto.BorrowHistoryResponse, error) {
+// GetBorrowHistory — страница истории выдач читателя в порядке page.Sort (по умолчанию — по дате выдачи)
+func (uc *BorrowUsecase) GetBorrowHistory(ctx context.Context, userID string, page domain.PageRequest) (dto.BorrowHistoryResponse, error) {
+	if err := checkOwner(ctx, userID); err != nil {
+		return dto.BorrowHistoryResponse{}, err
+	}
+
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return dto.BorrowHistoryResponse{}, fmt.Errorf("GetBorrowHistory: get user: %w", err)
@@ -44,103 +57,220 @@ func (uc *BorrowUsecase) GetBorrowHistory(ctx context.Context, userID string) (d
 		return dto.BorrowHistoryResponse{}, customErr.ErrInvalidID
 	}
 
-	borrows, err := uc.borrowRepo.GetByClientID(ctx, objID)
+	borrows, err := uc.borrowRepo.GetByClientIDPage(ctx, objID, page)
 	if err != nil {
 		return dto.BorrowHistoryResponse{}, fmt.Errorf("GetBorrowHistory: get borrows: %w", err)
 	}
 
+	viewer, _ := auth.PrincipalFromContext(ctx)
 	now := time.Now()
-	var overdue, normal []dto.BorrowHistoryItem
+	history := make([]dto.BorrowHistoryItem, 0, len(borrows.Items))
 
-	for _, b := range borrows {
-		book, err := uc.bookRepo.GetByID(ctx, b.BookID.Hex())
-		if err != nil || book == nil {
-			continue // можно логировать
+	for _, b := range borrows.Items {
+		book, err := uc.loanBook(ctx, b.BookID)
+		if err != nil {
+			return dto.BorrowHistoryResponse{}, fmt.Errorf("GetBorrowHistory: get book: %w", err)
 		}
 
 		isOverdue := b.ReturnedAt == nil && b.BorrowedAt.Before(now.AddDate(0, 0, -21))
 		item := dto.BorrowHistoryItem{
 			BorrowID:   b.ID,
-			BookID:     book.ID,
+			BookID:     b.BookID.Hex(),
+			ItemID:     itemIDHex(b.ItemID),
 			Title:      book.Title,
 			Author:     book.Author,
 			BorrowedAt: b.BorrowedAt,
 			ReturnedAt: b.ReturnedAt,
 			Status:     "ok",
+			Withdrawn:  book.Withdrawn != nil,
 		}
 		if isOverdue {
 			item.Status = "overdue"
-			overdue = append(overdue, item)
-		} else {
-			normal = append(normal, item)
 		}
+		history = append(history, item)
 	}
 
-	sort.SliceStable(overdue, func(i, j int) bool {
-		return overdue[i].BorrowedAt.Before(overdue[j].BorrowedAt)
-	})
-	sort.SliceStable(normal, func(i, j int) bool {
-		return normal[i].BorrowedAt.Before(normal[j].BorrowedAt)
-	})
-
 	return dto.BorrowHistoryResponse{
 		UserID:   user.ID,
 		FullName: user.FullName,
-		Phone:    user.Phone,
-		History:  append(overdue, normal...),
+		Phone:    dto.PhoneFor(viewer, user.ID, user.Phone),
+		History:  history,
+		Total:    borrows.Total,
+		Next:     borrows.Next,
 	}, nil
 }
 
-func (uc *BorrowUsecase) BorrowBook(ctx context.Context, input dto.BorrowBookInput) (domain.Borrow, error) {
+func (uc *BorrowUsecase) BorrowBook(ctx context.Context, input dto.BorrowBookInput) (dto.BorrowResponse, error) {
 	// 1. Проверка валидности ID
 	userObjID, err := primitive.ObjectIDFromHex(input.UserID)
 	if err != nil {
-		return domain.Borrow{}, customErr.ErrInvalidID
-	}
-	bookObjID, err := primitive.ObjectIDFromHex(input.BookID)
-	if err != nil {
-		return domain.Borrow{}, customErr.ErrInvalidID
+		return dto.BorrowResponse{}, customErr.ErrInvalidID
 	}
 
 	// 2. Проверка, существует ли пользователь
 	user, err := uc.userRepo.GetByID(ctx, input.UserID)
 	if err != nil {
-		return domain.Borrow{}, fmt.Errorf("BorrowBook: get user: %w", err)
+		return dto.BorrowResponse{}, fmt.Errorf("BorrowBook: get user: %w", err)
 	}
 	if user == nil {
-		return domain.Borrow{}, customErr.ErrUserNotFound
+		return dto.BorrowResponse{}, customErr.ErrUserNotFound
 	}
 
-	// 3. Проверка, существует ли книга
-	book, err := uc.bookRepo.GetByID(ctx, input.BookID)
+	// 3. Выбор экземпляра и перевод его в on_loan (атомарно — второй выдачи того же экземпляра не будет)
+	item, err := uc.checkoutItem(ctx, input)
 	if err != nil {
-		return domain.Borrow{}, fmt.Errorf("BorrowBook: get book: %w", err)
+		return dto.BorrowResponse{}, err
+	}
+	itemObjID, _ := primitive.ObjectIDFromHex(item.ID)
+
+	// 4. Сохраняем новую выдачу
+	borrow := domain.Borrow{
+		ClientID:   userObjID,
+		BookID:     item.BookID,
+		ItemID:     itemObjID,
+		BorrowedAt: time.Now(),
 	}
-	if book == nil {
-		return domain.Borrow{}, customErr.ErrBookNotFound
+
+	if err := uc.borrowRepo.Create(ctx, &borrow); err != nil {
+		uc.releaseItem(ctx, itemObjID)
+		return dto.BorrowResponse{}, fmt.Errorf("BorrowBook: insert: %w", err)
 	}
+	uc.audit.Record(ctx, domain.AuditBorrowCreate, domain.AuditEntityBorrow, borrow.ID, nil, borrow)
+
+	resp := dto.NewBorrowResponse(borrow)
+	resp.ItemID = item.ID
+	resp.Barcode = item.Barcode
+	return resp, nil
+}
 
-	// 4. Проверка: книга уже выдана?
-	hasActive, err := uc.borrowRepo.HasActiveBorrow(ctx, bookObjID)
+// loanBook — книга выдачи для отчётов. Списанные книги остаются в каталоге; книга, удалённая
+// до появления списания, возвращается пустой, чтобы выдача не пропала из отчёта
+func (uc *BorrowUsecase) loanBook(ctx context.Context, id primitive.ObjectID) (domain.Book, error) {
+	book, err := uc.bookRepo.GetByID(ctx, id.Hex())
+	if errors.Is(err, customErr.ErrBookNotFound) {
+		return domain.Book{ID: id.Hex()}, nil
+	}
 	if err != nil {
-		return domain.Borrow{}, fmt.Errorf("BorrowBook: check active borrow: %w", err)
+		return domain.Book{}, err
 	}
-	if hasActive {
-		return domain.Borrow{}, customErr.ErrBookAlreadyBorrowed
+	return *book, nil
+}
+
+// itemIDHex — пустая строка для выдач, оформленных до учёта экземпляров
+func itemIDHex(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
 	}
+	return id.Hex()
+}
 
-	// 5. Сохраняем новую выдачу
-	borrow := domain.Borrow{
-		ClientID:   userObjID,
-		BookID:     bookObjID,
-		BorrowedAt: time.Now(),
+// checkoutItemAttempts — сколько раз пробовать взять свободный экземпляр,
+// если его параллельно выдали другому читателю
+const checkoutItemAttempts = 3
+
+// checkoutItem находит экземпляр по itemId/barcode или любой свободный экземпляр bookId
+// и переводит его в статус on_loan
+func (uc *BorrowUsecase) checkoutItem(ctx context.Context, input dto.BorrowBookInput) (*domain.Item, error) {
+	if input.ItemID != "" || input.Barcode != "" {
+		var item *domain.Item
+		var err error
+		if input.ItemID != "" {
+			if _, err := primitive.ObjectIDFromHex(input.ItemID); err != nil {
+				return nil, customErr.ErrInvalidID
+			}
+			item, err = uc.itemRepo.GetByID(ctx, input.ItemID)
+		} else {
+			item, err = uc.itemRepo.GetByBarcode(ctx, input.Barcode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("BorrowBook: get item: %w", err)
+		}
+		if item == nil {
+			return nil, customErr.ErrItemNotFound
+		}
+		if input.BookID != "" && item.BookID.Hex() != input.BookID {
+			return nil, customErr.ErrItemNotFound
+		}
+		book, err := uc.bookRepo.GetByID(ctx, item.BookID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("BorrowBook: get book: %w", err)
+		}
+		if book.Withdrawn != nil {
+			return nil, customErr.ErrBookWithdrawn
+		}
+
+		objID, _ := primitive.ObjectIDFromHex(item.ID)
+		ok, err := uc.itemRepo.SetStatus(ctx, objID, domain.ItemAvailable, domain.ItemOnLoan)
+		if err != nil {
+			return nil, fmt.Errorf("BorrowBook: checkout item: %w", err)
+		}
+		if !ok {
+			return nil, customErr.ErrItemUnavailable
+		}
+		if err := uc.recheckWithdrawn(ctx, objID, item.BookID); err != nil {
+			return nil, err
+		}
+		return item, nil
 	}
 
-	if err := uc.borrowRepo.Create(ctx, &borrow); err != nil {
-		return domain.Borrow{}, fmt.Errorf("BorrowBook: insert: %w", err)
+	book, err := loadBook(ctx, uc.bookRepo, input.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("BorrowBook: get book: %w", err)
+	}
+	bookObjID, _ := primitive.ObjectIDFromHex(book.ID)
+	if book.Withdrawn != nil {
+		return nil, customErr.ErrBookWithdrawn
 	}
 
-	return borrow, nil
+	for i := 0; i < checkoutItemAttempts; i++ {
+		item, err := uc.itemRepo.FindAvailable(ctx, bookObjID)
+		if err != nil {
+			return nil, fmt.Errorf("BorrowBook: find item: %w", err)
+		}
+		if item == nil {
+			return nil, customErr.ErrNoAvailableCopies
+		}
+		objID, _ := primitive.ObjectIDFromHex(item.ID)
+		ok, err := uc.itemRepo.SetStatus(ctx, objID, domain.ItemAvailable, domain.ItemOnLoan)
+		if err != nil {
+			return nil, fmt.Errorf("BorrowBook: checkout item: %w", err)
+		}
+		if ok {
+			if err := uc.recheckWithdrawn(ctx, objID, bookObjID); err != nil {
+				return nil, err
+			}
+			return item, nil
+		}
+	}
+	return nil, customErr.ErrNoAvailableCopies
+}
+
+// recheckWithdrawn перечитывает книгу после того, как экземпляр занят: если её успели списать,
+// экземпляр освобождается. Списание со своей стороны пересчитывает занятые экземпляры после записи,
+// так что параллельные выдача и списание не проходят обе
+func (uc *BorrowUsecase) recheckWithdrawn(ctx context.Context, itemID, bookID primitive.ObjectID) error {
+	book, err := uc.bookRepo.GetByID(ctx, bookID.Hex())
+	if err == nil && book.Withdrawn != nil {
+		err = customErr.ErrBookWithdrawn
+	}
+	if err != nil {
+		uc.releaseItem(ctx, itemID)
+		if errors.Is(err, customErr.ErrBookWithdrawn) {
+			return err
+		}
+		return fmt.Errorf("BorrowBook: get book: %w", err)
+	}
+	return nil
+}
+
+// releaseItem возвращает экземпляр в available; ошибка только логируется
+func (uc *BorrowUsecase) releaseItem(ctx context.Context, itemID primitive.ObjectID) {
+	if itemID.IsZero() {
+		return
+	}
+	if _, err := uc.itemRepo.SetStatus(ctx, itemID, domain.ItemOnLoan, domain.ItemAvailable); err != nil {
+		log.Printf("release item %s: %v", itemID.Hex(), err)
+	}
 }
 
 func (uc *BorrowUsecase) ReturnBook(ctx context.Context, input dto.ReturnBookInput) error {
@@ -171,32 +301,46 @@ func (uc *BorrowUsecase) ReturnBook(ctx context.Context, input dto.ReturnBookInp
 
 	// Помечаем как возвращённую
 	now := time.Now()
-	err = uc.borrowRepo.Close(ctx, input.BorrowID, now)
+	closed, err := uc.borrowRepo.Close(ctx, input.BorrowID, now)
 	if err != nil {
 		return fmt.Errorf("ReturnBook: close borrow: %w", err)
 	}
+	if !closed {
+		// Выдачу закрыл параллельный возврат; экземпляр мог уже уйти другому читателю
+		return customErr.ErrAlreadyReturned
+	}
+	uc.releaseItem(ctx, borrow.ItemID)
+
+	returned := *borrow
+	returned.ReturnedAt = &now
+	uc.audit.Record(ctx, domain.AuditBorrowReturn, domain.AuditEntityBorrow, input.BorrowID, *borrow, returned)
 
 	return nil
 }
 
-func (uc *BorrowUsecase) GetOverdueBorrows(ctx context.Context) ([]dto.OverdueReportItem, error) {
+func (uc *BorrowUsecase) GetOverdueBorrows(ctx context.Context, page domain.PageRequest) (domain.Page[dto.OverdueReportItem], error) {
 	now := time.Now()
 
-	// Получаем список всех просроченных выдач
-	borrows, err := uc.borrowRepo.GetOverdue(ctx, now)
+	// Получаем страницу просроченных выдач
+	borrows, err := uc.borrowRepo.GetOverduePage(ctx, now, page)
 	if err != nil {
-		return nil, fmt.Errorf("GetOverdueBorrows: %w", err)
+		return domain.Page[dto.OverdueReportItem]{}, fmt.Errorf("GetOverdueBorrows: %w", err)
 	}
 
-	// Счётчик просрочек по пользователям
-	overdueCount := make(map[string]int)
-	for _, b := range borrows {
-		overdueCount[b.ClientID.Hex()]++
+	// Счётчик просрочек по пользователям страницы — по всем их выдачам, а не только по этой странице
+	clientIDs := make([]primitive.ObjectID, 0, len(borrows.Items))
+	for _, b := range borrows.Items {
+		clientIDs = append(clientIDs, b.ClientID)
+	}
+	overdueCount, err := uc.borrowRepo.CountOverdueByClients(ctx, now, clientIDs)
+	if err != nil {
+		return domain.Page[dto.OverdueReportItem]{}, fmt.Errorf("GetOverdueBorrows: %w", err)
 	}
 
-	var report []dto.OverdueReportItem
+	viewer, _ := auth.PrincipalFromContext(ctx)
+	report := make([]dto.OverdueReportItem, 0, len(borrows.Items))
 
-	for _, b := range borrows {
+	for _, b := range borrows.Items {
 		userID := b.ClientID.Hex()
 		bookID := b.BookID.Hex()
 
@@ -207,9 +351,9 @@ func (uc *BorrowUsecase) GetOverdueBorrows(ctx context.Context) ([]dto.OverdueRe
 		}
 
 		// Получаем книгу
-		book, err := uc.bookRepo.GetByID(ctx, bookID)
-		if err != nil || book == nil {
-			continue // можно логировать
+		book, err := uc.loanBook(ctx, b.BookID)
+		if err != nil {
+			return domain.Page[dto.OverdueReportItem]{}, fmt.Errorf("GetOverdueBorrows: get book: %w", err)
 		}
 
 		// Вычисляем просрочку
@@ -221,25 +365,36 @@ func (uc *BorrowUsecase) GetOverdueBorrows(ctx context.Context) ([]dto.OverdueRe
 		report = append(report, dto.OverdueReportItem{
 			UserID:       user.ID,
 			FullName:     user.FullName,
-			Phone:        user.Phone,
-			BookID:       book.ID,
+			Phone:        dto.PhoneFor(viewer, user.ID, user.Phone),
+			BookID:       bookID,
 			Title:        book.Title,
 			Author:       book.Author,
+			Withdrawn:    book.Withdrawn != nil,
 			BorrowedAt:   b.BorrowedAt,
 			DaysOverdue:  daysOverdue,
 			TotalOverdue: overdueCount[userID],
 		})
 	}
 
-	return report, nil
+	return domain.Page[dto.OverdueReportItem]{Items: report, Total: borrows.Total, Next: borrows.Next}, nil
 }
 
-func (uc *BorrowUsecase) GetDailyBorrowStats(ctx context.Context, from, to time.Time) ([]domain.BorrowStat, error) {
+// GetDailyBorrowStats — уникальные читатели по дням; с workID — только по изданиям произведения
+func (uc *BorrowUsecase) GetDailyBorrowStats(ctx context.Context, from, to time.Time, workID string) ([]domain.BorrowStat, error) {
 	if from.After(to) {
 		return nil, fmt.Errorf("GetDailyBorrowStats: invalid time range (from > to)")
 	}
 
-	stats, err := uc.borrowRepo.GetDailyStats(ctx, from, to)
+	var bookIDs []primitive.ObjectID
+	if workID != "" {
+		editions, err := workEditions(ctx, uc.bookRepo, workID)
+		if err != nil {
+			return nil, fmt.Errorf("GetDailyBorrowStats: %w", err)
+		}
+		bookIDs = editionIDs(editions)
+	}
+
+	stats, err := uc.borrowRepo.GetDailyStats(ctx, from, to, bookIDs)
 	if err != nil {
 		return nil, fmt.Errorf("GetDailyBorrowStats: %w", err)
 	}
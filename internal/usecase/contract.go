@@ -2,43 +2,276 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"library-Mongo/internal/auth"
 	"library-Mongo/internal/domain"
 	"library-Mongo/internal/usecase/dto"
 	"time"
 )
 
 type BookUC interface {
-	CreateBook(ctx context.Context, input dto.CreateBookInput) (domain.Book, error)
+	CreateBook(ctx context.Context, input dto.CreateBookInput) (dto.BookResponse, error)
 	UpdateBook(ctx context.Context, input dto.UpdateBookInput) error
-	DeleteBook(ctx context.Context, id string) error
-	GetBookByID(ctx context.Context, id string) (domain.Book, error)
-	SearchBooks(ctx context.Context, filter domain.BookFilter) ([]domain.Book, error)
+	// Проверки CreateBook и UpdateBook без сохранения (dry run импорта)
+	ValidateBook(ctx context.Context, input dto.CreateBookInput) error
+	ValidateBookUpdate(ctx context.Context, input dto.UpdateBookInput) error
+	// Списать книгу с причиной; книги на руках у читателей не списываются (librarian)
+	DeleteBook(ctx context.Context, id, reason string) error
+	// Вернуть списанную книгу в каталог (librarian)
+	RestoreBook(ctx context.Context, id string) error
+	// Списанная книга тоже возвращается, с полем withdrawn
+	GetBookByID(ctx context.Context, id string) (dto.BookResponse, error)
+	// Поиск по ISBN-10 или ISBN-13 (например, со сканера штрихкода)
+	GetBookByISBN(ctx context.Context, raw string) (dto.BookResponse, error)
+	// Страница каталога; withFacets — посчитать фасеты по всей выборке фильтра
+	SearchBooks(ctx context.Context, filter domain.BookFilter, page domain.PageRequest, withFacets bool) (dto.BookSearchResult, error)
 	CountBooks(ctx context.Context) (int64, error)
+	// Другие издания произведения книги и соседние тома её серии
+	RelatedBooks(ctx context.Context, id string) (dto.RelatedBooksResponse, error)
+}
+
+type DuplicateUC interface {
+	// Пары похожих книг со сходством не ниже minScore, от самых похожих (librarian)
+	FindDuplicates(ctx context.Context, minScore float64, limit int) ([]dto.DuplicateCandidate, error)
+	// Влить дубликат в книгу targetID; ID дубликата продолжает открывать targetID (librarian)
+	MergeBooks(ctx context.Context, targetID, duplicateID string) (dto.MergeBooksResponse, error)
+}
+
+type RevisionUC interface {
+	// Ревизии книги, по умолчанию от новых к старым (librarian)
+	ListRevisions(ctx context.Context, bookID string, page domain.PageRequest) (domain.Page[dto.BookRevisionResponse], error)
+	GetRevision(ctx context.Context, bookID string, number int) (dto.BookRevisionResponse, error)
+	// Изменённые поля между двумя ревизиями
+	DiffRevisions(ctx context.Context, bookID string, from, to int) (dto.RevisionDiff, error)
+	// Вернуть книге поля старой ревизии; откат записывается новой ревизией (librarian)
+	RevertBook(ctx context.Context, bookID string, number int) (dto.BookResponse, error)
+}
+
+type AuthorUC interface {
+	CreateAuthor(ctx context.Context, input dto.CreateAuthorInput) (dto.AuthorResponse, error)
+	UpdateAuthor(ctx context.Context, input dto.UpdateAuthorInput) error
+	DeleteAuthor(ctx context.Context, id string) error
+	// По ID влитой записи возвращает действующую
+	GetAuthor(ctx context.Context, id string) (dto.AuthorResponse, error)
+	// Поиск по любому написанию имени
+	SearchAuthors(ctx context.Context, name string, page domain.PageRequest) (domain.Page[dto.AuthorResponse], error)
+	// Влить дубликат в запись targetID с перепривязкой книг (librarian)
+	MergeAuthors(ctx context.Context, targetID, duplicateID string) (dto.MergeAuthorsResponse, error)
+}
+
+type GenreUC interface {
+	CreateGenre(ctx context.Context, input dto.CreateGenreInput) (dto.GenreResponse, error)
+	// Смена родителя переносит жанр вместе с поджанрами (librarian)
+	UpdateGenre(ctx context.Context, input dto.UpdateGenreInput) error
+	// Удаляется только жанр без книг и поджанров
+	DeleteGenre(ctx context.Context, id string) error
+	GetGenre(ctx context.Context, id string) (dto.GenreResponse, error)
+	// Дерево рубрикатора с числом книг в каждом узле
+	GenreTree(ctx context.Context) (dto.GenreTree, error)
+	// Влить дубликат в жанр targetID: книги и поджанры переносятся, дубликат удаляется (librarian)
+	MergeGenres(ctx context.Context, targetID, duplicateID string) (dto.MergeGenresResponse, error)
+}
+
+type WorkUC interface {
+	// Книги из BookIDs сразу становятся изданиями произведения (librarian)
+	CreateWork(ctx context.Context, input dto.CreateWorkInput) (dto.WorkResponse, error)
+	UpdateWork(ctx context.Context, input dto.UpdateWorkInput) error
+	// Удаляется только произведение без изданий
+	DeleteWork(ctx context.Context, id string) error
+	GetWork(ctx context.Context, id string) (dto.WorkResponse, error)
+	SearchWorks(ctx context.Context, title string, page domain.PageRequest) (domain.Page[dto.WorkResponse], error)
+	// Выдачи всех изданий произведения (librarian)
+	WorkBorrows(ctx context.Context, id string, page domain.PageRequest) (domain.Page[dto.BorrowHistoryItem], error)
+	// Выдачи и читатели по изданиям и в целом по произведению (librarian)
+	WorkStats(ctx context.Context, id string) (dto.WorkStatsResponse, error)
+}
+
+type SeriesUC interface {
+	CreateSeries(ctx context.Context, input dto.CreateSeriesInput) (dto.SeriesResponse, error)
+	// Новое название переносится во все книги серии (librarian)
+	UpdateSeries(ctx context.Context, input dto.UpdateSeriesInput) error
+	// Удаляется только серия без книг
+	DeleteSeries(ctx context.Context, id string) error
+	GetSeries(ctx context.Context, id string) (dto.SeriesResponse, error)
+	SearchSeries(ctx context.Context, title string, page domain.PageRequest) (domain.Page[dto.SeriesResponse], error)
+}
+
+type MARCUC interface {
+	// Импорт ISO 2709 или MARCXML с отчётом по записям; dryRun — только отчёт (librarian)
+	ImportMARC(ctx context.Context, r io.Reader, format string, dryRun bool) (dto.MARCImportReport, error)
+	// Выгрузка книг под фильтром в MARCXML потоком; возвращает число записей
+	ExportMARC(ctx context.Context, filter domain.BookFilter, w io.Writer) (int, error)
+}
+
+type SheetUC interface {
+	// Импорт книг из CSV/XLSX: строки без id заводятся, с id — обновляются; dryRun — только отчёт (librarian)
+	ImportBooks(ctx context.Context, r io.Reader, format string, mapping map[string]string, dryRun bool) (dto.SheetImportReport, error)
+	// Выгрузка книг под фильтром в CSV/XLSX; возвращает число книг
+	ExportBooks(ctx context.Context, filter domain.BookFilter, format string, w io.Writer) (int, error)
+}
+
+type CoverUC interface {
+	// Загрузить или заменить обложку книги (librarian)
+	SetCover(ctx context.Context, bookID string, r io.Reader) (dto.CoverResponse, error)
+	// Оригинал (size пустой) или миниатюра small/medium
+	GetCover(ctx context.Context, bookID, size string) (dto.CoverFile, error)
+	DeleteCover(ctx context.Context, bookID string) error
+}
+
+type ItemUC interface {
+	// Завести экземпляр книги (librarian)
+	CreateItem(ctx context.Context, input dto.CreateItemInput) (dto.ItemResponse, error)
+	// Изменить штрихкод, место хранения или статус (librarian)
+	UpdateItem(ctx context.Context, input dto.UpdateItemInput) error
+	DeleteItem(ctx context.Context, id string) error
+	GetItem(ctx context.Context, id string) (dto.ItemResponse, error)
+	GetItemByBarcode(ctx context.Context, barcode string) (dto.ItemResponse, error)
+	// Экземпляры одной книги
+	ListItems(ctx context.Context, bookID string) ([]dto.ItemResponse, error)
 }
 
 type UserUC interface {
-	RegisterUser(ctx context.Context, input dto.RegisterUserInput) (domain.User, error)
-	Login(ctx context.Context, phone, password string) (domain.User, error)
-	GetUserByID(ctx context.Context, id string) (domain.User, error)
-	SearchUsers(ctx context.Context, filter domain.UserFilter) ([]domain.User, error)
+	RegisterUser(ctx context.Context, input dto.RegisterUserInput) (dto.UserResponse, error)
+	Login(ctx context.Context, input dto.LoginInput) (dto.LoginResponse, error)
+	GetUserByID(ctx context.Context, id string) (dto.UserResponse, error)
+	// Подсказка не nil, если ФИО исправлено и страница — результат исправленного запроса
+	SearchUsers(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (domain.Page[dto.UserResponse], dto.SearchSuggestion, error)
 	UpdateUser(ctx context.Context, input dto.UpdateUserInput) error
 	DeleteUser(ctx context.Context, id string) error
 	CountUsers(ctx context.Context, filter *domain.UserFilter) (int64, error)
 	BlockUser(ctx context.Context, id string) error
 	UnblockUser(ctx context.Context, id string) error
+	// Журнал временных блокировок входа (admin)
+	ListLockoutEvents(ctx context.Context, filter domain.LockoutEventFilter) ([]domain.LockoutEvent, error)
+}
+
+type SessionUC interface {
+	// Обменять refresh-токен на новую пару (ротация с обнаружением повторного использования)
+	Refresh(ctx context.Context, refreshToken string) (dto.TokenPair, error)
+	// Завершить текущую сессию
+	Logout(ctx context.Context) error
+	// Активные сессии вызывающего
+	ListSessions(ctx context.Context) ([]dto.SessionResponse, error)
+	// Завершить одну свою сессию
+	RevokeSession(ctx context.Context, id string) error
+	// Завершить все свои сессии
+	RevokeAllSessions(ctx context.Context) (int64, error)
+	// Проверка сессии access-токена (для auth-middleware)
+	IsSessionActive(ctx context.Context, id string) (bool, error)
+}
+
+type VerificationUC interface {
+	// Отправить код сброса пароля на телефон
+	RequestPasswordReset(ctx context.Context, phone string) error
+	// Проверить код и получить токен сброса
+	VerifyPasswordReset(ctx context.Context, phone, code string) (dto.ResetTicketResponse, error)
+	// Установить новый пароль по токену сброса
+	ResetPassword(ctx context.Context, input dto.ResetPasswordInput) error
+	// Подтвердить телефон кодом из SMS
+	VerifyPhone(ctx context.Context, phone, code string) error
+	// Повторно отправить код подтверждения
+	ResendPhoneVerification(ctx context.Context, phone string) error
+}
+
+type TwoFactorUC interface {
+	// Второй шаг входа: код из приложения или код восстановления
+	CompleteLogin(ctx context.Context, input dto.TwoFactorLoginInput) (dto.LoginResponse, error)
+	// Привязка аутентификатора во время входа, когда роль требует 2FA
+	BeginLoginEnrollment(ctx context.Context, challengeToken string) (dto.TwoFactorEnrollment, error)
+	// Привязка аутентификатора вошедшим пользователем
+	BeginEnrollment(ctx context.Context) (dto.TwoFactorEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, code string) (dto.RecoveryCodesResponse, error)
+	GetStatus(ctx context.Context) (dto.TwoFactorStatus, error)
+	Disable(ctx context.Context, input dto.TwoFactorCodeRequest) error
+	// Сброс аутентификатора коллеги (admin)
+	ResetUser(ctx context.Context, userID string) error
+	// Роли с обязательной 2FA (admin)
+	GetPolicy(ctx context.Context) (dto.TwoFactorPolicy, error)
+	SetPolicy(ctx context.Context, roles []string) (dto.TwoFactorPolicy, error)
+}
+
+type APIKeyUC interface {
+	// Выпустить ключ (admin); полный ключ возвращается один раз
+	CreateAPIKey(ctx context.Context, input dto.CreateAPIKeyInput) (dto.APIKeyCreatedResponse, error)
+	ListAPIKeys(ctx context.Context, includeRevoked bool) ([]dto.APIKeyResponse, error)
+	GetAPIKey(ctx context.Context, id string) (dto.APIKeyResponse, error)
+	// Заменить секрет ключа (admin)
+	RotateAPIKey(ctx context.Context, id string) (dto.APIKeyCreatedResponse, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	// Проверка ключа запроса (для auth-middleware)
+	AuthenticateAPIKey(ctx context.Context, key, ip string) (auth.Principal, error)
+}
+
+type AuditUC interface {
+	// Записи журнала аудита по фильтру (admin)
+	ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+// AuditRecorder — запись изменений в журнал аудита (реализуется AuditUsecase)
+type AuditRecorder interface {
+	Record(ctx context.Context, action, entityType, entityID string, before, after any)
+}
+
+// TwoFactorGate — второй шаг входа для UserUsecase (реализуется TwoFactorUsecase)
+type TwoFactorGate interface {
+	LoginChallenge(ctx context.Context, user domain.User) (*dto.TwoFactorChallenge, error)
+}
+
+// PhoneVerifier — запуск подтверждения телефона при регистрации (реализуется VerificationUsecase)
+type PhoneVerifier interface {
+	StartPhoneVerification(ctx context.Context, user domain.User) error
+}
+
+// SessionManager — выдача и отзыв сессий для UserUsecase (реализуется SessionUsecase)
+type SessionManager interface {
+	StartSession(ctx context.Context, user domain.User, client dto.ClientInfo) (dto.TokenPair, error)
+	RevokeUserSessions(ctx context.Context, userID, reason string) error
+}
+
+// LoginThrottler — защита входа от перебора (реализуется LoginGuard)
+type LoginThrottler interface {
+	Check(ctx context.Context, phone, ip string) error
+	Failure(ctx context.Context, phone, ip string) error
+	Success(ctx context.Context, phone string) error
+	ListLockoutEvents(ctx context.Context, filter domain.LockoutEventFilter) ([]domain.LockoutEvent, error)
+}
+
+// TokenIssuer выпускает access-токены, привязанные к сессии
+type TokenIssuer interface {
+	IssueAccessToken(user domain.User, sessionID string) (string, time.Time, error)
+}
+
+// ChallengeIssuer выпускает и проверяет токены второго шага входа (реализуется auth.TokenManager)
+type ChallengeIssuer interface {
+	IssueChallengeToken(userID, purpose string) (string, time.Time, error)
+	ParseChallengeToken(raw string) (*auth.ChallengeClaims, error)
+}
+
+// SecretCipher шифрует TOTP-секреты (реализуется auth.SecretBox)
+type SecretCipher interface {
+	Seal(plain string) (string, error)
+	Open(sealed string) (string, error)
+}
+
+// PasswordHasher хэширует и проверяет пароли (реализуется auth.PasswordHasher)
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(stored, password string) (ok bool, needsRehash bool)
+	// VerifyDummy — проверка пароля неизвестного пользователя, по времени как Verify
+	VerifyDummy(password string)
 }
 
 type BorrowUC interface {
 	// Оформить выдачу книги (librarian)
-	BorrowBook(ctx context.Context, input dto.BorrowBookInput) (domain.Borrow, error)
+	BorrowBook(ctx context.Context, input dto.BorrowBookInput) (dto.BorrowResponse, error)
 	// Оформить возврат книги (librarian)
 	ReturnBook(ctx context.Context, input dto.ReturnBookInput) error
 	// История всех выдач конкретного читателя (reader/librarian)
-	GetBorrowHistory(ctx context.Context, userID string) (dto.BorrowHistoryResponse, error)
+	GetBorrowHistory(ctx context.Context, userID string, page domain.PageRequest) (dto.BorrowHistoryResponse, error)
 	//Список всех просроченных выдач (librarian)
-	GetOverdueBorrows(ctx context.Context) ([]dto.OverdueReportItem, error)
-	// Статистика уникальных читателей по дням/месяцам (для отчёта 3)
-	GetDailyBorrowStats(ctx context.Context, from, to time.Time) ([]domain.BorrowStat, error)
+	GetOverdueBorrows(ctx context.Context, page domain.PageRequest) (domain.Page[dto.OverdueReportItem], error)
+	// Статистика уникальных читателей по дням/месяцам (для отчёта 3); workID — по всем изданиям произведения
+	GetDailyBorrowStats(ctx context.Context, from, to time.Time, workID string) ([]domain.BorrowStat, error)
 	// Подсчитать число активных (не возвращённых) выдач
 	CountActiveBorrows(ctx context.Context) (int64, error)
 }
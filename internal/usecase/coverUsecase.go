@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/imaging"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"time"
+)
+
+// CoverUsecase — обложки книг: оригинал и миниатюры в хранилище, описание — в документе книги
+type CoverUsecase struct {
+	bookRepo repo.BookRepository
+	storage  repo.CoverStorage
+	audit    AuditRecorder
+	maxSize  int64
+}
+
+func NewCoverUsecase(bookRepo repo.BookRepository, storage repo.CoverStorage, audit AuditRecorder, maxSize int) *CoverUsecase {
+	return &CoverUsecase{bookRepo: bookRepo, storage: storage, audit: audit, maxSize: int64(maxSize)}
+}
+
+// SetCover заменяет обложку книги. Тип определяется по содержимому файла;
+// миниатюры строятся сразу, чтобы GET не пересчитывал их на каждый запрос
+func (uc *CoverUsecase) SetCover(ctx context.Context, bookID string, r io.Reader) (dto.CoverResponse, error) {
+	book, err := uc.getBook(ctx, bookID)
+	if err != nil {
+		return dto.CoverResponse{}, fmt.Errorf("SetCover: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.maxSize+1))
+	if err != nil {
+		return dto.CoverResponse{}, fmt.Errorf("SetCover: %w", err)
+	}
+	if int64(len(data)) > uc.maxSize {
+		return dto.CoverResponse{}, customErr.ErrCoverTooLarge
+	}
+	info, err := imaging.Inspect(data)
+	if err != nil {
+		return dto.CoverResponse{}, imageError(err)
+	}
+	thumbnails, err := imaging.Thumbnails(data, domain.CoverThumbnails)
+	if err != nil {
+		return dto.CoverResponse{}, imageError(err)
+	}
+
+	sum := sha256.Sum256(data)
+	cover := &domain.Cover{
+		Hash:        hex.EncodeToString(sum[:]),
+		ContentType: info.ContentType,
+		Size:        int64(len(data)),
+		Width:       info.Width,
+		Height:      info.Height,
+		UpdatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+
+	// Сначала файлы, потом описание: книга не ссылается на обложку, которой нет в хранилище
+	if err := uc.storage.Save(ctx, book.ID, domain.CoverOriginal, data); err != nil {
+		return dto.CoverResponse{}, fmt.Errorf("SetCover: %w", err)
+	}
+	for variant, thumb := range thumbnails {
+		if err := uc.storage.Save(ctx, book.ID, variant, thumb); err != nil {
+			return dto.CoverResponse{}, fmt.Errorf("SetCover: %w", err)
+		}
+	}
+	if err := uc.bookRepo.SetCover(ctx, book.ID, cover); err != nil {
+		return dto.CoverResponse{}, fmt.Errorf("SetCover: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditBookCover, domain.AuditEntityBook, book.ID, book.Cover, cover)
+
+	return *dto.NewCoverResponse(book.ID, cover), nil
+}
+
+// imageError — ошибки imaging в ошибки API
+func imageError(err error) error {
+	if errors.Is(err, imaging.ErrTooLarge) {
+		return fmt.Errorf("%w: %v", customErr.ErrCoverTooLarge, err)
+	}
+	return fmt.Errorf("%w: %v", customErr.ErrUnsupportedImage, err)
+}
+
+// GetCover — оригинал (size пустой) или миниатюра
+func (uc *CoverUsecase) GetCover(ctx context.Context, bookID, size string) (dto.CoverFile, error) {
+	variant := domain.CoverOriginal
+	if size != "" && size != domain.CoverOriginal {
+		if _, ok := domain.CoverThumbnails[size]; !ok {
+			return dto.CoverFile{}, customErr.ErrInvalidCoverSize
+		}
+		variant = size
+	}
+
+	book, err := uc.getBook(ctx, bookID)
+	if err != nil {
+		return dto.CoverFile{}, fmt.Errorf("GetCover: %w", err)
+	}
+	if book.Cover == nil {
+		return dto.CoverFile{}, customErr.ErrCoverNotFound
+	}
+
+	f, err := uc.storage.Open(ctx, book.ID, variant)
+	if err != nil {
+		return dto.CoverFile{}, fmt.Errorf("GetCover: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return dto.CoverFile{}, fmt.Errorf("GetCover: %w", err)
+	}
+
+	contentType := "image/jpeg"
+	if variant == domain.CoverOriginal {
+		contentType = book.Cover.ContentType
+	}
+	return dto.CoverFile{
+		Data:        data,
+		ContentType: contentType,
+		ETag:        `"` + book.Cover.Version() + "-" + variant + `"`,
+		Version:     book.Cover.Version(),
+		ModTime:     book.Cover.UpdatedAt,
+	}, nil
+}
+
+func (uc *CoverUsecase) DeleteCover(ctx context.Context, bookID string) error {
+	book, err := uc.getBook(ctx, bookID)
+	if err != nil {
+		return fmt.Errorf("DeleteCover: %w", err)
+	}
+	if book.Cover == nil {
+		return customErr.ErrCoverNotFound
+	}
+
+	if err := uc.bookRepo.SetCover(ctx, book.ID, nil); err != nil {
+		return fmt.Errorf("DeleteCover: %w", err)
+	}
+	if err := uc.storage.DeleteAll(ctx, book.ID); err != nil {
+		return fmt.Errorf("DeleteCover: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditBookCover, domain.AuditEntityBook, book.ID, book.Cover, nil)
+	return nil
+}
+
+func (uc *CoverUsecase) getBook(ctx context.Context, id string) (*domain.Book, error) {
+	return loadBook(ctx, uc.bookRepo, id)
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type CreateAPIKeyInput struct {
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`               // catalog:read, catalog:write, circulation:read, circulation:write
+	AllowedIPs []string   `json:"allowedIps,omitempty"` // IP или CIDR
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// APIKeyResponse — ключ без секрета
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowedIps,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	CreatedBy  string     `json:"createdBy"`
+	RotatedAt  *time.Time `json:"rotatedAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Active     bool       `json:"active"`
+}
+
+// APIKeyCreatedResponse — полный ключ показывается только при создании и ротации
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func NewAPIKeyResponse(k domain.APIKey, now time.Time) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		AllowedIPs: k.AllowedIPs,
+		ExpiresAt:  k.ExpiresAt,
+		CreatedAt:  k.CreatedAt,
+		CreatedBy:  k.CreatedBy,
+		RotatedAt:  k.RotatedAt,
+		LastUsedAt: k.LastUsedAt,
+		LastUsedIP: k.LastUsedIP,
+		RevokedAt:  k.RevokedAt,
+		Active:     k.Active(now),
+	}
+}
+
+func NewAPIKeyResponses(keys []domain.APIKey, now time.Time) []APIKeyResponse {
+	res := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, NewAPIKeyResponse(k, now))
+	}
+	return res
+}
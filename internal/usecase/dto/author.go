@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"fmt"
+	"library-Mongo/internal/domain"
+)
+
+type CreateAuthorInput struct {
+	Name      string   `json:"name"`     // каноническое имя: "Толстой, Лев Николаевич"
+	Variants  []string `json:"variants"` // другие написания
+	BirthYear int      `json:"birthYear,omitempty"`
+	DeathYear int      `json:"deathYear,omitempty"`
+}
+
+type UpdateAuthorInput struct {
+	ID        string    `json:"-"`
+	Name      *string   `json:"name,omitempty"` // новое имя переносится во все книги автора
+	Variants  *[]string `json:"variants,omitempty"`
+	BirthYear *int      `json:"birthYear,omitempty"`
+	DeathYear *int      `json:"deathYear,omitempty"`
+}
+
+// MergeAuthorsInput — запись-дубликат, которая вливается в запись из пути запроса
+type MergeAuthorsInput struct {
+	DuplicateID string `json:"duplicateId"`
+}
+
+// AuthorResponse — представление автора для API
+type AuthorResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Variants  []string `json:"variants,omitempty"`
+	BirthYear int      `json:"birthYear,omitempty"`
+	DeathYear int      `json:"deathYear,omitempty"`
+	Lifespan  string   `json:"lifespan,omitempty"` // "1828–1910", "1947–"
+}
+
+func NewAuthorResponse(a domain.Author) AuthorResponse {
+	resp := AuthorResponse{
+		ID:        a.ID,
+		Name:      a.Name,
+		Variants:  a.Variants,
+		BirthYear: a.BirthYear,
+		DeathYear: a.DeathYear,
+	}
+	switch {
+	case a.BirthYear != 0 && a.DeathYear != 0:
+		resp.Lifespan = fmt.Sprintf("%d–%d", a.BirthYear, a.DeathYear)
+	case a.BirthYear != 0:
+		resp.Lifespan = fmt.Sprintf("%d–", a.BirthYear)
+	case a.DeathYear != 0:
+		resp.Lifespan = fmt.Sprintf("?–%d", a.DeathYear)
+	}
+	return resp
+}
+
+func NewAuthorResponses(authors []domain.Author) []AuthorResponse {
+	res := make([]AuthorResponse, 0, len(authors))
+	for _, a := range authors {
+		res = append(res, NewAuthorResponse(a))
+	}
+	return res
+}
+
+// MergeAuthorsResponse — итог слияния: оставшаяся запись и число перепривязанных книг
+type MergeAuthorsResponse struct {
+	Author       AuthorResponse `json:"author"`
+	BooksUpdated int64          `json:"booksUpdated"`
+}
+
+// ContributorInput — участник книги: запись автора и роль (по умолчанию author)
+type ContributorInput struct {
+	AuthorID string `json:"authorId"`
+	Role     string `json:"role,omitempty"` // author, translator, illustrator, editor
+}
+
+type ContributorResponse struct {
+	AuthorID string `json:"authorId"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+}
+
+func NewContributorResponses(contributors []domain.Contributor) []ContributorResponse {
+	if len(contributors) == 0 {
+		return nil
+	}
+	res := make([]ContributorResponse, 0, len(contributors))
+	for _, c := range contributors {
+		res = append(res, ContributorResponse{AuthorID: c.AuthorID.Hex(), Name: c.Name, Role: c.Role})
+	}
+	return res
+}
@@ -1,10 +1,25 @@
 package dto
 
+import (
+	"fmt"
+	"library-Mongo/internal/domain"
+)
+
 type CreateBookInput struct {
 	Title  string
 	Author string
 	Year   int
 	Genre  string
+	ISBN   string // ISBN-10 или ISBN-13, с дефисами или без
+	Copies int    // сколько экземпляров завести сразу (штрихкоды по умолчанию)
+
+	GenreID string // жанр из рубрикатора; без него Genre ищется среди названий и синонимов рубрикатора
+
+	WorkID   string // произведение, изданием которого является книга
+	SeriesID string // серия
+	Volume   int    // номер тома в серии; 0 — без номера
+
+	Contributors []ContributorInput // авторы, переводчики, иллюстраторы; Author тогда собирается из авторов
 }
 
 type UpdateBookInput struct {
@@ -13,4 +28,92 @@ type UpdateBookInput struct {
 	Author *string
 	Year   *int
 	Genre  *string
+	ISBN   *string // пустая строка удаляет ISBN
+
+	GenreID *string // жанр из рубрикатора; пустая строка — жанр определяется по Genre
+
+	WorkID   *string // пустая строка отвязывает книгу от произведения
+	SeriesID *string // пустая строка убирает книгу из серии
+	Volume   *int
+
+	Contributors *[]ContributorInput
+
+	RevertOf int `json:"-"` // номер ревизии, к которой откатывается книга (RevisionUsecase.RevertBook)
+}
+
+// BookResponse — представление книги для API
+type BookResponse struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Year   int    `json:"year"`
+	Genre  string `json:"genre"`
+	ISBN13 string `json:"isbn13,omitempty"`
+	ISBN10 string `json:"isbn10,omitempty"`
+
+	GenreID      string                `json:"genreId,omitempty"` // жанр в рубрикаторе; пусто — жанр только строкой
+	WorkID       string                `json:"workId,omitempty"`  // произведение, изданием которого является книга
+	Series       *domain.SeriesEntry   `json:"series,omitempty"`
+	Editions     int                   `json:"editions,omitempty"` // groupBy=work: сколько изданий произведения нашлось
+	Contributors []ContributorResponse `json:"contributors,omitempty"`
+	Cover        *CoverResponse        `json:"cover,omitempty"`
+	Withdrawn    *domain.Withdrawal    `json:"withdrawn,omitempty"` // книга списана
+
+	Score        float64              `json:"score,omitempty"` // релевантность при поиске по q
+	Availability AvailabilityResponse `json:"availability"`
+}
+
+// BookSearchResult — страница каталога и, если запрошены, фасеты по всей выборке.
+// Total и Next отдаются в заголовках X-Total-Count и Link.
+type BookSearchResult struct {
+	Items      []BookResponse     `json:"items"`
+	Total      int64              `json:"-"`
+	Next       string             `json:"-"`
+	Facets     *domain.BookFacets `json:"facets,omitempty"`
+	DidYouMean SearchSuggestion   `json:"didYouMean,omitempty"` // по запросу как есть ничего не нашлось
+}
+
+// AvailabilityResponse — сколько экземпляров книги можно выдать сейчас
+type AvailabilityResponse struct {
+	Total     int    `json:"total"`
+	Available int    `json:"available"`
+	Summary   string `json:"summary"` // "3 of 5 available"
+}
+
+func NewAvailabilityResponse(a domain.Availability) AvailabilityResponse {
+	return AvailabilityResponse{
+		Total:     a.Total,
+		Available: a.Available,
+		Summary:   fmt.Sprintf("%d of %d available", a.Available, a.Total),
+	}
+}
+
+func NewBookResponse(b domain.Book, a domain.Availability) BookResponse {
+	return BookResponse{
+		ID:           b.ID,
+		Title:        b.Title,
+		Author:       b.Author,
+		Year:         b.Year,
+		Genre:        b.Genre,
+		GenreID:      b.GenreID,
+		WorkID:       b.WorkID,
+		Series:       b.Series,
+		Editions:     b.Editions,
+		ISBN13:       b.ISBN13,
+		ISBN10:       b.ISBN10,
+		Contributors: NewContributorResponses(b.Contributors),
+		Cover:        NewCoverResponse(b.ID, b.Cover),
+		Withdrawn:    b.Withdrawn,
+		Score:        b.Score,
+		Availability: NewAvailabilityResponse(a),
+	}
+}
+
+// NewBookResponses — availability по ID книги; книги без экземпляров получают 0 of 0
+func NewBookResponses(books []domain.Book, availability map[string]domain.Availability) []BookResponse {
+	res := make([]BookResponse, 0, len(books))
+	for _, b := range books {
+		res = append(res, NewBookResponse(b, availability[b.ID]))
+	}
+	return res
 }
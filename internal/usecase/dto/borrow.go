@@ -1,17 +1,21 @@
 package dto
 
 import (
+	"library-Mongo/internal/domain"
 	"time"
 )
 
 type BorrowHistoryItem struct {
 	BorrowID   string     `json:"borrowId"`
+	UserID     string     `json:"userId,omitempty"` // читатель — в истории выдач произведения
 	BookID     string     `json:"bookId"`
+	ItemID     string     `json:"itemId,omitempty"`
 	Title      string     `json:"title"`
 	Author     string     `json:"author"`
 	BorrowedAt time.Time  `json:"borrowedAt"`
 	ReturnedAt *time.Time `json:"returnedAt,omitempty"`
-	Status     string     `json:"status"` // "ok" / "overdue"
+	Status     string     `json:"status"`              // "ok" / "overdue"
+	Withdrawn  bool       `json:"withdrawn,omitempty"` // книга с тех пор списана
 }
 
 type BorrowHistoryResponse struct {
@@ -19,11 +23,17 @@ type BorrowHistoryResponse struct {
 	FullName string              `json:"fullName"`
 	Phone    string              `json:"phone"`
 	History  []BorrowHistoryItem `json:"history"`
+	Total    int64               `json:"total"`          // всего выдач у читателя
+	Next     string              `json:"next,omitempty"` // курсор следующей страницы (параметр after)
 }
 
+// BorrowBookInput — экземпляр задаётся itemId или barcode; если указан только bookId,
+// выдаётся любой свободный экземпляр книги
 type BorrowBookInput struct {
-	UserID string `json:"userId"`
-	BookID string `json:"bookId"`
+	UserID  string `json:"userId"`
+	BookID  string `json:"bookId,omitempty"`
+	ItemID  string `json:"itemId,omitempty"`
+	Barcode string `json:"barcode,omitempty"`
 }
 
 type ReturnBookInput struct {
@@ -37,7 +47,29 @@ type OverdueReportItem struct {
 	BookID       string    `json:"bookId"`
 	Title        string    `json:"title"`
 	Author       string    `json:"author"`
+	Withdrawn    bool      `json:"withdrawn,omitempty"` // книга списана (выдана до списания)
 	BorrowedAt   time.Time `json:"borrowedAt"`
 	DaysOverdue  int       `json:"daysOverdue"`
 	TotalOverdue int       `json:"totalOverdue"` // для повторяющихся читателей
 }
+
+// BorrowResponse — представление выдачи для API
+type BorrowResponse struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	BookID     string     `json:"bookId"`
+	ItemID     string     `json:"itemId,omitempty"`
+	Barcode    string     `json:"barcode,omitempty"`
+	BorrowedAt time.Time  `json:"borrowedAt"`
+	ReturnedAt *time.Time `json:"returnedAt,omitempty"`
+}
+
+func NewBorrowResponse(b domain.Borrow) BorrowResponse {
+	return BorrowResponse{
+		ID:         b.ID,
+		UserID:     b.ClientID.Hex(),
+		BookID:     b.BookID.Hex(),
+		BorrowedAt: b.BorrowedAt,
+		ReturnedAt: b.ReturnedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+// CoverResponse — обложка книги. Адреса содержат версию (?v=), поэтому их можно кэшировать бессрочно
+type CoverResponse struct {
+	URL         string            `json:"url"`
+	Thumbnails  map[string]string `json:"thumbnails"` // размер (small, medium) -> адрес миниатюры
+	ContentType string            `json:"contentType"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Size        int64             `json:"size"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// NewCoverResponse — nil, если у книги нет обложки
+func NewCoverResponse(bookID string, c *domain.Cover) *CoverResponse {
+	if c == nil {
+		return nil
+	}
+	url := "/books/" + bookID + "/cover"
+	res := &CoverResponse{
+		URL:         url + "?v=" + c.Version(),
+		Thumbnails:  make(map[string]string, len(domain.CoverThumbnails)),
+		ContentType: c.ContentType,
+		Width:       c.Width,
+		Height:      c.Height,
+		Size:        c.Size,
+		UpdatedAt:   c.UpdatedAt,
+	}
+	for size := range domain.CoverThumbnails {
+		res.Thumbnails[size] = url + "?size=" + size + "&v=" + c.Version()
+	}
+	return res
+}
+
+// CoverFile — содержимое файла обложки с данными для заголовков кэширования
+type CoverFile struct {
+	Data        []byte
+	ContentType string
+	ETag        string // в кавычках, готов для заголовка
+	Version     string // текущая версия обложки (сравнивается с ?v=)
+	ModTime     time.Time
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "library-Mongo/internal/domain"
+
+// DuplicateCandidate — пара книг, похожих на одну запись, введённую дважды.
+// Score от 0 до 1; TitleScore и AuthorScore — сходство нормализованных названий и авторов
+type DuplicateCandidate struct {
+	Score       float64          `json:"score"`
+	TitleScore  float64          `json:"titleScore"`
+	AuthorScore float64          `json:"authorScore"`
+	SameYear    bool             `json:"sameYear"`
+	Books       [2]DuplicateBook `json:"books"`
+}
+
+// DuplicateBook — поля книги, по которым библиотекарь решает, какую запись оставить
+type DuplicateBook struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Year   int    `json:"year"`
+	ISBN13 string `json:"isbn13,omitempty"`
+}
+
+func NewDuplicateBook(b domain.Book) DuplicateBook {
+	return DuplicateBook{
+		ID:     b.ID,
+		Title:  b.Title,
+		Author: b.Author,
+		Year:   b.Year,
+		ISBN13: b.ISBN13,
+	}
+}
+
+// MergeBooksInput — книга-дубликат, которая вливается в книгу из пути запроса
+type MergeBooksInput struct {
+	DuplicateID string `json:"duplicateId"`
+}
+
+// MergeBooksResponse — итог слияния: оставшаяся книга и сколько выдач и экземпляров на неё перенесено
+type MergeBooksResponse struct {
+	Book            BookResponse `json:"book"`
+	BorrowsRelinked int64        `json:"borrowsRelinked"`
+	ItemsRelinked   int64        `json:"itemsRelinked"`
+}
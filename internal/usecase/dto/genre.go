@@ -0,0 +1,73 @@
+package dto
+
+import "library-Mongo/internal/domain"
+
+type CreateGenreInput struct {
+	LabelRU  string   `json:"labelRu"`            // название по-русски, обязательно
+	LabelEN  string   `json:"labelEn,omitempty"`  // название по-английски
+	Synonyms []string `json:"synonyms,omitempty"` // другие написания: "sci-fi", "НФ"
+	ParentID string   `json:"parentId,omitempty"` // родительский жанр; пусто — корень рубрикатора
+}
+
+type UpdateGenreInput struct {
+	ID       string    `json:"-"`
+	LabelRU  *string   `json:"labelRu,omitempty"` // новое название переносится во все книги жанра
+	LabelEN  *string   `json:"labelEn,omitempty"`
+	Synonyms *[]string `json:"synonyms,omitempty"`
+	ParentID *string   `json:"parentId,omitempty"` // перенос жанра вместе с поджанрами; "" — в корень
+}
+
+// MergeGenresInput — жанр-дубликат, который вливается в жанр из пути запроса
+type MergeGenresInput struct {
+	DuplicateID string `json:"duplicateId"`
+}
+
+// GenreResponse — представление жанра для API
+type GenreResponse struct {
+	ID        string   `json:"id"`
+	ParentID  string   `json:"parentId,omitempty"`
+	Ancestors []string `json:"ancestors"` // ID предков от корня до родителя
+	LabelRU   string   `json:"labelRu"`
+	LabelEN   string   `json:"labelEn,omitempty"`
+	Synonyms  []string `json:"synonyms,omitempty"`
+}
+
+func NewGenreResponse(g domain.Genre) GenreResponse {
+	ancestors := g.Ancestors
+	if ancestors == nil {
+		ancestors = []string{}
+	}
+	return GenreResponse{
+		ID:        g.ID,
+		ParentID:  g.ParentID,
+		Ancestors: ancestors,
+		LabelRU:   g.LabelRU,
+		LabelEN:   g.LabelEN,
+		Synonyms:  g.Synonyms,
+	}
+}
+
+// MergeGenresResponse — итог слияния: оставшийся жанр, число перепривязанных книг и перенесённых поджанров
+type MergeGenresResponse struct {
+	Genre        GenreResponse `json:"genre"`
+	BooksUpdated int64         `json:"booksUpdated"`
+	GenresMoved  int64         `json:"genresMoved"`
+}
+
+// GenreNode — узел дерева рубрикатора. Books — книги, отнесённые к самому жанру,
+// TotalBooks — вместе с поджанрами (столько найдёт поиск по жанру)
+type GenreNode struct {
+	ID         string      `json:"id"`
+	LabelRU    string      `json:"labelRu"`
+	LabelEN    string      `json:"labelEn,omitempty"`
+	Synonyms   []string    `json:"synonyms,omitempty"`
+	Books      int64       `json:"books"`
+	TotalBooks int64       `json:"totalBooks"`
+	Children   []GenreNode `json:"children"`
+}
+
+// GenreTree — рубрикатор целиком; Unclassified — книги, жанр которых не найден в рубрикаторе
+type GenreTree struct {
+	Genres       []GenreNode `json:"genres"`
+	Unclassified int64       `json:"unclassified"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+type CreateItemInput struct {
+	BookID     string     `json:"-"`
+	Barcode    string     `json:"barcode"`
+	Location   string     `json:"location"`
+	AcquiredAt *time.Time `json:"acquiredAt,omitempty"` // по умолчанию — сейчас
+	Status     string     `json:"status,omitempty"`     // по умолчанию available
+}
+
+type UpdateItemInput struct {
+	ID         string     `json:"-"`
+	Barcode    *string    `json:"barcode,omitempty"`
+	Location   *string    `json:"location,omitempty"`
+	AcquiredAt *time.Time `json:"acquiredAt,omitempty"`
+	Status     *string    `json:"status,omitempty"` // available, in_repair, lost
+}
+
+// ItemResponse — представление экземпляра для API
+type ItemResponse struct {
+	ID         string    `json:"id"`
+	BookID     string    `json:"bookId"`
+	Barcode    string    `json:"barcode"`
+	Status     string    `json:"status"`
+	Location   string    `json:"location"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+}
+
+func NewItemResponse(i domain.Item) ItemResponse {
+	return ItemResponse{
+		ID:         i.ID,
+		BookID:     i.BookID.Hex(),
+		Barcode:    i.Barcode,
+		Status:     i.Status,
+		Location:   i.Location,
+		AcquiredAt: i.AcquiredAt,
+	}
+}
+
+func NewItemResponses(items []domain.Item) []ItemResponse {
+	res := make([]ItemResponse, 0, len(items))
+	for _, i := range items {
+		res = append(res, NewItemResponse(i))
+	}
+	return res
+}
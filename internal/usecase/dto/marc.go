@@ -0,0 +1,56 @@
+package dto
+
+// Статусы записи в отчёте импорта MARC
+const (
+	ImportCreated   = "created"   // книга заведена
+	ImportReady     = "ready"     // dry run: книга была бы заведена
+	ImportDuplicate = "duplicate" // такая книга уже есть в каталоге или раньше в этом же файле
+	ImportInvalid   = "invalid"   // запись не разобрана или в ней нет обязательных полей
+	ImportFailed    = "failed"    // запись корректна, но сохранить её не удалось
+)
+
+// MARCImportRecord — результат по одной записи файла
+type MARCImportRecord struct {
+	Index         int      `json:"index"`                   // номер записи в файле, с 1
+	ControlNumber string   `json:"controlNumber,omitempty"` // поле 001 исходной записи
+	Title         string   `json:"title,omitempty"`
+	Author        string   `json:"author,omitempty"`
+	ISBN          string   `json:"isbn,omitempty"` // ISBN-13
+	Status        string   `json:"status"`
+	BookID        string   `json:"bookId,omitempty"`            // заведённая книга
+	DuplicateOf   string   `json:"duplicateOf,omitempty"`       // ID уже существующей книги
+	DuplicateOfNo int      `json:"duplicateOfRecord,omitempty"` // номер более ранней записи того же файла
+	NewAuthors    []string `json:"newAuthors,omitempty"`        // имена, которых нет в справочнике авторов
+	Warnings      []string `json:"warnings,omitempty"`          // проигнорированные значения
+	Error         string   `json:"error,omitempty"`
+}
+
+// MARCImportReport — итог импорта; при DryRun каталог не меняется
+type MARCImportReport struct {
+	DryRun     bool               `json:"dryRun"`
+	Total      int                `json:"total"`
+	Created    int                `json:"created"`
+	Ready      int                `json:"ready"`
+	Duplicates int                `json:"duplicates"`
+	Invalid    int                `json:"invalid"`
+	Failed     int                `json:"failed"`
+	Records    []MARCImportRecord `json:"records"`
+}
+
+// Add учитывает запись в счётчиках отчёта
+func (r *MARCImportReport) Add(rec MARCImportRecord) {
+	r.Total++
+	switch rec.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportReady:
+		r.Ready++
+	case ImportDuplicate:
+		r.Duplicates++
+	case ImportInvalid:
+		r.Invalid++
+	case ImportFailed:
+		r.Failed++
+	}
+	r.Records = append(r.Records, rec)
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "library-Mongo/internal/auth"
+
+// PhoneFor возвращает телефон владельца ownerID таким, каким его может видеть viewer
+func PhoneFor(viewer auth.Principal, ownerID, phone string) string {
+	if auth.CanAccessOwn(viewer, ownerID) {
+		return phone
+	}
+	return MaskPhone(phone)
+}
+
+// MaskPhone оставляет первые два и последние два символа: +79161234567 -> +7********67
+func MaskPhone(phone string) string {
+	r := []rune(phone)
+	if len(r) <= 4 {
+		return "****"
+	}
+	masked := make([]rune, len(r))
+	for i := range r {
+		if i < 2 || i >= len(r)-2 {
+			masked[i] = r[i]
+		} else {
+			masked[i] = '*'
+		}
+	}
+	return string(masked)
+}
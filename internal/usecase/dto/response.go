@@ -1,9 +1,38 @@
 package dto
 
+import (
+	"time"
+)
+
 type ErrorResponse struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"` // стабильный машиночитаемый код ошибки
 }
 
+// Коды ошибок доступа
+const (
+	CodeAuthRequired     = "auth_required"
+	CodeInvalidToken     = "invalid_token"
+	CodeSessionRevoked   = "session_revoked"
+	CodeTokenReused      = "refresh_token_reused"
+	CodeLoginLocked      = "login_temporarily_locked"
+	CodePhoneNotVerified = "phone_not_verified"
+	CodeInvalidCode      = "invalid_code"
+	CodeCodeAttempts     = "code_attempts_exceeded"
+	CodeRoleForbidden    = "role_forbidden"
+	CodeNotOwner         = "not_owner"
+	CodeTwoFactorEnabled = "two_factor_enabled"
+	CodeTwoFactorMissing = "two_factor_not_enabled"
+	CodeTwoFactorNeeded  = "two_factor_required"
+	CodeInvalidAPIKey    = "invalid_api_key"
+	CodeIPNotAllowed     = "ip_not_allowed"
+	CodeScopeForbidden   = "scope_forbidden"
+)
+
+// SearchSuggestion — "возможно, вы имели в виду": параметр запроса -> значение с исправленными опечатками.
+// Ответ, к которому приложена подсказка, уже содержит результаты исправленного запроса
+type SearchSuggestion map[string]string
+
 type SuccessResponse struct {
 	Status string `json:"status"`
 }
@@ -20,3 +49,25 @@ type LoginRequest struct {
 	Phone    string `json:"phone"`
 	Password string `json:"password"`
 }
+
+// TokenPair — access-токен и refresh-токен сессии
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	TokenType        string    `json:"tokenType"` // всегда "Bearer"
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// LoginResponse — либо пара токенов с пользователем, либо (при 2FA) только twoFactor:
+// тогда вход завершается через POST /users/login/2fa
+type LoginResponse struct {
+	*TokenPair
+	User          *UserResponse       `json:"user,omitempty"`
+	TwoFactor     *TwoFactorChallenge `json:"twoFactor,omitempty"`
+	RecoveryCodes []string            `json:"recoveryCodes,omitempty"` // выдаются один раз при привязке во время входа
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+// BookRevisionResponse — ревизия книги: состояние записи после изменения и кто его внёс
+type BookRevisionResponse struct {
+	Number   int               `json:"number"`
+	Action   string            `json:"action"`             // create, update, revert, baseline
+	RevertOf int               `json:"revertOf,omitempty"` // для revert — номер восстановленной ревизии
+	Record   domain.BookRecord `json:"record"`
+	Actor    domain.AuditActor `json:"actor"`
+	At       time.Time         `json:"at"`
+}
+
+func NewBookRevisionResponse(r domain.BookRevision) BookRevisionResponse {
+	return BookRevisionResponse{
+		Number:   r.Number,
+		Action:   r.Action,
+		RevertOf: r.RevertOf,
+		Record:   r.Record,
+		Actor:    r.Actor,
+		At:       r.At,
+	}
+}
+
+func NewBookRevisionResponses(revs []domain.BookRevision) []BookRevisionResponse {
+	res := make([]BookRevisionResponse, 0, len(revs))
+	for _, r := range revs {
+		res = append(res, NewBookRevisionResponse(r))
+	}
+	return res
+}
+
+// RevisionDiff — поля, которые отличаются между ревизиями From и To (from — значение в From)
+type RevisionDiff struct {
+	BookID  string                        `json:"bookId"`
+	From    int                           `json:"from"`
+	To      int                           `json:"to"`
+	Changes map[string]domain.AuditChange `json:"changes"`
+}
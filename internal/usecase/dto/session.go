@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"library-Mongo/internal/domain"
+	"time"
+)
+
+// SessionResponse — активная сессия (устройство) пользователя
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"` // сессия, из которой сделан запрос
+}
+
+func NewSessionResponse(s domain.Session, currentID string) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.ID == currentID,
+	}
+}
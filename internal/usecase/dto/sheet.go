@@ -0,0 +1,54 @@
+package dto
+
+// Статусы строки в отчёте импорта таблицы
+const (
+	RowCreated   = "created"   // книга заведена
+	RowUpdated   = "updated"   // книга из колонки id изменена
+	RowUnchanged = "unchanged" // значения совпадают с каталогом, запись не трогалась
+	RowValid     = "valid"     // dry run: строка прошла проверки
+	RowInvalid   = "invalid"   // строка не прошла проверки
+	RowFailed    = "failed"    // строка корректна, но сохранить её не удалось
+)
+
+// SheetImportRow — результат по одной строке таблицы
+type SheetImportRow struct {
+	Row    int      `json:"row"`              // номер строки в файле; заголовок — строка 1
+	Action string   `json:"action"`           // create или update (есть id)
+	Status string   `json:"status"`           // created, updated, unchanged, valid, invalid, failed
+	BookID string   `json:"bookId,omitempty"` // заведённая или изменённая книга
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SheetImportReport — итог импорта; при DryRun каталог не меняется
+type SheetImportReport struct {
+	DryRun    bool              `json:"dryRun"`
+	Columns   map[string]string `json:"columns"` // поле книги -> заголовок колонки
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Unchanged int               `json:"unchanged"`
+	Valid     int               `json:"valid"`
+	Invalid   int               `json:"invalid"`
+	Failed    int               `json:"failed"`
+	Rows      []SheetImportRow  `json:"rows"`
+}
+
+// Add учитывает строку в счётчиках отчёта
+func (r *SheetImportReport) Add(row SheetImportRow) {
+	r.Total++
+	switch row.Status {
+	case RowCreated:
+		r.Created++
+	case RowUpdated:
+		r.Updated++
+	case RowUnchanged:
+		r.Unchanged++
+	case RowValid:
+		r.Valid++
+	case RowInvalid:
+		r.Invalid++
+	case RowFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
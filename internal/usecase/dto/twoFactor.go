@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+)
+
+// TwoFactorChallenge — второй шаг входа: пароль проверен, нужен код
+type TwoFactorChallenge struct {
+	ChallengeToken string    `json:"challengeToken"`
+	Type           string    `json:"type"` // "verify" — ввести код, "enroll" — сначала привязать аутентификатор
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+// TwoFactorLoginRequest — код из приложения или код восстановления
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recoveryCode,omitempty"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string
+	Code           string
+	RecoveryCode   string
+	Client         ClientInfo
+}
+
+type ChallengeRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+}
+
+// TwoFactorEnrollment — данные для приложения-аутентификатора
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"` // base32, для ручного ввода
+	URI    string `json:"uri"`    // otpauth://totp/..., содержимое QR-кода
+}
+
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // показываются один раз
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabledAt,omitempty"`
+	RecoveryCodesLeft int        `json:"recoveryCodesLeft"`
+	Required          bool       `json:"required"` // обязательна для роли пользователя
+}
+
+// TwoFactorPolicy — роли, для которых 2FA обязательна
+type TwoFactorPolicy struct {
+	Roles     []string   `json:"roles"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy string     `json:"updatedBy,omitempty"`
+}
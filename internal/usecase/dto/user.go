@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"library-Mongo/internal/auth"
+	"library-Mongo/internal/domain"
+	"time"
+)
+
 type RegisterUserInput struct {
 	FullName string
 	Phone    string
@@ -10,6 +16,13 @@ type RegisterUserInput struct {
 type LoginInput struct {
 	Phone    string
 	Password string
+	Client   ClientInfo
+}
+
+// ClientInfo — откуда пришёл запрос (для сессий)
+type ClientInfo struct {
+	IP        string
+	UserAgent string
 }
 
 type UpdateUserInput struct {
@@ -20,3 +33,58 @@ type UpdateUserInput struct {
 	Role     *string
 	IsActive *bool
 }
+
+// UserResponse — представление пользователя для API (без пароля)
+type UserResponse struct {
+	ID           string `json:"id"`
+	FullName     string `json:"fullName"`
+	Role         string `json:"role"`
+	Phone        string `json:"phone"` // маскируется, если смотрит другой читатель
+	RegisteredAt string `json:"registeredAt"`
+	IsActive     bool   `json:"isActive"`
+
+	PendingVerification bool `json:"pendingVerification,omitempty"` // ждёт подтверждения телефона
+}
+
+// NewUserResponse формирует ответ с учётом роли смотрящего:
+// сотрудник и сам пользователь видят телефон полностью, остальные — маску
+func NewUserResponse(u domain.User, viewer auth.Principal) UserResponse {
+	return UserResponse{
+		ID:           u.ID,
+		FullName:     u.FullName,
+		Role:         u.Role,
+		Phone:        PhoneFor(viewer, u.ID, u.Phone),
+		RegisteredAt: u.RegisteredAt,
+		IsActive:     u.IsActive,
+
+		PendingVerification: u.PendingVerification,
+	}
+}
+
+func NewUserResponses(users []domain.User, viewer auth.Principal) []UserResponse {
+	res := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		res = append(res, NewUserResponse(u, viewer))
+	}
+	return res
+}
+
+type PhoneRequest struct {
+	Phone string `json:"phone"`
+}
+
+type PhoneCodeRequest struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
+// ResetTicketResponse — токен сброса пароля, выдаётся после проверки кода
+type ResetTicketResponse struct {
+	ResetToken string    `json:"resetToken"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type ResetPasswordInput struct {
+	ResetToken  string `json:"resetToken"`
+	NewPassword string `json:"newPassword"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import "library-Mongo/internal/domain"
+
+type CreateWorkInput struct {
+	Title   string   `json:"title"`             // каноническое название: "Война и мир"
+	Author  string   `json:"author,omitempty"`  // автор произведения
+	Year    int      `json:"year,omitempty"`    // год первой публикации
+	BookIDs []string `json:"bookIds,omitempty"` // книги, которые сразу становятся изданиями произведения
+}
+
+type UpdateWorkInput struct {
+	ID     string  `json:"-"`
+	Title  *string `json:"title,omitempty"`
+	Author *string `json:"author,omitempty"`
+	Year   *int    `json:"year,omitempty"`
+}
+
+// WorkResponse — представление произведения для API; издания — GET /books?workId=...
+type WorkResponse struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author,omitempty"`
+	Year   int    `json:"year,omitempty"`
+}
+
+func NewWorkResponse(w domain.Work) WorkResponse {
+	return WorkResponse{ID: w.ID, Title: w.Title, Author: w.Author, Year: w.Year}
+}
+
+func NewWorkResponses(works []domain.Work) []WorkResponse {
+	res := make([]WorkResponse, 0, len(works))
+	for _, w := range works {
+		res = append(res, NewWorkResponse(w))
+	}
+	return res
+}
+
+// WorkStatsResponse — выдачи произведения по всем изданиям, включая списанные.
+// UniqueReaders — читатели без повторов: взявший два издания считается один раз
+type WorkStatsResponse struct {
+	Work          WorkResponse         `json:"work"`
+	Editions      int                  `json:"editions"`
+	Borrows       int64                `json:"borrows"`
+	ActiveBorrows int64                `json:"activeBorrows"`
+	UniqueReaders int                  `json:"uniqueReaders"`
+	ByEdition     []EditionBorrowStats `json:"byEdition"`
+}
+
+// EditionBorrowStats — выдачи одного издания
+type EditionBorrowStats struct {
+	BookID        string `json:"bookId"`
+	Title         string `json:"title"`
+	Year          int    `json:"year"`
+	ISBN13        string `json:"isbn13,omitempty"`
+	Withdrawn     bool   `json:"withdrawn,omitempty"`
+	Borrows       int64  `json:"borrows"`
+	ActiveBorrows int64  `json:"activeBorrows"`
+	UniqueReaders int    `json:"uniqueReaders"`
+}
+
+type CreateSeriesInput struct {
+	Title string `json:"title"` // название серии: "Хроники Нарнии"
+}
+
+type UpdateSeriesInput struct {
+	ID    string  `json:"-"`
+	Title *string `json:"title,omitempty"` // новое название переносится во все книги серии
+}
+
+// SeriesResponse — представление серии для API; тома — GET /books?seriesId=...&sort=volume
+type SeriesResponse struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func NewSeriesResponse(s domain.Series) SeriesResponse {
+	return SeriesResponse{ID: s.ID, Title: s.Title}
+}
+
+func NewSeriesResponses(series []domain.Series) []SeriesResponse {
+	res := make([]SeriesResponse, 0, len(series))
+	for _, s := range series {
+		res = append(res, NewSeriesResponse(s))
+	}
+	return res
+}
+
+// RelatedBooksResponse — что показать на странице книги: другие издания того же произведения
+// и соседние тома серии. Списанные книги не показываются
+type RelatedBooksResponse struct {
+	Work     *WorkResponse       `json:"work,omitempty"`
+	Editions []BookResponse      `json:"editions"`
+	Series   *domain.SeriesEntry `json:"series,omitempty"`
+	Previous *BookResponse       `json:"previousVolume,omitempty"`
+	Next     *BookResponse       `json:"nextVolume,omitempty"`
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/textsim"
+	"library-Mongo/internal/usecase/dto"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Веса сходства пары книг: название решает больше всего, год только уточняет
+const (
+	duplicateTitleWeight  = 0.6
+	duplicateAuthorWeight = 0.3
+	duplicateYearWeight   = 0.1
+
+	// duplicateISBNPenalty — множитель для книг с разными ISBN: скорее это разные издания
+	duplicateISBNPenalty = 0.7
+	// duplicateMaxBlock — слово, которое встречается у большего числа книг, не сводит их в пары
+	duplicateMaxBlock = 500
+)
+
+// DuplicateUsecase — поиск книг, введённых в каталог несколько раз, и их слияние.
+// Дубликат после слияния остаётся перенаправлением: его ID открывает оставшуюся книгу
+type DuplicateUsecase struct {
+	bookRepo   repo.BookRepository
+	itemRepo   repo.ItemRepository
+	borrowRepo repo.BorrowRepository
+	books      BookUC
+	audit      AuditRecorder
+}
+
+func NewDuplicateUsecase(bookRepo repo.BookRepository, itemRepo repo.ItemRepository, borrowRepo repo.BorrowRepository, books BookUC, audit AuditRecorder) *DuplicateUsecase {
+	return &DuplicateUsecase{
+		bookRepo:   bookRepo,
+		itemRepo:   itemRepo,
+		borrowRepo: borrowRepo,
+		books:      books,
+		audit:      audit,
+	}
+}
+
+// dupEntry — книга с заранее посчитанными ключами сравнения
+type dupEntry struct {
+	book   domain.Book
+	title  string
+	author string
+}
+
+// FindDuplicates сравнивает книги каталога (без списанных) попарно и возвращает пары со сходством
+// не ниже minScore, от самых похожих. Сравниваются только книги с общим словом названия или автора
+func (uc *DuplicateUsecase) FindDuplicates(ctx context.Context, minScore float64, limit int) ([]dto.DuplicateCandidate, error) {
+	var entries []dupEntry
+	blocks := map[string][]int{}
+	err := uc.bookRepo.Each(ctx, domain.BookFilter{}, func(b domain.Book) error {
+		e := dupEntry{book: b, title: textsim.Key(b.Title), author: authorKey(b.Author)}
+		for _, key := range blockKeys(e) {
+			blocks[key] = append(blocks[key], len(entries))
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FindDuplicates: %w", err)
+	}
+
+	seen := map[[2]int]bool{}
+	res := []dto.DuplicateCandidate{}
+	for _, block := range blocks {
+		if len(block) > duplicateMaxBlock {
+			continue
+		}
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				pair := [2]int{block[x], block[y]}
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+				if c := compareBooks(entries[pair[0]], entries[pair[1]]); c.Score >= minScore {
+					res = append(res, c)
+				}
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Books[0].ID < res[j].Books[0].ID
+	})
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// authorKey — SortedKey автора без инициалов: "Булгаков М.А." и "Булгаков" совпадают
+func authorKey(author string) string {
+	var words []string
+	for _, w := range strings.Fields(textsim.SortedKey(author)) {
+		if utf8.RuneCountInString(w) > 1 {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// blockKeys — ключи групп, внутри которых книги сравниваются попарно: название целиком
+// и значимые слова названия и автора. Опечатка в одном слове не мешает найти пару по другому
+func blockKeys(e dupEntry) []string {
+	var keys []string
+	if e.title != "" {
+		keys = append(keys, "k:"+e.title)
+	}
+	for _, w := range strings.Fields(e.title) {
+		if utf8.RuneCountInString(w) >= 4 {
+			keys = append(keys, "t:"+w)
+		}
+	}
+	for _, w := range strings.Fields(e.author) {
+		if utf8.RuneCountInString(w) >= 4 {
+			keys = append(keys, "a:"+w)
+		}
+	}
+	return keys
+}
+
+// compareBooks — сходство пары; книги в паре идут в порядке добавления в каталог
+func compareBooks(a, b dupEntry) dto.DuplicateCandidate {
+	titleScore := textsim.Similarity(a.title, b.title)
+	authorScore := 0.5
+	if a.author != "" && b.author != "" {
+		authorScore = textsim.Similarity(a.author, b.author)
+	}
+	yearScore := 0.0
+	switch {
+	case a.book.Year == b.book.Year:
+		yearScore = 1
+	case a.book.Year == 0 || b.book.Year == 0:
+		yearScore = 0.5
+	}
+
+	score := duplicateTitleWeight*titleScore + duplicateAuthorWeight*authorScore + duplicateYearWeight*yearScore
+	if a.book.ISBN13 != "" && b.book.ISBN13 != "" && a.book.ISBN13 != b.book.ISBN13 {
+		score *= duplicateISBNPenalty
+	}
+	return dto.DuplicateCandidate{
+		Score:       round3(score),
+		TitleScore:  round3(titleScore),
+		AuthorScore: round3(authorScore),
+		SameYear:    a.book.Year == b.book.Year,
+		Books:       [2]dto.DuplicateBook{dto.NewDuplicateBook(a.book), dto.NewDuplicateBook(b.book)},
+	}
+}
+
+func round3(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
+
+// MergeBooks вливает дубликат в книгу targetID: все выдачи и экземпляры дубликата переносятся
+// на неё, а дубликат остаётся перенаправлением. Поля оставшейся книги не меняются.
+// Дубликат помечается влитым до переноса: если перенос оборвётся, повторный вызов с тем же
+// targetID его завершит (RelinkBook переносит только то, что ещё осталось на дубликате)
+func (uc *DuplicateUsecase) MergeBooks(ctx context.Context, targetID, duplicateID string) (dto.MergeBooksResponse, error) {
+	if targetID == duplicateID {
+		return dto.MergeBooksResponse{}, customErr.ErrMergeSelf
+	}
+	target, err := uc.getUnmerged(ctx, targetID)
+	if err != nil {
+		return dto.MergeBooksResponse{}, err
+	}
+	if target.Withdrawn != nil {
+		return dto.MergeBooksResponse{}, customErr.ErrBookWithdrawn
+	}
+	if _, err := primitive.ObjectIDFromHex(duplicateID); err != nil {
+		return dto.MergeBooksResponse{}, customErr.ErrInvalidID
+	}
+	duplicate, err := uc.bookRepo.GetByID(ctx, duplicateID)
+	if err != nil {
+		return dto.MergeBooksResponse{}, fmt.Errorf("MergeBooks: %w", err)
+	}
+	// Книга, уже влитая в другую, считается отсутствующей; влитая в target — недоделанное слияние
+	if duplicate.MergedInto != "" && duplicate.MergedInto != target.ID {
+		return dto.MergeBooksResponse{}, customErr.ErrBookNotFound
+	}
+
+	targetObjID, _ := primitive.ObjectIDFromHex(target.ID)
+	duplicateObjID, _ := primitive.ObjectIDFromHex(duplicate.ID)
+	if duplicate.MergedInto == "" {
+		if err := uc.bookRepo.MarkMerged(ctx, duplicateObjID, targetObjID); err != nil {
+			return dto.MergeBooksResponse{}, fmt.Errorf("MergeBooks: %w", err)
+		}
+		merged := *duplicate
+		merged.MergedInto = target.ID
+		uc.audit.Record(ctx, domain.AuditBookMerge, domain.AuditEntityBook, duplicate.ID, *duplicate, merged)
+	}
+
+	borrows, err := uc.borrowRepo.RelinkBook(ctx, duplicateObjID, targetObjID)
+	if err != nil {
+		return dto.MergeBooksResponse{}, fmt.Errorf("MergeBooks: relink borrows: %w", err)
+	}
+	items, err := uc.itemRepo.RelinkBook(ctx, duplicateObjID, targetObjID)
+	if err != nil {
+		return dto.MergeBooksResponse{}, fmt.Errorf("MergeBooks: relink items: %w", err)
+	}
+
+	book, err := uc.books.GetBookByID(ctx, target.ID)
+	if err != nil {
+		return dto.MergeBooksResponse{}, fmt.Errorf("MergeBooks: %w", err)
+	}
+	return dto.MergeBooksResponse{Book: book, BorrowsRelinked: borrows, ItemsRelinked: items}, nil
+}
+
+// getUnmerged — книга для слияния; уже влитая книга считается отсутствующей
+func (uc *DuplicateUsecase) getUnmerged(ctx context.Context, id string) (*domain.Book, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	book, err := uc.bookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("MergeBooks: %w", err)
+	}
+	if book.MergedInto != "" {
+		return nil, customErr.ErrBookNotFound
+	}
+	return book, nil
+}
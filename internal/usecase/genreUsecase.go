@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/textsim"
+	"library-Mongo/internal/usecase/dto"
+	"slices"
+	"strings"
+	"time"
+)
+
+type GenreUsecase struct {
+	genreRepo repo.GenreRepository
+	bookRepo  repo.BookRepository
+	audit     AuditRecorder
+}
+
+func NewGenreUsecase(genreRepo repo.GenreRepository, bookRepo repo.BookRepository, audit AuditRecorder) *GenreUsecase {
+	return &GenreUsecase{genreRepo: genreRepo, bookRepo: bookRepo, audit: audit}
+}
+
+func (uc *GenreUsecase) CreateGenre(ctx context.Context, input dto.CreateGenreInput) (dto.GenreResponse, error) {
+	labelRU := strings.TrimSpace(input.LabelRU)
+	if textsim.Key(labelRU) == "" {
+		return dto.GenreResponse{}, customErr.ErrGenreLabelRequired
+	}
+
+	now := time.Now()
+	genre := domain.Genre{
+		Ancestors: []string{},
+		LabelRU:   labelRU,
+		LabelEN:   strings.TrimSpace(input.LabelEN),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if input.ParentID != "" {
+		parent, err := loadGenre(ctx, uc.genreRepo, input.ParentID)
+		if err != nil {
+			return dto.GenreResponse{}, err
+		}
+		genre.ParentID = parent.ID
+		genre.Ancestors = parent.Path()
+	}
+	genre.Synonyms = cleanSynonyms(genre, input.Synonyms)
+	genre.Keys = domain.GenreKeys(genre)
+
+	if err := uc.genreRepo.Create(ctx, &genre); err != nil {
+		return dto.GenreResponse{}, fmt.Errorf("CreateGenre: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditGenreCreate, domain.AuditEntityGenre, genre.ID, nil, genre)
+
+	return dto.NewGenreResponse(genre), nil
+}
+
+// UpdateGenre меняет жанр; новое русское название переписывается во всех книгах жанра,
+// новый родитель переносит жанр вместе с поджанрами
+func (uc *GenreUsecase) UpdateGenre(ctx context.Context, input dto.UpdateGenreInput) error {
+	genre, err := loadGenre(ctx, uc.genreRepo, input.ID)
+	if err != nil {
+		return err
+	}
+	before := *genre
+
+	if input.LabelRU != nil {
+		genre.LabelRU = strings.TrimSpace(*input.LabelRU)
+		if textsim.Key(genre.LabelRU) == "" {
+			return customErr.ErrGenreLabelRequired
+		}
+	}
+	if input.LabelEN != nil {
+		genre.LabelEN = strings.TrimSpace(*input.LabelEN)
+	}
+	if input.Synonyms != nil {
+		genre.Synonyms = *input.Synonyms
+	} else if genre.LabelRU != before.LabelRU {
+		// Прежнее название остаётся синонимом: книги и запросы со старым написанием находят жанр
+		genre.Synonyms = append(genre.Synonyms, before.LabelRU)
+	}
+	genre.Synonyms = cleanSynonyms(*genre, genre.Synonyms)
+	if input.ParentID != nil && *input.ParentID != genre.ParentID {
+		genre.ParentID, genre.Ancestors = "", []string{}
+		if *input.ParentID != "" {
+			parent, err := loadGenre(ctx, uc.genreRepo, *input.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.ID == genre.ID || slices.Contains(parent.Ancestors, genre.ID) {
+				return customErr.ErrGenreCycle
+			}
+			genre.ParentID = parent.ID
+			genre.Ancestors = parent.Path()
+		}
+	}
+	genre.Keys = domain.GenreKeys(*genre)
+	now := time.Now()
+	genre.UpdatedAt = now
+
+	if err := uc.genreRepo.Update(ctx, genre); err != nil {
+		return fmt.Errorf("UpdateGenre: %w", err)
+	}
+	if genre.ParentID != before.ParentID {
+		if _, err := uc.genreRepo.Rebase(ctx, genre.ID, genre.Path(), now); err != nil {
+			return fmt.Errorf("UpdateGenre: move subgenres: %w", err)
+		}
+	}
+	if genre.LabelRU != before.LabelRU {
+		if _, err := uc.bookRepo.RelinkGenre(ctx, genre.ID, genre.ID, genre.LabelRU); err != nil {
+			return fmt.Errorf("UpdateGenre: rename in books: %w", err)
+		}
+	}
+	uc.audit.Record(ctx, domain.AuditGenreUpdate, domain.AuditEntityGenre, genre.ID, before, *genre)
+	return nil
+}
+
+// DeleteGenre удаляет жанр без поджанров, на который не ссылается ни одна книга (и списанная тоже)
+func (uc *GenreUsecase) DeleteGenre(ctx context.Context, id string) error {
+	genre, err := loadGenre(ctx, uc.genreRepo, id)
+	if err != nil {
+		return err
+	}
+	children, err := uc.genreRepo.CountChildren(ctx, genre.ID)
+	if err != nil {
+		return fmt.Errorf("DeleteGenre: %w", err)
+	}
+	if children > 0 {
+		return customErr.ErrGenreHasChildren
+	}
+	books, err := uc.bookRepo.CountInGenre(ctx, genre.ID)
+	if err != nil {
+		return fmt.Errorf("DeleteGenre: %w", err)
+	}
+	if books > 0 {
+		return customErr.ErrGenreInUse
+	}
+	if err := uc.genreRepo.Delete(ctx, genre.ID); err != nil {
+		return fmt.Errorf("DeleteGenre: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditGenreDelete, domain.AuditEntityGenre, genre.ID, *genre, nil)
+	return nil
+}
+
+func (uc *GenreUsecase) GetGenre(ctx context.Context, id string) (dto.GenreResponse, error) {
+	genre, err := loadGenre(ctx, uc.genreRepo, id)
+	if err != nil {
+		return dto.GenreResponse{}, err
+	}
+	return dto.NewGenreResponse(*genre), nil
+}
+
+// GenreTree собирает рубрикатор в дерево; узлы одного уровня идут по русскому названию
+func (uc *GenreUsecase) GenreTree(ctx context.Context) (dto.GenreTree, error) {
+	genres, err := uc.genreRepo.All(ctx)
+	if err != nil {
+		return dto.GenreTree{}, fmt.Errorf("GenreTree: %w", err)
+	}
+	counts, err := uc.bookRepo.CountByGenre(ctx)
+	if err != nil {
+		return dto.GenreTree{}, fmt.Errorf("GenreTree: %w", err)
+	}
+
+	known := make(map[string]bool, len(genres))
+	for _, g := range genres {
+		known[g.ID] = true
+	}
+	children := map[string][]domain.Genre{}
+	for _, g := range genres {
+		// Жанр с пропавшим родителем показывается в корне, а не теряется
+		parent := g.ParentID
+		if !known[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], g)
+	}
+
+	var build func(parent string) []dto.GenreNode
+	build = func(parent string) []dto.GenreNode {
+		nodes := []dto.GenreNode{}
+		for _, g := range children[parent] {
+			node := dto.GenreNode{
+				ID:       g.ID,
+				LabelRU:  g.LabelRU,
+				LabelEN:  g.LabelEN,
+				Synonyms: g.Synonyms,
+				Books:    counts[g.ID],
+				Children: build(g.ID),
+			}
+			node.TotalBooks = node.Books
+			for _, child := range node.Children {
+				node.TotalBooks += child.TotalBooks
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+
+	tree := dto.GenreTree{Genres: build(""), Unclassified: counts[""]}
+	// Ссылки на удалённые в обход API жанры тоже считаются книгами вне рубрикатора
+	for id, n := range counts {
+		if id != "" && !known[id] {
+			tree.Unclassified += n
+		}
+	}
+	return tree, nil
+}
+
+// MergeGenres вливает дубликат в жанр targetID: названия и синонимы дубликата становятся синонимами,
+// книги перепривязываются, поджанры переносятся под target, а сам дубликат удаляется
+func (uc *GenreUsecase) MergeGenres(ctx context.Context, targetID, duplicateID string) (dto.MergeGenresResponse, error) {
+	if targetID == duplicateID {
+		return dto.MergeGenresResponse{}, customErr.ErrMergeSelf
+	}
+	target, err := loadGenre(ctx, uc.genreRepo, targetID)
+	if err != nil {
+		return dto.MergeGenresResponse{}, err
+	}
+	duplicate, err := loadGenre(ctx, uc.genreRepo, duplicateID)
+	if err != nil {
+		return dto.MergeGenresResponse{}, err
+	}
+	// Поджанры дубликата переезжают под target: сам target среди них оказаться не может
+	if slices.Contains(target.Ancestors, duplicate.ID) {
+		return dto.MergeGenresResponse{}, customErr.ErrGenreCycle
+	}
+	before := *target
+
+	synonyms := append(append(target.Synonyms, duplicate.LabelRU, duplicate.LabelEN), duplicate.Synonyms...)
+	target.Synonyms = cleanSynonyms(*target, synonyms)
+	target.Keys = domain.GenreKeys(*target)
+	now := time.Now()
+	target.UpdatedAt = now
+
+	// Написание дубликата может совпасть с чужим жанром (ключи сравниваются без учёта регистра
+	// и ё/е). Проверка — до записей: иначе ошибка Update пришлась бы на уже удалённый дубликат
+	for _, label := range append([]string{target.LabelRU, target.LabelEN}, target.Synonyms...) {
+		other, err := uc.genreRepo.FindByLabel(ctx, label)
+		if err != nil {
+			return dto.MergeGenresResponse{}, fmt.Errorf("MergeGenres: %w", err)
+		}
+		if other != nil && other.ID != target.ID && other.ID != duplicate.ID {
+			return dto.MergeGenresResponse{}, customErr.ErrGenreLabelTaken
+		}
+	}
+
+	books, err := uc.bookRepo.RelinkGenre(ctx, duplicate.ID, target.ID, target.LabelRU)
+	if err != nil {
+		return dto.MergeGenresResponse{}, fmt.Errorf("MergeGenres: relink books: %w", err)
+	}
+	moved, err := uc.genreRepo.Rebase(ctx, duplicate.ID, target.Path(), now)
+	if err != nil {
+		return dto.MergeGenresResponse{}, fmt.Errorf("MergeGenres: move subgenres: %w", err)
+	}
+	// Ключи уникальны: написания дубликата освобождаются до того, как их получит target
+	if err := uc.genreRepo.Delete(ctx, duplicate.ID); err != nil {
+		return dto.MergeGenresResponse{}, fmt.Errorf("MergeGenres: %w", err)
+	}
+	if err := uc.genreRepo.Update(ctx, target); err != nil {
+		return dto.MergeGenresResponse{}, fmt.Errorf("MergeGenres: %w", err)
+	}
+
+	uc.audit.Record(ctx, domain.AuditGenreMerge, domain.AuditEntityGenre, duplicate.ID, *duplicate, nil)
+	uc.audit.Record(ctx, domain.AuditGenreUpdate, domain.AuditEntityGenre, target.ID, before, *target)
+
+	return dto.MergeGenresResponse{Genre: dto.NewGenreResponse(*target), BooksUpdated: books, GenresMoved: moved}, nil
+}
+
+// loadGenre — жанр рубрикатора по ID; ErrGenreNotFound, если его нет
+func loadGenre(ctx context.Context, genreRepo repo.GenreRepository, id string) (*domain.Genre, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	genre, err := genreRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loadGenre: %w", err)
+	}
+	if genre == nil {
+		return nil, customErr.ErrGenreNotFound
+	}
+	return genre, nil
+}
+
+// cleanSynonyms убирает пустые написания, повторы и совпадающие с названиями жанра
+func cleanSynonyms(g domain.Genre, synonyms []string) []string {
+	seen := map[string]bool{textsim.Key(g.LabelRU): true, textsim.Key(g.LabelEN): true}
+	var res []string
+	for _, s := range synonyms {
+		s = strings.TrimSpace(s)
+		key := textsim.Key(s)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, s)
+	}
+	return res
+}
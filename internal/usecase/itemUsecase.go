@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"strings"
+	"time"
+)
+
+type ItemUsecase struct {
+	itemRepo repo.ItemRepository
+	bookRepo repo.BookRepository
+	audit    AuditRecorder
+}
+
+func NewItemUsecase(itemRepo repo.ItemRepository, bookRepo repo.BookRepository, audit AuditRecorder) *ItemUsecase {
+	return &ItemUsecase{itemRepo: itemRepo, bookRepo: bookRepo, audit: audit}
+}
+
+func (uc *ItemUsecase) CreateItem(ctx context.Context, input dto.CreateItemInput) (dto.ItemResponse, error) {
+	book, err := loadBook(ctx, uc.bookRepo, input.BookID)
+	if err != nil {
+		return dto.ItemResponse{}, fmt.Errorf("CreateItem: %w", err)
+	}
+	bookObjID, _ := primitive.ObjectIDFromHex(book.ID)
+
+	barcode := strings.TrimSpace(input.Barcode)
+	if barcode == "" {
+		return dto.ItemResponse{}, customErr.ErrBarcodeRequired
+	}
+	status := input.Status
+	if status == "" {
+		status = domain.ItemAvailable
+	}
+	// on_loan ставит и снимает только выдача: без неё экземпляр считался бы выданным никому
+	if status == domain.ItemOnLoan || !domain.IsValidItemStatus(status) {
+		return dto.ItemResponse{}, customErr.ErrInvalidItemStatus
+	}
+	acquiredAt := time.Now()
+	if input.AcquiredAt != nil {
+		acquiredAt = *input.AcquiredAt
+	}
+
+	item := domain.Item{
+		BookID:     bookObjID,
+		Barcode:    barcode,
+		Status:     status,
+		Location:   input.Location,
+		AcquiredAt: acquiredAt,
+	}
+	if err := uc.itemRepo.Create(ctx, &item); err != nil {
+		return dto.ItemResponse{}, fmt.Errorf("CreateItem: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditItemCreate, domain.AuditEntityItem, item.ID, nil, item)
+
+	return dto.NewItemResponse(item), nil
+}
+
+// UpdateItem меняет данные экземпляра; статус выданного экземпляра меняется только возвратом
+// (кроме пометки "утерян"). Все проверки — до записи: изменение проходит целиком или не проходит
+func (uc *ItemUsecase) UpdateItem(ctx context.Context, input dto.UpdateItemInput) error {
+	item, err := uc.getItem(ctx, input.ID)
+	if err != nil {
+		return err
+	}
+	before := *item
+
+	if input.Status != nil && *input.Status != item.Status {
+		status := *input.Status
+		if status == domain.ItemOnLoan || !domain.IsValidItemStatus(status) {
+			return customErr.ErrInvalidItemStatus
+		}
+		if item.Status == domain.ItemOnLoan && status != domain.ItemLost {
+			return customErr.ErrItemOnLoan
+		}
+		item.Status = status
+	}
+	if input.Barcode != nil {
+		barcode := strings.TrimSpace(*input.Barcode)
+		if barcode == "" {
+			return customErr.ErrBarcodeRequired
+		}
+		item.Barcode = barcode
+	}
+	if input.Location != nil {
+		item.Location = *input.Location
+	}
+	if input.AcquiredAt != nil {
+		item.AcquiredAt = *input.AcquiredAt
+	}
+	ok, err := uc.itemRepo.Update(ctx, item, before.Status)
+	if err != nil {
+		return fmt.Errorf("UpdateItem: %w", err)
+	}
+	if !ok {
+		// Статус поменялся параллельно (например, экземпляр только что выдали)
+		return customErr.ErrItemUnavailable
+	}
+
+	uc.audit.Record(ctx, domain.AuditItemUpdate, domain.AuditEntityItem, item.ID, before, *item)
+	return nil
+}
+
+func (uc *ItemUsecase) DeleteItem(ctx context.Context, id string) error {
+	item, err := uc.getItem(ctx, id)
+	if err != nil {
+		return err
+	}
+	if item.Status == domain.ItemOnLoan {
+		return customErr.ErrItemOnLoan
+	}
+	if err := uc.itemRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("DeleteItem: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditItemDelete, domain.AuditEntityItem, id, *item, nil)
+	return nil
+}
+
+func (uc *ItemUsecase) GetItem(ctx context.Context, id string) (dto.ItemResponse, error) {
+	item, err := uc.getItem(ctx, id)
+	if err != nil {
+		return dto.ItemResponse{}, err
+	}
+	return dto.NewItemResponse(*item), nil
+}
+
+func (uc *ItemUsecase) GetItemByBarcode(ctx context.Context, barcode string) (dto.ItemResponse, error) {
+	item, err := uc.itemRepo.GetByBarcode(ctx, barcode)
+	if err != nil {
+		return dto.ItemResponse{}, fmt.Errorf("GetItemByBarcode: %w", err)
+	}
+	if item == nil {
+		return dto.ItemResponse{}, customErr.ErrItemNotFound
+	}
+	return dto.NewItemResponse(*item), nil
+}
+
+// ListItems — экземпляры книги; ID влитого дубликата открывает экземпляры оставшейся книги
+func (uc *ItemUsecase) ListItems(ctx context.Context, bookID string) ([]dto.ItemResponse, error) {
+	book, err := loadBook(ctx, uc.bookRepo, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("ListItems: %w", err)
+	}
+	bookObjID, _ := primitive.ObjectIDFromHex(book.ID)
+	items, err := uc.itemRepo.ListByBook(ctx, bookObjID)
+	if err != nil {
+		return nil, fmt.Errorf("ListItems: %w", err)
+	}
+	return dto.NewItemResponses(items), nil
+}
+
+func (uc *ItemUsecase) getItem(ctx context.Context, id string) (*domain.Item, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	item, err := uc.itemRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getItem: %w", err)
+	}
+	if item == nil {
+		return nil, customErr.ErrItemNotFound
+	}
+	return item, nil
+}
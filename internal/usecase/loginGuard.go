@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"log"
+	"time"
+)
+
+// LoginGuardPolicy — пороги и длительности защиты от перебора паролей
+type LoginGuardPolicy struct {
+	MaxFailures   int           // неудач подряд по телефону до блокировки
+	MaxFailuresIP int           // неудач подряд с одного IP до блокировки
+	Window        time.Duration // время жизни счётчика после последней неудачи
+	LockBase      time.Duration // первая блокировка
+	LockMax       time.Duration // потолок экспоненциального роста
+}
+
+// LoginGuard считает неудачные входы по телефону и IP в Mongo (общие для всех
+// экземпляров сервиса) и временно блокирует вход с экспоненциальным ростом паузы
+type LoginGuard struct {
+	attemptRepo repo.LoginAttemptRepository
+	policy      LoginGuardPolicy
+}
+
+func NewLoginGuard(attemptRepo repo.LoginAttemptRepository, policy LoginGuardPolicy) *LoginGuard {
+	return &LoginGuard{attemptRepo: attemptRepo, policy: policy}
+}
+
+type guardCounter struct {
+	kind  string
+	value string
+	max   int
+}
+
+func (g *LoginGuard) counters(phone, ip string) []guardCounter {
+	var res []guardCounter
+	if phone != "" {
+		res = append(res, guardCounter{kind: "phone", value: phone, max: g.policy.MaxFailures})
+	}
+	if ip != "" {
+		res = append(res, guardCounter{kind: "ip", value: ip, max: g.policy.MaxFailuresIP})
+	}
+	return res
+}
+
+// Check возвращает *customErr.LockoutError, если телефон или IP сейчас заблокированы
+func (g *LoginGuard) Check(ctx context.Context, phone, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, c := range g.counters(phone, ip) {
+		a, err := g.attemptRepo.Get(ctx, c.kind+":"+c.value)
+		if err != nil {
+			return fmt.Errorf("LoginGuard.Check: %w", err)
+		}
+		if a != nil && a.LockedUntil != nil && now.Before(*a.LockedUntil) {
+			retryAfter = max(retryAfter, a.LockedUntil.Sub(now))
+		}
+	}
+	if retryAfter > 0 {
+		return &customErr.LockoutError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Failure учитывает неудачную попытку; если порог достигнут — блокирует вход
+// и возвращает *customErr.LockoutError
+func (g *LoginGuard) Failure(ctx context.Context, phone, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, c := range g.counters(phone, ip) {
+		key := c.kind + ":" + c.value
+		a, err := g.attemptRepo.RegisterFailure(ctx, key, now, now.Add(g.policy.Window))
+		if err != nil {
+			return fmt.Errorf("LoginGuard.Failure: %w", err)
+		}
+		if a.Failures < c.max {
+			continue
+		}
+
+		lock := g.lockDuration(a.Failures - c.max)
+		until := now.Add(lock)
+		if err := g.attemptRepo.Lock(ctx, key, until, until.Add(g.policy.Window)); err != nil {
+			return fmt.Errorf("LoginGuard.Failure: %w", err)
+		}
+
+		event := domain.LockoutEvent{
+			Kind:        c.kind,
+			Value:       c.value,
+			Failures:    a.Failures,
+			IP:          ip,
+			LockedUntil: until,
+			CreatedAt:   now,
+		}
+		if err := g.attemptRepo.CreateEvent(ctx, &event); err != nil {
+			log.Printf("LoginGuard.Failure: save lockout event: %v", err)
+		}
+		log.Printf("LoginGuard: %s %s locked for %s after %d failures", c.kind, c.value, lock, a.Failures)
+
+		retryAfter = max(retryAfter, lock)
+	}
+	if retryAfter > 0 {
+		return &customErr.LockoutError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Success сбрасывает счётчик телефона; счётчик IP живёт до истечения окна
+func (g *LoginGuard) Success(ctx context.Context, phone string) error {
+	if err := g.attemptRepo.Reset(ctx, "phone:"+phone); err != nil {
+		return fmt.Errorf("LoginGuard.Success: %w", err)
+	}
+	return nil
+}
+
+// lockDuration — LockBase * 2^extra, но не больше LockMax
+func (g *LoginGuard) lockDuration(extra int) time.Duration {
+	d := g.policy.LockBase
+	for i := 0; i < extra && d < g.policy.LockMax; i++ {
+		d *= 2
+	}
+	return min(d, g.policy.LockMax)
+}
+
+func (g *LoginGuard) ListLockoutEvents(ctx context.Context, filter domain.LockoutEventFilter) ([]domain.LockoutEvent, error) {
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	events, err := g.attemptRepo.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ListLockoutEvents: %w", err)
+	}
+	return events, nil
+}
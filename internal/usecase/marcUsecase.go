@@ -0,0 +1,447 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/isbn"
+	"library-Mongo/internal/marc"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MARCUsecase — обмен записями с другими библиотеками в MARC21.
+// Книги и авторы заводятся через BookUC и AuthorUC, поэтому импорт попадает в журнал аудита.
+type MARCUsecase struct {
+	bookRepo   repo.BookRepository
+	authorRepo repo.AuthorRepository
+	books      BookUC
+	authors    AuthorUC
+}
+
+func NewMARCUsecase(bookRepo repo.BookRepository, authorRepo repo.AuthorRepository, books BookUC, authors AuthorUC) *MARCUsecase {
+	return &MARCUsecase{bookRepo: bookRepo, authorRepo: authorRepo, books: books, authors: authors}
+}
+
+// ImportMARC заводит книги из потока ISO 2709 или MARCXML (format пустой — определить по содержимому).
+// Дубликаты — по ISBN или по названию и автору, в каталоге и внутри файла — пропускаются.
+// При dryRun каталог и справочник авторов не меняются, отчёт показывает, что было бы сделано.
+func (uc *MARCUsecase) ImportMARC(ctx context.Context, r io.Reader, format string, dryRun bool) (dto.MARCImportReport, error) {
+	reader, err := marc.NewReader(r, format)
+	if err != nil {
+		return dto.MARCImportReport{}, fmt.Errorf("%w: %v", customErr.ErrInvalidMARC, err)
+	}
+
+	report := dto.MARCImportReport{DryRun: dryRun, Records: []dto.MARCImportRecord{}}
+	batch := newImportBatch()
+	for index := 1; ; index++ {
+		rec, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// Поток, в котором не прочитать ни одной записи, — не MARC
+			if errors.Is(err, marc.ErrBadStream) && report.Total == 0 {
+				return report, fmt.Errorf("%w: %w", customErr.ErrInvalidMARC, err)
+			}
+			report.Add(dto.MARCImportRecord{Index: index, Status: dto.ImportInvalid, Error: err.Error()})
+			if errors.Is(err, marc.ErrBadStream) {
+				break
+			}
+			continue
+		}
+		report.Add(uc.importRecord(ctx, index, rec, dryRun, batch))
+	}
+	return report, nil
+}
+
+func (uc *MARCUsecase) importRecord(ctx context.Context, index int, rec *marc.Record, dryRun bool, batch *importBatch) dto.MARCImportRecord {
+	book := bookFromRecord(rec)
+	res := dto.MARCImportRecord{
+		Index:         index,
+		ControlNumber: rec.ControlValue("001"),
+		Title:         book.Title,
+		Author:        book.author(),
+		ISBN:          book.ISBN,
+		Warnings:      book.Warnings,
+	}
+	failed := func(err error) dto.MARCImportRecord {
+		res.Status, res.Error = dto.ImportFailed, err.Error()
+		return res
+	}
+
+	if err := book.validate(); err != nil {
+		res.Status, res.Error = dto.ImportInvalid, err.Error()
+		return res
+	}
+	if first := batch.duplicateOf(book); first != 0 {
+		res.Status, res.DuplicateOfNo = dto.ImportDuplicate, first
+		return res
+	}
+	batch.add(index, book)
+
+	// Имена сверяются со справочником по всем написаниям
+	known := make([]*domain.Author, len(book.Names))
+	for i, n := range book.Names {
+		author, err := uc.authorRepo.FindByName(ctx, n.Name)
+		if err != nil {
+			return failed(err)
+		}
+		known[i] = author
+	}
+
+	dupID, err := uc.findDuplicate(ctx, book, known)
+	if err != nil {
+		return failed(err)
+	}
+	if dupID != "" {
+		res.Status, res.DuplicateOf = dto.ImportDuplicate, dupID
+		return res
+	}
+
+	contributors := make([]dto.ContributorInput, 0, len(book.Names))
+	for i, n := range book.Names {
+		if known[i] != nil {
+			contributors = append(contributors, dto.ContributorInput{AuthorID: known[i].ID, Role: n.Role})
+			continue
+		}
+		res.NewAuthors = append(res.NewAuthors, n.Name)
+		if dryRun {
+			continue
+		}
+		// Имя могло появиться в справочнике при импорте предыдущей записи
+		author, err := uc.authorRepo.FindByName(ctx, n.Name)
+		if err != nil {
+			return failed(err)
+		}
+		authorID := ""
+		if author != nil {
+			authorID = author.ID
+		} else {
+			created, err := uc.authors.CreateAuthor(ctx, dto.CreateAuthorInput{Name: n.Name, BirthYear: n.BirthYear, DeathYear: n.DeathYear})
+			if err != nil {
+				return failed(err)
+			}
+			authorID = created.ID
+		}
+		contributors = append(contributors, dto.ContributorInput{AuthorID: authorID, Role: n.Role})
+	}
+	if dryRun {
+		res.Status = dto.ImportReady
+		return res
+	}
+
+	created, err := uc.books.CreateBook(ctx, dto.CreateBookInput{
+		Title:        book.Title,
+		Year:         book.Year,
+		Genre:        book.Genre,
+		ISBN:         book.ISBN,
+		Contributors: contributors,
+	})
+	if err != nil {
+		if errors.Is(err, customErr.ErrISBNTaken) {
+			res.Status = dto.ImportDuplicate
+			return res
+		}
+		return failed(err)
+	}
+	res.Status, res.BookID, res.Author = dto.ImportCreated, created.ID, created.Author
+	return res
+}
+
+// findDuplicate — ID книги каталога с тем же ISBN либо с тем же названием и автором.
+// Книги с разными ISBN считаются разными изданиями.
+func (uc *MARCUsecase) findDuplicate(ctx context.Context, book marcBook, known []*domain.Author) (string, error) {
+	if book.ISBN != "" {
+		existing, err := uc.bookRepo.GetByISBN(ctx, book.ISBN)
+		if err == nil {
+			return existing.ID, nil
+		}
+		if !errors.Is(err, customErr.ErrBookNotFound) {
+			return "", err
+		}
+	}
+
+	candidates, err := uc.bookRepo.FindByTitle(ctx, book.Title)
+	if err != nil {
+		return "", err
+	}
+	authorKey := domain.NameKey(book.author())
+	for _, c := range candidates {
+		if book.ISBN != "" && c.ISBN13 != "" {
+			continue
+		}
+		if domain.NameKey(c.Author) == authorKey {
+			return c.ID, nil
+		}
+		for i, n := range book.Names {
+			if n.Role != domain.RoleAuthor || known[i] == nil {
+				continue
+			}
+			for _, cc := range c.Contributors {
+				if cc.AuthorID.Hex() == known[i].ID {
+					return c.ID, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// ExportMARC пишет книги под фильтром в w как MARCXML и возвращает их число.
+// До первой книги в w ничего не пишется, так что ошибку фильтра можно вернуть обычным ответом.
+func (uc *MARCUsecase) ExportMARC(ctx context.Context, filter domain.BookFilter, w io.Writer) (int, error) {
+	filter.ISBN = isbnQuery(filter.ISBN)
+
+	xw := marc.NewXMLWriter(w)
+	count := 0
+	err := uc.bookRepo.Each(ctx, filter, func(b domain.Book) error {
+		count++
+		return xw.Write(recordFromBook(b))
+	})
+	if err != nil {
+		return count, fmt.Errorf("ExportMARC: %w", err)
+	}
+	if err := xw.Close(); err != nil {
+		return count, fmt.Errorf("ExportMARC: %w", err)
+	}
+	return count, nil
+}
+
+// marcBook — поля книги, извлечённые из записи MARC
+type marcBook struct {
+	Title    string
+	Year     int
+	Genre    string
+	ISBN     string // ISBN-13; пусто, если в 020 нет корректного
+	Names    []marcName
+	Warnings []string
+}
+
+type marcName struct {
+	Name      string
+	Role      string
+	BirthYear int
+	DeathYear int
+}
+
+func (b marcBook) author() string {
+	var names []string
+	for _, n := range b.Names {
+		if n.Role == domain.RoleAuthor {
+			names = append(names, n.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (b marcBook) validate() error {
+	switch {
+	case b.Title == "":
+		return errors.New("missing title (245 $a)")
+	case b.author() == "":
+		return errors.New("missing author (100 $a)")
+	case b.Genre == "":
+		return errors.New("missing genre (650 $a)")
+	}
+	return nil
+}
+
+// relatorRoles — коды ($4) и термины ($e) отношения из полей 700 в роли участников
+var relatorRoles = map[string]string{
+	"aut": domain.RoleAuthor, "author": domain.RoleAuthor, "автор": domain.RoleAuthor,
+	"trl": domain.RoleTranslator, "translator": domain.RoleTranslator, "переводчик": domain.RoleTranslator, "пер": domain.RoleTranslator,
+	"ill": domain.RoleIllustrator, "illustrator": domain.RoleIllustrator, "иллюстратор": domain.RoleIllustrator,
+	"художник": domain.RoleIllustrator, "ил": domain.RoleIllustrator,
+	"edt": domain.RoleEditor, "editor": domain.RoleEditor, "редактор": domain.RoleEditor, "ред": domain.RoleEditor,
+}
+
+// lifeDates — годы жизни в $d: "1828-1910", "1947-"
+var lifeDates = regexp.MustCompile(`(\d{4})\s*-\s*(\d{4})?`)
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// bookFromRecord переносит в книгу поля 020 (ISBN), 100/700 (участники), 245 (название),
+// 260/264 (год издания) и 650/655 (жанр)
+func bookFromRecord(rec *marc.Record) marcBook {
+	var b marcBook
+
+	for _, f := range rec.Fields("020") {
+		for _, v := range f.Values('a') {
+			// В $a после номера бывает уточнение: "978-5-389-06256-6 (в пер.)"
+			raw := strings.Fields(v)
+			if len(raw) == 0 {
+				continue
+			}
+			isbn13, err := isbn.Normalize(raw[0])
+			if err != nil {
+				b.Warnings = append(b.Warnings, fmt.Sprintf("020: invalid ISBN %q ignored", v))
+				continue
+			}
+			if b.ISBN == "" {
+				b.ISBN = isbn13
+			}
+		}
+	}
+
+	for _, f := range rec.Fields("100") {
+		if n, ok := nameFromField(f, domain.RoleAuthor); ok {
+			b.Names = append(b.Names, n)
+		}
+	}
+	for _, f := range rec.Fields("700") {
+		role, ok := relatorRole(f)
+		if !ok {
+			b.Warnings = append(b.Warnings, fmt.Sprintf("700: %q with unsupported role ignored", f.Value('a')))
+			continue
+		}
+		if n, ok := nameFromField(f, role); ok {
+			b.Names = append(b.Names, n)
+		}
+	}
+
+	if fields := rec.Fields("245"); len(fields) > 0 {
+		b.Title = marc.TrimPunct(fields[0].Value('a'))
+	}
+
+	// 264 — в записях по RDA вместо 260
+	for _, tag := range []string{"260", "264"} {
+		for _, f := range rec.Fields(tag) {
+			if y := yearPattern.FindString(f.Value('c')); y != "" && b.Year == 0 {
+				b.Year, _ = strconv.Atoi(y)
+			}
+		}
+	}
+
+	// Тематическая рубрика (650), при её отсутствии — жанр/форма (655)
+	for _, tag := range []string{"650", "655"} {
+		for _, f := range rec.Fields(tag) {
+			if v := marc.TrimPunct(f.Value('a')); v != "" && b.Genre == "" {
+				b.Genre = v
+			}
+		}
+	}
+	return b
+}
+
+func nameFromField(f marc.DataField, role string) (marcName, bool) {
+	name := marc.TrimPunct(f.Value('a'))
+	if domain.NameKey(name) == "" {
+		return marcName{}, false
+	}
+	n := marcName{Name: name, Role: role}
+	if m := lifeDates.FindStringSubmatch(f.Value('d')); m != nil {
+		n.BirthYear, _ = strconv.Atoi(m[1])
+		n.DeathYear, _ = strconv.Atoi(m[2])
+	}
+	return n, true
+}
+
+// relatorRole — роль из $4 или $e; без них участник 700 считается соавтором
+func relatorRole(f marc.DataField) (string, bool) {
+	terms := append(f.Values('4'), f.Values('e')...)
+	if len(terms) == 0 {
+		return domain.RoleAuthor, true
+	}
+	for _, t := range terms {
+		if role, ok := relatorRoles[strings.Trim(strings.ToLower(t), " .,")]; ok {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// recordFromBook — запись MARC21 книги: 001, 020, 100, 245, 260, 650, 700 (в порядке тегов)
+func recordFromBook(b domain.Book) *marc.Record {
+	rec := &marc.Record{Leader: marc.DefaultLeader}
+	rec.AddControl("001", b.ID)
+	rec.AddData("020", ' ', ' ', marc.Subfield{Code: 'a', Value: b.ISBN13})
+	rec.AddData("020", ' ', ' ', marc.Subfield{Code: 'a', Value: b.ISBN10})
+
+	names := b.Contributors
+	if len(names) == 0 && b.Author != "" {
+		names = []domain.Contributor{{Name: b.Author, Role: domain.RoleAuthor}}
+	}
+	var added []marc.DataField
+	hasMain := false
+	for _, c := range names {
+		if !hasMain && c.Role == domain.RoleAuthor {
+			rec.AddData("100", nameIndicator(c.Name), ' ', marc.Subfield{Code: 'a', Value: c.Name})
+			hasMain = true
+			continue
+		}
+		added = append(added, marc.DataField{Tag: "700", Ind1: nameIndicator(c.Name), Ind2: ' ', Subfields: []marc.Subfield{
+			{Code: 'a', Value: c.Name},
+			{Code: 'e', Value: c.Role},
+		}})
+	}
+
+	// Первый индикатор 245: есть ли основная точка доступа (100)
+	titleInd := byte('0')
+	if hasMain {
+		titleInd = '1'
+	}
+	rec.AddData("245", titleInd, '0', marc.Subfield{Code: 'a', Value: b.Title})
+	if b.Year != 0 {
+		rec.AddData("260", ' ', ' ', marc.Subfield{Code: 'c', Value: strconv.Itoa(b.Year)})
+	}
+	rec.AddData("650", ' ', '4', marc.Subfield{Code: 'a', Value: b.Genre})
+	rec.Data = append(rec.Data, added...)
+	return rec
+}
+
+// nameIndicator — первый индикатор имени: 1 — "Фамилия, Имя", 0 — прямой порядок
+func nameIndicator(name string) byte {
+	if strings.Contains(name, ",") {
+		return '1'
+	}
+	return '0'
+}
+
+// importBatch помнит уже прочитанные записи файла для поиска повторов внутри него
+type importBatch struct {
+	isbn  map[string]int
+	title map[string][]batchBook // NameKey названия и автора
+}
+
+type batchBook struct {
+	index int
+	isbn  string
+}
+
+func newImportBatch() *importBatch {
+	return &importBatch{isbn: map[string]int{}, title: map[string][]batchBook{}}
+}
+
+func batchTitleKey(b marcBook) string {
+	return domain.NameKey(b.Title) + "|" + domain.NameKey(b.author())
+}
+
+// duplicateOf — номер более ранней записи с той же книгой; 0 — повтора нет
+func (s *importBatch) duplicateOf(b marcBook) int {
+	if b.ISBN != "" {
+		if index, ok := s.isbn[b.ISBN]; ok {
+			return index
+		}
+	}
+	for _, prev := range s.title[batchTitleKey(b)] {
+		if prev.isbn == "" || b.ISBN == "" {
+			return prev.index
+		}
+	}
+	return 0
+}
+
+func (s *importBatch) add(index int, b marcBook) {
+	if b.ISBN != "" {
+		s.isbn[b.ISBN] = index
+	}
+	key := batchTitleKey(b)
+	s.title[key] = append(s.title[key], batchBook{index: index, isbn: b.ISBN})
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/audit"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+)
+
+// RevisionUsecase — история изменений книги: список ревизий, сравнение и откат.
+// Ревизии пишет BookUsecase при каждом создании и изменении книги; откат проходит через
+// BookUC.UpdateBook с теми же проверками, что и PUT /books, и сам становится новой ревизией
+type RevisionUsecase struct {
+	revisionRepo repo.BookRevisionRepository
+	bookRepo     repo.BookRepository
+	genreRepo    repo.GenreRepository
+	books        BookUC
+}
+
+func NewRevisionUsecase(revisionRepo repo.BookRevisionRepository, bookRepo repo.BookRepository, genreRepo repo.GenreRepository, books BookUC) *RevisionUsecase {
+	return &RevisionUsecase{revisionRepo: revisionRepo, bookRepo: bookRepo, genreRepo: genreRepo, books: books}
+}
+
+func (uc *RevisionUsecase) ListRevisions(ctx context.Context, bookID string, page domain.PageRequest) (domain.Page[dto.BookRevisionResponse], error) {
+	objID, err := uc.bookObjectID(ctx, bookID)
+	if err != nil {
+		return domain.Page[dto.BookRevisionResponse]{}, fmt.Errorf("ListRevisions: %w", err)
+	}
+	revs, err := uc.revisionRepo.ListPage(ctx, objID, page)
+	if err != nil {
+		return domain.Page[dto.BookRevisionResponse]{}, fmt.Errorf("ListRevisions: %w", err)
+	}
+	return domain.Page[dto.BookRevisionResponse]{
+		Items: dto.NewBookRevisionResponses(revs.Items),
+		Total: revs.Total,
+		Next:  revs.Next,
+	}, nil
+}
+
+func (uc *RevisionUsecase) GetRevision(ctx context.Context, bookID string, number int) (dto.BookRevisionResponse, error) {
+	rev, err := uc.revision(ctx, bookID, number)
+	if err != nil {
+		return dto.BookRevisionResponse{}, fmt.Errorf("GetRevision: %w", err)
+	}
+	return dto.NewBookRevisionResponse(*rev), nil
+}
+
+// DiffRevisions — поля, изменившиеся от ревизии from к ревизии to (from может быть и позже to)
+func (uc *RevisionUsecase) DiffRevisions(ctx context.Context, bookID string, from, to int) (dto.RevisionDiff, error) {
+	a, err := uc.revision(ctx, bookID, from)
+	if err != nil {
+		return dto.RevisionDiff{}, fmt.Errorf("DiffRevisions: %w", err)
+	}
+	b, err := uc.revision(ctx, bookID, to)
+	if err != nil {
+		return dto.RevisionDiff{}, fmt.Errorf("DiffRevisions: %w", err)
+	}
+	changes, err := audit.Diff(a.Record, b.Record)
+	if err != nil {
+		return dto.RevisionDiff{}, fmt.Errorf("DiffRevisions: %w", err)
+	}
+	return dto.RevisionDiff{BookID: bookID, From: from, To: to, Changes: changes}, nil
+}
+
+// RevertBook возвращает книге поля ревизии number. Участники привязываются к действующим
+// записям авторов, так что после слияния авторов откат не вернёт ссылку на дубликат.
+// Жанр восстанавливается по ссылке на рубрикатор; если жанр с тех пор удалён (влит в другой),
+// книга классифицируется по названию — оно осталось синонимом жанра, в который его влили
+func (uc *RevisionUsecase) RevertBook(ctx context.Context, bookID string, number int) (dto.BookResponse, error) {
+	rev, err := uc.revision(ctx, bookID, number)
+	if err != nil {
+		return dto.BookResponse{}, fmt.Errorf("RevertBook: %w", err)
+	}
+
+	r := rev.Record
+	contributors := make([]dto.ContributorInput, 0, len(r.Contributors))
+	for _, c := range r.Contributors {
+		contributors = append(contributors, dto.ContributorInput{AuthorID: c.AuthorID.Hex(), Role: c.Role})
+	}
+	seriesID, volume := "", 0
+	if r.Series != nil {
+		seriesID, volume = r.Series.SeriesID, r.Series.Volume
+	}
+	genreID := r.GenreID
+	if genreID != "" {
+		genre, err := uc.genreRepo.GetByID(ctx, genreID)
+		if err != nil {
+			return dto.BookResponse{}, fmt.Errorf("RevertBook: %w", err)
+		}
+		if genre == nil {
+			genreID = ""
+		}
+	}
+	input := dto.UpdateBookInput{
+		ID:           bookID,
+		Title:        &r.Title,
+		Author:       &r.Author,
+		Year:         &r.Year,
+		Genre:        &r.Genre,
+		GenreID:      &genreID,
+		ISBN:         &r.ISBN13,
+		Contributors: &contributors,
+		WorkID:       &r.WorkID,
+		SeriesID:     &seriesID,
+		Volume:       &volume,
+		RevertOf:     number,
+	}
+	if err := uc.books.UpdateBook(ctx, input); err != nil {
+		return dto.BookResponse{}, fmt.Errorf("RevertBook: %w", err)
+	}
+	return uc.books.GetBookByID(ctx, bookID)
+}
+
+func (uc *RevisionUsecase) revision(ctx context.Context, bookID string, number int) (*domain.BookRevision, error) {
+	objID, err := uc.bookObjectID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := uc.revisionRepo.GetByNumber(ctx, objID, number)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, customErr.ErrRevisionNotFound
+	}
+	return rev, nil
+}
+
+// bookObjectID проверяет ID и наличие книги (списанная тоже подходит); ID влитого дубликата
+// заменяется ID книги, в которую его влили
+func (uc *RevisionUsecase) bookObjectID(ctx context.Context, bookID string) (primitive.ObjectID, error) {
+	book, err := loadBook(ctx, uc.bookRepo, bookID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	objID, _ := primitive.ObjectIDFromHex(book.ID)
+	return objID, nil
+}
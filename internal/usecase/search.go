@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"library-Mongo/internal/textsim"
+	"strings"
+	"unicode"
+)
+
+// vocabularyFunc — ключи поиска (textsim.Latin), начинающиеся с first
+type vocabularyFunc func(ctx context.Context, first string) ([]string, error)
+
+// correctKeys подбирает словам text, которых нет в словаре, ближайший ключ словаря не дальше
+// textsim.MaxTypos. Возвращает исправления: ключ слова -> ключ словаря; пусто — исправлять нечего.
+// Словарь берётся по первой букве слова: опечатку в первой букве исправить не получится
+func correctKeys(ctx context.Context, text string, vocab vocabularyFunc) (map[string]string, error) {
+	fixes := map[string]string{}
+	for _, key := range textsim.SearchKeys(text) {
+		typos := textsim.MaxTypos(key)
+		if typos == 0 {
+			continue
+		}
+		runes := []rune(key)
+		words, err := vocab(ctx, string(runes[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		best, bestDist, known := "", typos+1, false
+		for _, w := range words {
+			if strings.HasPrefix(w, key) {
+				known = true
+				break
+			}
+			d := textsim.Distance(key, w)
+			// Слово могли не дописать: "булгоко" ближе к началу "bulgakov", чем к нему целиком
+			if wr := []rune(w); len(wr) > len(runes) {
+				d = min(d, textsim.Distance(key, string(wr[:len(runes)])))
+			}
+			if d < bestDist {
+				best, bestDist = w, d
+			}
+		}
+		if !known && best != "" {
+			fixes[key] = best
+		}
+	}
+	return fixes, nil
+}
+
+// rewriteWords заменяет в text слова с исправлениями на display(исправление); исключения
+// полнотекстового запроса (-слово) не трогаются, кавычки и пунктуация отбрасываются
+func rewriteWords(text string, fixes map[string]string, display func(fix string) string) string {
+	var out []string
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "-") {
+			out = append(out, field)
+			continue
+		}
+		for _, word := range strings.FieldsFunc(field, notWordRune) {
+			if fix, ok := fixes[textsim.Latin(word)]; ok {
+				word = display(fix)
+			}
+			out = append(out, word)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// displayWord — слово из sources с ключом key, как оно написано в каталоге (в нижнем регистре).
+// Ключ — транслитерация, показывать его читателю вместо "Толстой" не стоит
+func displayWord(key string, sources []string) string {
+	for _, s := range sources {
+		for _, word := range strings.FieldsFunc(s, notWordRune) {
+			if textsim.Latin(word) == key {
+				return strings.ToLower(word)
+			}
+		}
+	}
+	return key
+}
+
+func notWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func sameKey(fix string) string {
+	return fix
+}
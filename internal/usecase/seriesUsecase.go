@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/textsim"
+	"library-Mongo/internal/usecase/dto"
+	"strings"
+	"time"
+)
+
+type SeriesUsecase struct {
+	seriesRepo repo.SeriesRepository
+	bookRepo   repo.BookRepository
+	audit      AuditRecorder
+}
+
+func NewSeriesUsecase(seriesRepo repo.SeriesRepository, bookRepo repo.BookRepository, audit AuditRecorder) *SeriesUsecase {
+	return &SeriesUsecase{seriesRepo: seriesRepo, bookRepo: bookRepo, audit: audit}
+}
+
+func (uc *SeriesUsecase) CreateSeries(ctx context.Context, input dto.CreateSeriesInput) (dto.SeriesResponse, error) {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return dto.SeriesResponse{}, customErr.ErrSeriesTitleRequired
+	}
+
+	now := time.Now()
+	series := domain.Series{
+		Title:     title,
+		Keys:      textsim.SearchKeys(title),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.seriesRepo.Create(ctx, &series); err != nil {
+		return dto.SeriesResponse{}, fmt.Errorf("CreateSeries: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditSeriesCreate, domain.AuditEntitySeries, series.ID, nil, series)
+
+	return dto.NewSeriesResponse(series), nil
+}
+
+// UpdateSeries меняет серию; новое название переписывается во всех её книгах
+func (uc *SeriesUsecase) UpdateSeries(ctx context.Context, input dto.UpdateSeriesInput) error {
+	series, err := loadSeries(ctx, uc.seriesRepo, input.ID)
+	if err != nil {
+		return err
+	}
+	before := *series
+
+	if input.Title != nil {
+		series.Title = strings.TrimSpace(*input.Title)
+		if series.Title == "" {
+			return customErr.ErrSeriesTitleRequired
+		}
+		series.Keys = textsim.SearchKeys(series.Title)
+	}
+	series.UpdatedAt = time.Now()
+
+	if err := uc.seriesRepo.Update(ctx, series); err != nil {
+		return fmt.Errorf("UpdateSeries: %w", err)
+	}
+	if series.Title != before.Title {
+		if _, err := uc.bookRepo.RenameSeries(ctx, series.ID, series.Title); err != nil {
+			return fmt.Errorf("UpdateSeries: rename in books: %w", err)
+		}
+	}
+	uc.audit.Record(ctx, domain.AuditSeriesUpdate, domain.AuditEntitySeries, series.ID, before, *series)
+	return nil
+}
+
+// DeleteSeries удаляет серию, в которой не осталось книг (и списанных тоже)
+func (uc *SeriesUsecase) DeleteSeries(ctx context.Context, id string) error {
+	series, err := loadSeries(ctx, uc.seriesRepo, id)
+	if err != nil {
+		return err
+	}
+	books, err := uc.bookRepo.CountInSeries(ctx, series.ID)
+	if err != nil {
+		return fmt.Errorf("DeleteSeries: %w", err)
+	}
+	if books > 0 {
+		return customErr.ErrSeriesInUse
+	}
+	if err := uc.seriesRepo.Delete(ctx, series.ID); err != nil {
+		return fmt.Errorf("DeleteSeries: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditSeriesDelete, domain.AuditEntitySeries, series.ID, *series, nil)
+	return nil
+}
+
+func (uc *SeriesUsecase) GetSeries(ctx context.Context, id string) (dto.SeriesResponse, error) {
+	series, err := loadSeries(ctx, uc.seriesRepo, id)
+	if err != nil {
+		return dto.SeriesResponse{}, err
+	}
+	return dto.NewSeriesResponse(*series), nil
+}
+
+func (uc *SeriesUsecase) SearchSeries(ctx context.Context, title string, page domain.PageRequest) (domain.Page[dto.SeriesResponse], error) {
+	series, err := uc.seriesRepo.SearchPage(ctx, title, page)
+	if err != nil {
+		return domain.Page[dto.SeriesResponse]{}, fmt.Errorf("SearchSeries: %w", err)
+	}
+	return domain.Page[dto.SeriesResponse]{
+		Items: dto.NewSeriesResponses(series.Items),
+		Total: series.Total,
+		Next:  series.Next,
+	}, nil
+}
+
+// loadSeries — серия по ID; ErrSeriesNotFound, если её нет
+func loadSeries(ctx context.Context, seriesRepo repo.SeriesRepository, id string) (*domain.Series, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	series, err := seriesRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loadSeries: %w", err)
+	}
+	if series == nil {
+		return nil, customErr.ErrSeriesNotFound
+	}
+	return series, nil
+}
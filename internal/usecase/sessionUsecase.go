@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/auth"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"log"
+	"time"
+)
+
+type SessionUsecase struct {
+	sessionRepo repo.SessionRepository
+	userRepo    repo.UserRepository
+	tokens      TokenIssuer
+	refreshTTL  time.Duration
+}
+
+func NewSessionUsecase(
+	sessionRepo repo.SessionRepository,
+	userRepo repo.UserRepository,
+	tokens TokenIssuer,
+	refreshTTL time.Duration,
+) *SessionUsecase {
+	return &SessionUsecase{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		tokens:      tokens,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// StartSession создаёт сессию для вошедшего пользователя и выдаёт пару токенов
+func (uc *SessionUsecase) StartSession(ctx context.Context, user domain.User, client dto.ClientInfo) (dto.TokenPair, error) {
+	userObjID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return dto.TokenPair{}, customErr.ErrInvalidID
+	}
+
+	refresh, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return dto.TokenPair{}, fmt.Errorf("StartSession: %w", err)
+	}
+
+	now := time.Now()
+	session := domain.Session{
+		UserID:      userObjID,
+		RefreshHash: hash,
+		UserAgent:   client.UserAgent,
+		IP:          client.IP,
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		ExpiresAt:   now.Add(uc.refreshTTL),
+	}
+	if err := uc.sessionRepo.Create(ctx, &session); err != nil {
+		return dto.TokenPair{}, fmt.Errorf("StartSession: %w", err)
+	}
+
+	return uc.tokenPair(user, session.ID, refresh, session.ExpiresAt)
+}
+
+// Refresh обменивает refresh-токен на новую пару (ротация). Повторное
+// предъявление уже обменянного токена отзывает всю сессию.
+func (uc *SessionUsecase) Refresh(ctx context.Context, refreshToken string) (dto.TokenPair, error) {
+	if refreshToken == "" {
+		return dto.TokenPair{}, customErr.ErrInvalidToken
+	}
+
+	hash := auth.HashOpaqueToken(refreshToken)
+	session, err := uc.sessionRepo.GetByRefreshHash(ctx, hash)
+	if err != nil {
+		return dto.TokenPair{}, fmt.Errorf("Refresh: %w", err)
+	}
+	now := time.Now()
+	if session == nil || !session.Active(now) {
+		return dto.TokenPair{}, customErr.ErrInvalidToken
+	}
+	if session.RefreshHash != hash {
+		uc.revokeReused(ctx, session.ID, now)
+		return dto.TokenPair{}, customErr.ErrRefreshTokenReused
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, session.UserID.Hex())
+	if err != nil {
+		return dto.TokenPair{}, fmt.Errorf("Refresh: get user: %w", err)
+	}
+	if user == nil {
+		return dto.TokenPair{}, customErr.ErrInvalidToken
+	}
+	if !user.IsActive {
+		if err := uc.RevokeUserSessions(ctx, user.ID, domain.RevokeBlocked); err != nil {
+			log.Printf("Refresh: %v", err)
+		}
+		return dto.TokenPair{}, customErr.ErrUserBlocked
+	}
+
+	newRefresh, newHash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return dto.TokenPair{}, fmt.Errorf("Refresh: %w", err)
+	}
+	expiresAt := now.Add(uc.refreshTTL)
+	rotated, err := uc.sessionRepo.Rotate(ctx, session.ID, hash, newHash, expiresAt, now)
+	if err != nil {
+		return dto.TokenPair{}, fmt.Errorf("Refresh: %w", err)
+	}
+	if !rotated {
+		// Токен обменяли параллельно — тот же признак утечки
+		uc.revokeReused(ctx, session.ID, now)
+		return dto.TokenPair{}, customErr.ErrRefreshTokenReused
+	}
+
+	return uc.tokenPair(*user, session.ID, newRefresh, expiresAt)
+}
+
+func (uc *SessionUsecase) revokeReused(ctx context.Context, sessionID string, now time.Time) {
+	log.Printf("Refresh: refresh token reuse detected, revoking session %s", sessionID)
+	if err := uc.sessionRepo.Revoke(ctx, sessionID, domain.RevokeRefreshReuse, now); err != nil {
+		log.Printf("Refresh: revoke session %s: %v", sessionID, err)
+	}
+}
+
+func (uc *SessionUsecase) tokenPair(user domain.User, sessionID, refresh string, refreshExpiresAt time.Time) (dto.TokenPair, error) {
+	access, expiresAt, err := uc.tokens.IssueAccessToken(user, sessionID)
+	if err != nil {
+		return dto.TokenPair{}, fmt.Errorf("tokenPair: %w", err)
+	}
+	return dto.TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// Logout завершает текущую сессию
+func (uc *SessionUsecase) Logout(ctx context.Context) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || p.SessionID == "" {
+		return customErr.ErrUnauthorized
+	}
+	if err := uc.sessionRepo.Revoke(ctx, p.SessionID, domain.RevokeLogout, time.Now()); err != nil {
+		return fmt.Errorf("Logout: %w", err)
+	}
+	return nil
+}
+
+// ListSessions — активные сессии вызывающего пользователя
+func (uc *SessionUsecase) ListSessions(ctx context.Context) ([]dto.SessionResponse, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, customErr.ErrUnauthorized
+	}
+	userObjID, err := primitive.ObjectIDFromHex(p.UserID)
+	if err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+
+	sessions, err := uc.sessionRepo.ListActiveByUser(ctx, userObjID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ListSessions: %w", err)
+	}
+
+	res := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, dto.NewSessionResponse(s, p.SessionID))
+	}
+	return res, nil
+}
+
+// RevokeSession завершает одну свою сессию (выход на другом устройстве)
+func (uc *SessionUsecase) RevokeSession(ctx context.Context, id string) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return customErr.ErrUnauthorized
+	}
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return customErr.ErrInvalidID
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("RevokeSession: %w", err)
+	}
+	// Чужая сессия неотличима от несуществующей
+	if session == nil || session.UserID.Hex() != p.UserID {
+		return customErr.ErrSessionNotFound
+	}
+
+	if err := uc.sessionRepo.Revoke(ctx, id, domain.RevokeLogout, time.Now()); err != nil {
+		return fmt.Errorf("RevokeSession: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllSessions завершает все сессии вызывающего, включая текущую
+func (uc *SessionUsecase) RevokeAllSessions(ctx context.Context) (int64, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return 0, customErr.ErrUnauthorized
+	}
+	userObjID, err := primitive.ObjectIDFromHex(p.UserID)
+	if err != nil {
+		return 0, customErr.ErrInvalidID
+	}
+
+	n, err := uc.sessionRepo.RevokeAllByUser(ctx, userObjID, domain.RevokeLogout, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("RevokeAllSessions: %w", err)
+	}
+	return n, nil
+}
+
+// RevokeUserSessions отзывает все сессии пользователя (блокировка, смена пароля)
+func (uc *SessionUsecase) RevokeUserSessions(ctx context.Context, userID, reason string) error {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return customErr.ErrInvalidID
+	}
+	if _, err := uc.sessionRepo.RevokeAllByUser(ctx, userObjID, reason, time.Now()); err != nil {
+		return fmt.Errorf("RevokeUserSessions: %w", err)
+	}
+	return nil
+}
+
+// IsSessionActive — проверка сессии access-токена на каждом запросе
+func (uc *SessionUsecase) IsSessionActive(ctx context.Context, id string) (bool, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return false, nil
+	}
+	session, err := uc.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("IsSessionActive: %w", err)
+	}
+	return session != nil && session.Active(time.Now()), nil
+}
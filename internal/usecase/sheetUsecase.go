@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/isbn"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/sheet"
+	"library-Mongo/internal/usecase/dto"
+	"strconv"
+	"strings"
+)
+
+// Поля книги в таблице. Экспорт пишет sheetExportFields в этом порядке, так что выгрузка
+// загружается обратно без сопоставления колонок: строки с id обновляют книги
+var (
+	sheetExportFields = []string{"id", "title", "author", "year", "genre", "isbn"}
+	sheetImportFields = []string{"id", "title", "author", "year", "genre", "isbn", "copies"}
+)
+
+// SheetUsecase — загрузка и выгрузка книг таблицами CSV и XLSX.
+// Строки проверяются и сохраняются через BookUC, по тем же правилам, что и POST/PUT /books.
+type SheetUsecase struct {
+	bookRepo repo.BookRepository
+	books    BookUC
+}
+
+func NewSheetUsecase(bookRepo repo.BookRepository, books BookUC) *SheetUsecase {
+	return &SheetUsecase{bookRepo: bookRepo, books: books}
+}
+
+// ImportBooks заводит книги из строк без id и обновляет книги из строк с id.
+// mapping — поле книги -> заголовок колонки; поля без сопоставления ищутся по собственному имени.
+// Пустая ячейка в строке обновления оставляет поле как есть.
+func (uc *SheetUsecase) ImportBooks(ctx context.Context, r io.Reader, format string, mapping map[string]string, dryRun bool) (dto.SheetImportReport, error) {
+	rows, err := sheet.Read(r, format)
+	if err != nil {
+		return dto.SheetImportReport{}, fmt.Errorf("%w: %v", customErr.ErrInvalidSpreadsheet, err)
+	}
+	if len(rows) == 0 {
+		return dto.SheetImportReport{}, fmt.Errorf("%w: no header row", customErr.ErrInvalidSpreadsheet)
+	}
+	columns, err := sheetColumns(rows[0], mapping)
+	if err != nil {
+		return dto.SheetImportReport{}, err
+	}
+
+	report := dto.SheetImportReport{DryRun: dryRun, Columns: map[string]string{}, Rows: []dto.SheetImportRow{}}
+	for field, i := range columns {
+		report.Columns[field] = strings.TrimSpace(rows[0][i])
+	}
+	isbnRows := map[string]int{}
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		report.Add(uc.importRow(ctx, i+2, row, columns, dryRun, isbnRows))
+	}
+	return report, nil
+}
+
+// sheetColumns — номер колонки для каждого поля, найденного в заголовке
+func sheetColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	find := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	columns := map[string]int{}
+	for field, name := range mapping {
+		if !isSheetField(field) {
+			return nil, fmt.Errorf("%w: unknown field %q", customErr.ErrColumnMapping, field)
+		}
+		i := find(name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: column %q not found", customErr.ErrColumnMapping, name)
+		}
+		columns[field] = i
+	}
+	for _, field := range sheetImportFields {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		if i := find(field); i >= 0 {
+			columns[field] = i
+		}
+	}
+
+	// Без колонки id каждая строка — новая книга, и без обязательных колонок ни одна не пройдёт
+	if _, ok := columns["id"]; !ok {
+		var missing []string
+		for _, field := range []string{"title", "author", "genre"} {
+			if _, ok := columns[field]; !ok {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: no column for %s", customErr.ErrColumnMapping, strings.Join(missing, ", "))
+		}
+	}
+	return columns, nil
+}
+
+func isSheetField(field string) bool {
+	for _, f := range sheetImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (uc *SheetUsecase) importRow(ctx context.Context, n int, row []string, columns map[string]int, dryRun bool, isbnRows map[string]int) dto.SheetImportRow {
+	res := dto.SheetImportRow{Row: n, Action: "create"}
+	get := func(field string) string {
+		i, ok := columns[field]
+		if !ok {
+			return ""
+		}
+		return sheet.Cell(row, i)
+	}
+
+	var errs []string
+	year, copies := 0, 0
+	if v := get("year"); v != "" {
+		y, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("year: %q is not a number", v))
+		}
+		year = y
+	}
+	if v := get("copies"); v != "" {
+		c, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("copies: %q is not a number", v))
+		}
+		copies = c
+	}
+	// Один ISBN дважды в файле: вторая строка упала бы на сохранении, dry run должен это показать
+	if v := get("isbn"); v != "" {
+		if isbn13, err := isbn.Normalize(v); err == nil {
+			if prev, ok := isbnRows[isbn13]; ok {
+				errs = append(errs, fmt.Sprintf("isbn: same ISBN as row %d", prev))
+			} else {
+				isbnRows[isbn13] = n
+			}
+		}
+	}
+
+	id := get("id")
+	if id != "" {
+		res.Action, res.BookID = "update", id
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			errs = append(errs, "id: invalid book ID")
+		}
+		if copies != 0 {
+			errs = append(errs, "copies: only for new books, add items via /books/{id}/items")
+		}
+	}
+	if len(errs) > 0 {
+		res.Status, res.Errors = dto.RowInvalid, errs
+		return res
+	}
+
+	if id != "" {
+		return uc.updateRow(ctx, res, sheetUpdateInput(id, get, year), dryRun)
+	}
+
+	input := dto.CreateBookInput{
+		Title:  get("title"),
+		Author: get("author"),
+		Year:   year,
+		Genre:  get("genre"),
+		ISBN:   get("isbn"),
+		Copies: copies,
+	}
+	if err := uc.books.ValidateBook(ctx, input); err != nil {
+		res.Status, res.Errors = dto.RowInvalid, []string{rowError(err)}
+		return res
+	}
+	if dryRun {
+		res.Status = dto.RowValid
+		return res
+	}
+	book, err := uc.books.CreateBook(ctx, input)
+	if err != nil {
+		res.Status, res.Errors = dto.RowFailed, []string{rowError(err)}
+		return res
+	}
+	res.Status, res.BookID = dto.RowCreated, book.ID
+	return res
+}
+
+// sheetUpdateInput — только непустые ячейки строки
+func sheetUpdateInput(id string, get func(string) string, year int) dto.UpdateBookInput {
+	input := dto.UpdateBookInput{ID: id}
+	set := func(field string) *string {
+		if v := get(field); v != "" {
+			return &v
+		}
+		return nil
+	}
+	input.Title = set("title")
+	input.Author = set("author")
+	input.Genre = set("genre")
+	input.ISBN = set("isbn")
+	if get("year") != "" {
+		input.Year = &year
+	}
+	return input
+}
+
+func (uc *SheetUsecase) updateRow(ctx context.Context, res dto.SheetImportRow, input dto.UpdateBookInput, dryRun bool) dto.SheetImportRow {
+	if err := uc.books.ValidateBookUpdate(ctx, input); err != nil {
+		res.Status, res.Errors = dto.RowInvalid, []string{rowError(err)}
+		return res
+	}
+
+	// Строка выгрузки, которую не правили, не создаёт лишних записей в журнале аудита
+	current, err := uc.books.GetBookByID(ctx, input.ID)
+	if err != nil {
+		res.Status, res.Errors = dto.RowFailed, []string{rowError(err)}
+		return res
+	}
+	if !bookChanged(current, input) {
+		res.Status = dto.RowUnchanged
+		return res
+	}
+	if dryRun {
+		res.Status = dto.RowValid
+		return res
+	}
+
+	if err := uc.books.UpdateBook(ctx, input); err != nil {
+		res.Status, res.Errors = dto.RowFailed, []string{rowError(err)}
+		return res
+	}
+	res.Status = dto.RowUpdated
+	return res
+}
+
+func bookChanged(b dto.BookResponse, input dto.UpdateBookInput) bool {
+	switch {
+	case input.Title != nil && *input.Title != b.Title,
+		input.Author != nil && *input.Author != b.Author,
+		input.Genre != nil && *input.Genre != b.Genre,
+		input.Year != nil && *input.Year != b.Year:
+		return true
+	case input.ISBN != nil:
+		isbn13, _ := isbn.Normalize(*input.ISBN)
+		return isbn13 != b.ISBN13
+	}
+	return false
+}
+
+// rowError — текст ошибки строки без внутренних префиксов
+func rowError(err error) string {
+	if errors.Is(err, customErr.ErrBookNotFound) {
+		return "book not found"
+	}
+	for _, known := range []error{
+		customErr.ErrInvalidISBN,
+		customErr.ErrISBNTaken,
+		customErr.ErrInvalidContributor,
+		customErr.ErrAuthorNotFound,
+		customErr.ErrInvalidID,
+	} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return err.Error()
+}
+
+// ExportBooks пишет книги под фильтром в w таблицей format и возвращает их число.
+// До первой книги в w ничего не пишется, так что ошибку фильтра можно вернуть обычным ответом.
+func (uc *SheetUsecase) ExportBooks(ctx context.Context, filter domain.BookFilter, format string, w io.Writer) (int, error) {
+	filter.ISBN = isbnQuery(filter.ISBN)
+
+	sw, err := sheet.NewWriter(w, format)
+	if err != nil {
+		return 0, fmt.Errorf("ExportBooks: %w", err)
+	}
+	count := 0
+	err = uc.bookRepo.Each(ctx, filter, func(b domain.Book) error {
+		if count == 0 {
+			if err := sw.WriteRow(sheetExportFields); err != nil {
+				return err
+			}
+		}
+		count++
+		year := ""
+		if b.Year != 0 {
+			year = strconv.Itoa(b.Year)
+		}
+		return sw.WriteRow([]string{b.ID, b.Title, b.Author, year, b.Genre, b.ISBN13})
+	})
+	if err != nil {
+		return count, fmt.Errorf("ExportBooks: %w", err)
+	}
+	if count == 0 {
+		if err := sw.WriteRow(sheetExportFields); err != nil {
+			return 0, fmt.Errorf("ExportBooks: %w", err)
+		}
+	}
+	if err := sw.Close(); err != nil {
+		return count, fmt.Errorf("ExportBooks: %w", err)
+	}
+	return count, nil
+}
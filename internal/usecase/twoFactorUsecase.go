@@ -0,0 +1,465 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/auth"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"log"
+	"slices"
+	"time"
+)
+
+// recoveryCodeCount — сколько кодов восстановления выдаётся при привязке
+const recoveryCodeCount = 10
+
+type TwoFactorUsecase struct {
+	twoFactorRepo repo.TwoFactorRepository
+	settingsRepo  repo.SettingsRepository
+	userRepo      repo.UserRepository
+	challenges    ChallengeIssuer
+	secrets       SecretCipher
+	sessions      SessionManager
+	guard         LoginThrottler
+	issuer        string
+}
+
+func NewTwoFactorUsecase(
+	twoFactorRepo repo.TwoFactorRepository,
+	settingsRepo repo.SettingsRepository,
+	userRepo repo.UserRepository,
+	challenges ChallengeIssuer,
+	secrets SecretCipher,
+	sessions SessionManager,
+	guard LoginThrottler,
+	issuer string,
+) *TwoFactorUsecase {
+	return &TwoFactorUsecase{
+		twoFactorRepo: twoFactorRepo,
+		settingsRepo:  settingsRepo,
+		userRepo:      userRepo,
+		challenges:    challenges,
+		secrets:       secrets,
+		sessions:      sessions,
+		guard:         guard,
+		issuer:        issuer,
+	}
+}
+
+// LoginChallenge решает, нужен ли второй шаг входа после проверки пароля;
+// nil — 2FA не включена и для роли не обязательна
+func (uc *TwoFactorUsecase) LoginChallenge(ctx context.Context, user domain.User) (*dto.TwoFactorChallenge, error) {
+	userObjID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+
+	tf, err := uc.twoFactorRepo.Get(ctx, userObjID)
+	if err != nil {
+		return nil, fmt.Errorf("LoginChallenge: %w", err)
+	}
+	purpose := ""
+	if tf != nil && tf.Enabled {
+		purpose = auth.ChallengeVerify
+	} else {
+		required, err := uc.isRequired(ctx, user.Role)
+		if err != nil {
+			return nil, fmt.Errorf("LoginChallenge: %w", err)
+		}
+		if required {
+			purpose = auth.ChallengeEnroll
+		}
+	}
+	if purpose == "" {
+		return nil, nil
+	}
+
+	token, expiresAt, err := uc.challenges.IssueChallengeToken(user.ID, purpose)
+	if err != nil {
+		return nil, fmt.Errorf("LoginChallenge: %w", err)
+	}
+	return &dto.TwoFactorChallenge{ChallengeToken: token, Type: purpose, ExpiresAt: expiresAt}, nil
+}
+
+// CompleteLogin — второй шаг входа. Для challenge типа enroll код подтверждает
+// начатую через BeginLoginEnrollment привязку, и в ответе приходят коды восстановления.
+// Неверные коды учитываются LoginGuard так же, как неверные пароли.
+func (uc *TwoFactorUsecase) CompleteLogin(ctx context.Context, input dto.TwoFactorLoginInput) (dto.LoginResponse, error) {
+	user, purpose, err := uc.challengeUser(ctx, input.ChallengeToken)
+	if err != nil {
+		return dto.LoginResponse{}, err
+	}
+	if err := uc.guard.Check(ctx, user.Phone, input.Client.IP); err != nil {
+		return dto.LoginResponse{}, err
+	}
+
+	userObjID, _ := primitive.ObjectIDFromHex(user.ID)
+	tf, err := uc.twoFactorRepo.Get(ctx, userObjID)
+	if err != nil {
+		return dto.LoginResponse{}, fmt.Errorf("CompleteLogin: %w", err)
+	}
+
+	var recoveryCodes []string
+	switch purpose {
+	case auth.ChallengeVerify:
+		// Аутентификатор могли сбросить, пока токен был действителен
+		if tf == nil || !tf.Enabled {
+			return dto.LoginResponse{}, customErr.ErrInvalidToken
+		}
+		ok, err := uc.checkCode(ctx, tf, input.Code, input.RecoveryCode)
+		if err != nil {
+			return dto.LoginResponse{}, fmt.Errorf("CompleteLogin: %w", err)
+		}
+		if !ok {
+			return dto.LoginResponse{}, uc.codeFailed(ctx, user.Phone, input.Client.IP)
+		}
+	case auth.ChallengeEnroll:
+		if tf == nil {
+			return dto.LoginResponse{}, customErr.ErrTwoFactorNotEnabled
+		}
+		if tf.Enabled {
+			return dto.LoginResponse{}, customErr.ErrInvalidToken
+		}
+		recoveryCodes, err = uc.confirm(ctx, tf, input.Code)
+		if errors.Is(err, customErr.ErrCodeInvalid) {
+			return dto.LoginResponse{}, uc.codeFailed(ctx, user.Phone, input.Client.IP)
+		}
+		if err != nil {
+			return dto.LoginResponse{}, fmt.Errorf("CompleteLogin: %w", err)
+		}
+	default:
+		return dto.LoginResponse{}, customErr.ErrInvalidToken
+	}
+
+	if err := uc.guard.Success(ctx, user.Phone); err != nil {
+		log.Printf("CompleteLogin: %v", err)
+	}
+
+	tokens, err := uc.sessions.StartSession(ctx, *user, input.Client)
+	if err != nil {
+		return dto.LoginResponse{}, fmt.Errorf("CompleteLogin: %w", err)
+	}
+	resp := dto.NewUserResponse(*user, auth.Principal{UserID: user.ID, Role: user.Role})
+	return dto.LoginResponse{
+		TokenPair:     &tokens,
+		User:          &resp,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// codeFailed учитывает неверный код; если это привело к блокировке — возвращает её
+func (uc *TwoFactorUsecase) codeFailed(ctx context.Context, phone, ip string) error {
+	if err := uc.guard.Failure(ctx, phone, ip); err != nil {
+		if errors.Is(err, customErr.ErrLoginLocked) {
+			return err
+		}
+		log.Printf("CompleteLogin: %v", err)
+	}
+	return customErr.ErrCodeInvalid
+}
+
+// BeginLoginEnrollment выдаёт секрет по challenge типа enroll — для сотрудников,
+// которые ещё не могут войти, потому что их роль требует 2FA
+func (uc *TwoFactorUsecase) BeginLoginEnrollment(ctx context.Context, challengeToken string) (dto.TwoFactorEnrollment, error) {
+	user, purpose, err := uc.challengeUser(ctx, challengeToken)
+	if err != nil {
+		return dto.TwoFactorEnrollment{}, err
+	}
+	if purpose != auth.ChallengeEnroll {
+		return dto.TwoFactorEnrollment{}, customErr.ErrInvalidToken
+	}
+	return uc.begin(ctx, *user)
+}
+
+// BeginEnrollment начинает привязку аутентификатора для вошедшего пользователя
+func (uc *TwoFactorUsecase) BeginEnrollment(ctx context.Context) (dto.TwoFactorEnrollment, error) {
+	user, err := uc.currentUser(ctx)
+	if err != nil {
+		return dto.TwoFactorEnrollment{}, err
+	}
+	return uc.begin(ctx, *user)
+}
+
+// ConfirmEnrollment включает 2FA первым кодом из приложения и выдаёт коды восстановления
+func (uc *TwoFactorUsecase) ConfirmEnrollment(ctx context.Context, code string) (dto.RecoveryCodesResponse, error) {
+	user, err := uc.currentUser(ctx)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	userObjID, _ := primitive.ObjectIDFromHex(user.ID)
+
+	tf, err := uc.twoFactorRepo.Get(ctx, userObjID)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, fmt.Errorf("ConfirmEnrollment: %w", err)
+	}
+	if tf == nil {
+		return dto.RecoveryCodesResponse{}, customErr.ErrTwoFactorNotEnabled
+	}
+	if tf.Enabled {
+		return dto.RecoveryCodesResponse{}, customErr.ErrTwoFactorEnabled
+	}
+
+	codes, err := uc.confirm(ctx, tf, code)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	return dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (uc *TwoFactorUsecase) GetStatus(ctx context.Context) (dto.TwoFactorStatus, error) {
+	user, err := uc.currentUser(ctx)
+	if err != nil {
+		return dto.TwoFactorStatus{}, err
+	}
+	userObjID, _ := primitive.ObjectIDFromHex(user.ID)
+
+	tf, err := uc.twoFactorRepo.Get(ctx, userObjID)
+	if err != nil {
+		return dto.TwoFactorStatus{}, fmt.Errorf("GetStatus: %w", err)
+	}
+	required, err := uc.isRequired(ctx, user.Role)
+	if err != nil {
+		return dto.TwoFactorStatus{}, fmt.Errorf("GetStatus: %w", err)
+	}
+
+	status := dto.TwoFactorStatus{Required: required}
+	if tf != nil && tf.Enabled {
+		status.Enabled = true
+		status.EnabledAt = tf.EnabledAt
+		status.RecoveryCodesLeft = len(tf.RecoveryCodes)
+	}
+	return status, nil
+}
+
+// Disable отключает свою 2FA по действующему коду; недоступно, если она обязательна для роли
+func (uc *TwoFactorUsecase) Disable(ctx context.Context, input dto.TwoFactorCodeRequest) error {
+	user, err := uc.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	userObjID, _ := primitive.ObjectIDFromHex(user.ID)
+
+	tf, err := uc.twoFactorRepo.Get(ctx, userObjID)
+	if err != nil {
+		return fmt.Errorf("Disable: %w", err)
+	}
+	if tf == nil || !tf.Enabled {
+		return customErr.ErrTwoFactorNotEnabled
+	}
+	required, err := uc.isRequired(ctx, user.Role)
+	if err != nil {
+		return fmt.Errorf("Disable: %w", err)
+	}
+	if required {
+		return customErr.ErrTwoFactorRequired
+	}
+
+	ok, err := uc.checkCode(ctx, tf, input.Code, input.RecoveryCode)
+	if err != nil {
+		return fmt.Errorf("Disable: %w", err)
+	}
+	if !ok {
+		return customErr.ErrCodeInvalid
+	}
+	if _, err := uc.twoFactorRepo.Delete(ctx, userObjID); err != nil {
+		return fmt.Errorf("Disable: %w", err)
+	}
+	return nil
+}
+
+// ResetUser (admin) удаляет аутентификатор коллеги, потерявшего устройство,
+// и завершает его сессии; если роль требует 2FA, при следующем входе он привяжет новое
+func (uc *TwoFactorUsecase) ResetUser(ctx context.Context, userID string) error {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return customErr.ErrInvalidID
+	}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ResetUser: %w", err)
+	}
+	if user == nil {
+		return customErr.ErrUserNotFound
+	}
+
+	deleted, err := uc.twoFactorRepo.Delete(ctx, userObjID)
+	if err != nil {
+		return fmt.Errorf("ResetUser: %w", err)
+	}
+	if !deleted {
+		return customErr.ErrTwoFactorNotEnabled
+	}
+	if err := uc.sessions.RevokeUserSessions(ctx, userID, domain.RevokeTwoFactorReset); err != nil {
+		return fmt.Errorf("ResetUser: %w", err)
+	}
+	return nil
+}
+
+func (uc *TwoFactorUsecase) GetPolicy(ctx context.Context) (dto.TwoFactorPolicy, error) {
+	s, err := uc.settingsRepo.GetSecurity(ctx)
+	if err != nil {
+		return dto.TwoFactorPolicy{}, fmt.Errorf("GetPolicy: %w", err)
+	}
+	policy := dto.TwoFactorPolicy{Roles: s.TwoFactorRoles, UpdatedBy: s.UpdatedBy}
+	if policy.Roles == nil {
+		policy.Roles = []string{}
+	}
+	if !s.UpdatedAt.IsZero() {
+		policy.UpdatedAt = &s.UpdatedAt
+	}
+	return policy, nil
+}
+
+// SetPolicy (admin) задаёт роли, для которых 2FA обязательна
+func (uc *TwoFactorUsecase) SetPolicy(ctx context.Context, roles []string) (dto.TwoFactorPolicy, error) {
+	unique := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !auth.IsValidRole(role) {
+			return dto.TwoFactorPolicy{}, customErr.ErrInvalidRole
+		}
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+
+	p, _ := auth.PrincipalFromContext(ctx)
+	s := domain.SecuritySettings{
+		TwoFactorRoles: unique,
+		UpdatedAt:      time.Now(),
+		UpdatedBy:      p.UserID,
+	}
+	if err := uc.settingsRepo.SaveSecurity(ctx, s); err != nil {
+		return dto.TwoFactorPolicy{}, fmt.Errorf("SetPolicy: %w", err)
+	}
+	return dto.TwoFactorPolicy{Roles: unique, UpdatedAt: &s.UpdatedAt, UpdatedBy: s.UpdatedBy}, nil
+}
+
+func (uc *TwoFactorUsecase) isRequired(ctx context.Context, role string) (bool, error) {
+	s, err := uc.settingsRepo.GetSecurity(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(s.TwoFactorRoles, role), nil
+}
+
+// challengeUser проверяет токен второго шага и возвращает пользователя и назначение токена
+func (uc *TwoFactorUsecase) challengeUser(ctx context.Context, challengeToken string) (*domain.User, string, error) {
+	claims, err := uc.challenges.ParseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := uc.userRepo.GetByID(ctx, claims.Subject)
+	if err != nil {
+		return nil, "", fmt.Errorf("challengeUser: %w", err)
+	}
+	if user == nil {
+		return nil, "", customErr.ErrInvalidToken
+	}
+	if !user.IsActive {
+		return nil, "", customErr.ErrUserBlocked
+	}
+	return user, claims.Purpose, nil
+}
+
+func (uc *TwoFactorUsecase) currentUser(ctx context.Context) (*domain.User, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, customErr.ErrUnauthorized
+	}
+	user, err := uc.userRepo.GetByID(ctx, p.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("currentUser: %w", err)
+	}
+	if user == nil {
+		return nil, customErr.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// begin генерирует новый секрет; незавершённая привязка перезаписывается
+func (uc *TwoFactorUsecase) begin(ctx context.Context, user domain.User) (dto.TwoFactorEnrollment, error) {
+	userObjID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return dto.TwoFactorEnrollment{}, customErr.ErrInvalidID
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return dto.TwoFactorEnrollment{}, fmt.Errorf("BeginEnrollment: %w", err)
+	}
+	sealed, err := uc.secrets.Seal(secret)
+	if err != nil {
+		return dto.TwoFactorEnrollment{}, fmt.Errorf("BeginEnrollment: %w", err)
+	}
+
+	ok, err := uc.twoFactorRepo.SavePending(ctx, &domain.TwoFactor{
+		UserID:    userObjID,
+		Secret:    sealed,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return dto.TwoFactorEnrollment{}, fmt.Errorf("BeginEnrollment: %w", err)
+	}
+	if !ok {
+		return dto.TwoFactorEnrollment{}, customErr.ErrTwoFactorEnabled
+	}
+
+	return dto.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(uc.issuer, user.Phone, secret),
+	}, nil
+}
+
+// confirm проверяет первый код и включает 2FA, возвращая открытые коды восстановления
+func (uc *TwoFactorUsecase) confirm(ctx context.Context, tf *domain.TwoFactor, code string) ([]string, error) {
+	secret, err := uc.secrets.Open(tf.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("confirm: %w", err)
+	}
+	step, ok := auth.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, customErr.ErrCodeInvalid
+	}
+
+	codes, err := auth.NewRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("confirm: %w", err)
+	}
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = auth.HashOpaqueToken(c)
+	}
+
+	enabled, err := uc.twoFactorRepo.Enable(ctx, tf.UserID, hashes, step, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("confirm: %w", err)
+	}
+	if !enabled {
+		return nil, customErr.ErrTwoFactorEnabled
+	}
+	return codes, nil
+}
+
+// checkCode принимает код из приложения (однократно в пределах интервала)
+// или неиспользованный код восстановления
+func (uc *TwoFactorUsecase) checkCode(ctx context.Context, tf *domain.TwoFactor, code, recoveryCode string) (bool, error) {
+	if recoveryCode != "" {
+		hash := auth.HashOpaqueToken(auth.NormalizeRecoveryCode(recoveryCode))
+		return uc.twoFactorRepo.UseRecoveryCode(ctx, tf.UserID, hash)
+	}
+
+	secret, err := uc.secrets.Open(tf.Secret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := auth.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return uc.twoFactorRepo.UseStep(ctx, tf.UserID, step)
+}
@@ -2,90 +2,249 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"library-Mongo/internal/auth"
 	"library-Mongo/internal/domain"
 	customErr "library-Mongo/internal/errors"
 	"library-Mongo/internal/repo"
 	"library-Mongo/internal/usecase/dto"
+	"log"
 	"time"
 )
 
 type UserUsecase struct {
-	userRepo repo.UserRepository
+	userRepo  repo.UserRepository
+	sessions  SessionManager
+	passwords PasswordHasher
+	guard     LoginThrottler
+	verifier  PhoneVerifier
+	twoFactor TwoFactorGate
+	audit     AuditRecorder
 }
 
-func NewUserUsecase(userRepo repo.UserRepository) *UserUsecase {
-	return &UserUsecase{userRepo: userRepo}
+func NewUserUsecase(
+	userRepo repo.UserRepository,
+	sessions SessionManager,
+	passwords PasswordHasher,
+	guard LoginThrottler,
+	verifier PhoneVerifier,
+	twoFactor TwoFactorGate,
+	audit AuditRecorder,
+) *UserUsecase {
+	return &UserUsecase{
+		userRepo:  userRepo,
+		sessions:  sessions,
+		passwords: passwords,
+		guard:     guard,
+		verifier:  verifier,
+		twoFactor: twoFactor,
+		audit:     audit,
+	}
 }
 
-func (uc *UserUsecase) RegisterUser(ctx context.Context, input dto.RegisterUserInput) (domain.User, error) {
+func (uc *UserUsecase) RegisterUser(ctx context.Context, input dto.RegisterUserInput) (dto.UserResponse, error) {
 	if input.FullName == "" || input.Phone == "" || input.Password == "" || input.Role == "" {
-		return domain.User{}, fmt.Errorf("RegisterUser: missing required fields")
+		return dto.UserResponse{}, fmt.Errorf("RegisterUser: missing required fields")
+	}
+	if !auth.IsValidRole(input.Role) {
+		return dto.UserResponse{}, customErr.ErrInvalidRole
+	}
+	// Сотрудников регистрирует только администратор
+	if input.Role != auth.RoleReader && callerRole(ctx) != auth.RoleAdmin {
+		return dto.UserResponse{}, customErr.ErrForbidden
+	}
+	if err := auth.ValidatePassword(input.Password); err != nil {
+		return dto.UserResponse{}, err
+	}
+	if err := uc.checkPhoneFree(ctx, input.Phone, ""); err != nil {
+		return dto.UserResponse{}, fmt.Errorf("RegisterUser: %w", err)
+	}
+
+	hash, err := uc.passwords.Hash(input.Password)
+	if err != nil {
+		return dto.UserResponse{}, fmt.Errorf("RegisterUser: %w", err)
 	}
 
 	user := domain.User{
 		FullName:     input.FullName,
 		Phone:        input.Phone,
-		Password:     input.Password,
+		Password:     hash,
 		Role:         input.Role,
 		RegisteredAt: time.Now().Format("2006-01-02 15:04:05"),
 		IsActive:     true,
+		// Самостоятельная регистрация активируется после подтверждения телефона;
+		// учётные записи, заведённые сотрудником, активны сразу
+		PendingVerification: !auth.IsStaff(callerRole(ctx)),
 	}
 
 	if err := uc.userRepo.Create(ctx, &user); err != nil {
-		return domain.User{}, fmt.Errorf("RegisterUser: %w", err)
+		return dto.UserResponse{}, fmt.Errorf("RegisterUser: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditUserRegister, domain.AuditEntityUser, user.ID, nil, user)
+	if user.PendingVerification {
+		// Код можно запросить повторно, поэтому ошибка отправки не отменяет регистрацию
+		if err := uc.verifier.StartPhoneVerification(ctx, user); err != nil {
+			log.Printf("RegisterUser: %v", err)
+		}
 	}
 
-	return user, nil
+	viewer, _ := auth.PrincipalFromContext(ctx)
+	return dto.NewUserResponse(user, viewer), nil
 }
 
-func (uc *UserUsecase) Login(ctx context.Context, phone, password string) (domain.User, error) {
+func (uc *UserUsecase) Login(ctx context.Context, input dto.LoginInput) (dto.LoginResponse, error) {
+	phone, password := input.Phone, input.Password
 	if phone == "" || password == "" {
-		return domain.User{}, fmt.Errorf("Login: phone and password required")
+		return dto.LoginResponse{}, fmt.Errorf("Login: phone and password required")
 	}
 
-	user, err := uc.userRepo.Login(ctx, phone, password)
+	// Временная блокировка после серии неудач проверяется до пароля
+	if err := uc.guard.Check(ctx, phone, input.Client.IP); err != nil {
+		return dto.LoginResponse{}, err
+	}
+
+	user, err := uc.userRepo.GetByPhone(ctx, phone)
 	if err != nil {
-		return domain.User{}, fmt.Errorf("Login: %w", err)
+		return dto.LoginResponse{}, fmt.Errorf("Login: %w", err)
 	}
+	// Неверный пароль неотличим от неизвестного телефона, в том числе по времени ответа
 	if user == nil {
-		return domain.User{}, customErr.ErrUserNotFound
+		uc.passwords.VerifyDummy(password)
+		return dto.LoginResponse{}, uc.loginFailed(ctx, input)
+	}
+	ok, needsRehash := uc.passwords.Verify(user.Password, password)
+	if !ok {
+		return dto.LoginResponse{}, uc.loginFailed(ctx, input)
+	}
+	if err := uc.guard.Success(ctx, phone); err != nil {
+		log.Printf("Login: %v", err)
 	}
+	if needsRehash {
+		uc.rehashPassword(ctx, user, password)
+	}
+
 	if !user.IsActive {
-		return domain.User{}, customErr.ErrUserBlocked
+		return dto.LoginResponse{}, customErr.ErrUserBlocked
+	}
+	if user.PendingVerification {
+		return dto.LoginResponse{}, customErr.ErrPhoneNotVerified
 	}
 
-	return *user, nil
+	// С включённой (или обязательной для роли) 2FA вместо токенов выдаётся challenge
+	challenge, err := uc.twoFactor.LoginChallenge(ctx, *user)
+	if err != nil {
+		return dto.LoginResponse{}, fmt.Errorf("Login: %w", err)
+	}
+	if challenge != nil {
+		return dto.LoginResponse{TwoFactor: challenge}, nil
+	}
+
+	tokens, err := uc.sessions.StartSession(ctx, *user, input.Client)
+	if err != nil {
+		return dto.LoginResponse{}, fmt.Errorf("Login: %w", err)
+	}
+
+	resp := dto.NewUserResponse(*user, auth.Principal{UserID: user.ID, Role: user.Role})
+	return dto.LoginResponse{
+		TokenPair: &tokens,
+		User:      &resp,
+	}, nil
+}
+
+// loginFailed учитывает неудачу; если она привела к блокировке — возвращает её
+func (uc *UserUsecase) loginFailed(ctx context.Context, input dto.LoginInput) error {
+	if err := uc.guard.Failure(ctx, input.Phone, input.Client.IP); err != nil {
+		if errors.Is(err, customErr.ErrLoginLocked) {
+			return err
+		}
+		log.Printf("Login: %v", err)
+	}
+	return customErr.ErrUserNotFound
+}
+
+// rehashPassword заменяет открытый пароль (или хэш с устаревшей стоимостью)
+// после успешного входа; ошибка не мешает входу
+func (uc *UserUsecase) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	hash, err := uc.passwords.Hash(password)
+	if err == nil {
+		err = uc.userRepo.UpdatePassword(ctx, user.ID, hash)
+	}
+	if err != nil {
+		log.Printf("Login: rehash password for user %s: %v", user.ID, err)
+		return
+	}
+	user.Password = hash
 }
 
-func (uc *UserUsecase) GetUserByID(ctx context.Context, id string) (domain.User, error) {
+func (uc *UserUsecase) GetUserByID(ctx context.Context, id string) (dto.UserResponse, error) {
 	if id == "" {
-		return domain.User{}, customErr.ErrInvalidID
+		return dto.UserResponse{}, customErr.ErrInvalidID
+	}
+	if err := checkOwner(ctx, id); err != nil {
+		return dto.UserResponse{}, err
 	}
 
 	user, err := uc.userRepo.GetByID(ctx, id)
 	if err != nil {
-		return domain.User{}, fmt.Errorf("GetUserByID: %w", err)
+		return dto.UserResponse{}, fmt.Errorf("GetUserByID: %w", err)
 	}
 	if user == nil {
-		return domain.User{}, customErr.ErrUserNotFound
+		return dto.UserResponse{}, customErr.ErrUserNotFound
 	}
 
-	return *user, nil
+	viewer, _ := auth.PrincipalFromContext(ctx)
+	return dto.NewUserResponse(*user, viewer), nil
 }
 
-func (uc *UserUsecase) SearchUsers(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
-	users, err := uc.userRepo.Search(ctx, filter)
+// SearchUsers — страница пользователей; если по ФИО ничего не нашлось, слова с опечатками
+// заменяются ближайшими ключами ФИО и возвращается подсказка с исправленным ФИО
+func (uc *UserUsecase) SearchUsers(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (domain.Page[dto.UserResponse], dto.SearchSuggestion, error) {
+	users, err := uc.userRepo.SearchPage(ctx, filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("SearchUsers: %w", err)
+		return domain.Page[dto.UserResponse]{}, nil, fmt.Errorf("SearchUsers: %w", err)
+	}
+	var suggestion dto.SearchSuggestion
+	if users.Total == 0 && page.After == "" && filter.FullNameContains != "" {
+		fixes, err := correctKeys(ctx, filter.FullNameContains, uc.userRepo.NameKeyVocabulary)
+		if err != nil {
+			return domain.Page[dto.UserResponse]{}, nil, fmt.Errorf("SearchUsers: %w", err)
+		}
+		if len(fixes) > 0 {
+			fixed := filter
+			fixed.FullNameContains = rewriteWords(filter.FullNameContains, fixes, sameKey)
+			fixedUsers, err := uc.userRepo.SearchPage(ctx, fixed, page)
+			if err != nil {
+				return domain.Page[dto.UserResponse]{}, nil, fmt.Errorf("SearchUsers: %w", err)
+			}
+			if fixedUsers.Total > 0 {
+				var names []string
+				for _, u := range fixedUsers.Items {
+					names = append(names, u.FullName)
+				}
+				users = fixedUsers
+				suggestion = dto.SearchSuggestion{"fullName": rewriteWords(filter.FullNameContains, fixes, func(fix string) string {
+					return displayWord(fix, names)
+				})}
+			}
+		}
 	}
-	return users, nil
+
+	viewer, _ := auth.PrincipalFromContext(ctx)
+	return domain.Page[dto.UserResponse]{
+		Items: dto.NewUserResponses(users.Items, viewer),
+		Total: users.Total,
+		Next:  users.Next,
+	}, suggestion, nil
 }
 
 func (uc *UserUsecase) UpdateUser(ctx context.Context, input dto.UpdateUserInput) error {
 	if input.ID == "" {
 		return customErr.ErrInvalidID
 	}
+	if err := checkOwner(ctx, input.ID); err != nil {
+		return err
+	}
 
 	user, err := uc.userRepo.GetByID(ctx, input.ID)
 	if err != nil {
@@ -94,15 +253,32 @@ func (uc *UserUsecase) UpdateUser(ctx context.Context, input dto.UpdateUserInput
 	if user == nil {
 		return customErr.ErrUserNotFound
 	}
+	if err := checkUserUpdate(ctx, user, input); err != nil {
+		return err
+	}
+	before := *user
 
 	if input.FullName != nil {
 		user.FullName = *input.FullName
 	}
-	if input.Phone != nil {
+	if input.Phone != nil && *input.Phone != user.Phone {
+		if err := uc.checkPhoneFree(ctx, *input.Phone, user.ID); err != nil {
+			return fmt.Errorf("UpdateUser: %w", err)
+		}
 		user.Phone = *input.Phone
+		// Новый телефон становится логином и адресом сброса пароля, поэтому подтверждается кодом,
+		// как при самостоятельной регистрации; телефон, внесённый сотрудником, считается проверенным
+		user.PendingVerification = !auth.IsStaff(callerRole(ctx))
 	}
 	if input.Password != nil {
-		user.Password = *input.Password
+		if err := auth.ValidatePassword(*input.Password); err != nil {
+			return err
+		}
+		hash, err := uc.passwords.Hash(*input.Password)
+		if err != nil {
+			return fmt.Errorf("UpdateUser: %w", err)
+		}
+		user.Password = hash
 	}
 	if input.Role != nil {
 		user.Role = *input.Role
@@ -114,17 +290,95 @@ func (uc *UserUsecase) UpdateUser(ctx context.Context, input dto.UpdateUserInput
 	if err := uc.userRepo.Update(ctx, user); err != nil {
 		return fmt.Errorf("UpdateUser: %w", err)
 	}
+	uc.audit.Record(ctx, userUpdateAction(before, *user), domain.AuditEntityUser, user.ID, before, *user)
+	if user.PendingVerification && user.Phone != before.Phone {
+		// Код можно запросить повторно, поэтому ошибка отправки не отменяет изменение
+		if err := uc.verifier.StartPhoneVerification(ctx, *user); err != nil {
+			log.Printf("UpdateUser: %v", err)
+		}
+	}
+
+	// Блокировка, смена пароля и смена роли немедленно завершают все сессии пользователя:
+	// роль записана в токене доступа, и без отзыва прежние права действовали бы до его истечения
+	reason := ""
+	switch {
+	case input.IsActive != nil && !*input.IsActive:
+		reason = domain.RevokeBlocked
+	case input.Password != nil:
+		reason = domain.RevokePasswordChanged
+	case user.Role != before.Role:
+		reason = domain.RevokeRoleChanged
+	}
+	if reason != "" {
+		if err := uc.sessions.RevokeUserSessions(ctx, user.ID, reason); err != nil {
+			return fmt.Errorf("UpdateUser: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkPhoneFree — ErrPhoneTaken, если телефон уже принадлежит другой учётной записи (не selfID):
+// по телефону входят и сбрасывают пароль, он должен указывать на одного пользователя
+func (uc *UserUsecase) checkPhoneFree(ctx context.Context, phone, selfID string) error {
+	owner, err := uc.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.ID != selfID {
+		return customErr.ErrPhoneTaken
+	}
+	return nil
+}
 
+// checkUserUpdate: роль меняет только администратор, блокирует — сотрудник,
+// библиотекарь не редактирует учётные записи других сотрудников
+func checkUserUpdate(ctx context.Context, target *domain.User, input dto.UpdateUserInput) error {
+	p, _ := auth.PrincipalFromContext(ctx)
+	if p.Role == auth.RoleAdmin {
+		if input.Role != nil && !auth.IsValidRole(*input.Role) {
+			return customErr.ErrInvalidRole
+		}
+		return nil
+	}
+	if input.Role != nil && *input.Role != target.Role {
+		return customErr.ErrForbidden
+	}
+	if input.IsActive != nil && !auth.IsStaff(p.Role) {
+		return customErr.ErrForbidden
+	}
+	if p.Role == auth.RoleLibrarian && auth.IsStaff(target.Role) && target.ID != p.UserID {
+		return customErr.ErrForbidden
+	}
 	return nil
 }
 
+// userUpdateAction — блокировку и разблокировку удобно искать в журнале отдельно
+func userUpdateAction(before, after domain.User) string {
+	switch {
+	case before.IsActive && !after.IsActive:
+		return domain.AuditUserBlock
+	case !before.IsActive && after.IsActive:
+		return domain.AuditUserUnblock
+	}
+	return domain.AuditUserUpdate
+}
+
 func (uc *UserUsecase) DeleteUser(ctx context.Context, id string) error {
 	if id == "" {
 		return customErr.ErrInvalidID
 	}
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("DeleteUser: %w", err)
+	}
+	if user == nil {
+		return customErr.ErrUserNotFound
+	}
 	if err := uc.userRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("DeleteUser: %w", err)
 	}
+	uc.audit.Record(ctx, domain.AuditUserDelete, domain.AuditEntityUser, id, *user, nil)
 	return nil
 }
 
@@ -141,3 +395,7 @@ func (uc *UserUsecase) UnblockUser(ctx context.Context, id string) error {
 	active := true
 	return uc.UpdateUser(ctx, dto.UpdateUserInput{ID: id, IsActive: &active})
 }
+
+func (uc *UserUsecase) ListLockoutEvents(ctx context.Context, filter domain.LockoutEventFilter) ([]domain.LockoutEvent, error) {
+	return uc.guard.ListLockoutEvents(ctx, filter)
+}
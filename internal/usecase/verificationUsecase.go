@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"library-Mongo/internal/auth"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/notify"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/usecase/dto"
+	"log"
+	"math/big"
+	"time"
+)
+
+// VerificationPolicy — сроки и лимиты одноразовых кодов
+type VerificationPolicy struct {
+	CodeTTL        time.Duration // срок действия кода
+	MaxAttempts    int           // неверных вводов до аннулирования кода
+	ResendCooldown time.Duration // пауза между отправками на один телефон
+	TicketTTL      time.Duration // срок токена сброса пароля
+}
+
+// VerificationUsecase — одноразовые коды по телефону: сброс пароля и
+// подтверждение телефона при регистрации
+type VerificationUsecase struct {
+	codeRepo  repo.VerificationCodeRepository
+	userRepo  repo.UserRepository
+	sender    notify.Sender
+	passwords PasswordHasher
+	sessions  SessionManager
+	policy    VerificationPolicy
+}
+
+func NewVerificationUsecase(
+	codeRepo repo.VerificationCodeRepository,
+	userRepo repo.UserRepository,
+	sender notify.Sender,
+	passwords PasswordHasher,
+	sessions SessionManager,
+	policy VerificationPolicy,
+) *VerificationUsecase {
+	return &VerificationUsecase{
+		codeRepo:  codeRepo,
+		userRepo:  userRepo,
+		sender:    sender,
+		passwords: passwords,
+		sessions:  sessions,
+		policy:    policy,
+	}
+}
+
+// RequestPasswordReset отправляет код сброса. Для неизвестного телефона и при
+// слишком частых запросах молча ничего не делает — ответ не выдаёт, есть ли такой пользователь.
+func (uc *VerificationUsecase) RequestPasswordReset(ctx context.Context, phone string) error {
+	if phone == "" {
+		return fmt.Errorf("RequestPasswordReset: phone required")
+	}
+
+	user, err := uc.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("RequestPasswordReset: %w", err)
+	}
+	if user == nil {
+		return nil
+	}
+
+	err = uc.issueCode(ctx, phone, domain.PurposePasswordReset, "Код для сброса пароля в библиотеке: %s")
+	if errors.Is(err, customErr.ErrCodeCooldown) {
+		return nil
+	}
+	return err
+}
+
+// VerifyPasswordReset проверяет код и выдаёт короткоживущий токен для смены пароля
+func (uc *VerificationUsecase) VerifyPasswordReset(ctx context.Context, phone, code string) (dto.ResetTicketResponse, error) {
+	c, err := uc.checkCode(ctx, phone, domain.PurposePasswordReset, code)
+	if err != nil {
+		return dto.ResetTicketResponse{}, err
+	}
+
+	ticket, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return dto.ResetTicketResponse{}, fmt.Errorf("VerifyPasswordReset: %w", err)
+	}
+	expiresAt := time.Now().Add(uc.policy.TicketTTL)
+	if err := uc.codeRepo.SetTicket(ctx, c.ID, hash, expiresAt); err != nil {
+		return dto.ResetTicketResponse{}, fmt.Errorf("VerifyPasswordReset: %w", err)
+	}
+
+	return dto.ResetTicketResponse{ResetToken: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса и завершает все сессии
+func (uc *VerificationUsecase) ResetPassword(ctx context.Context, input dto.ResetPasswordInput) error {
+	if input.ResetToken == "" {
+		return customErr.ErrInvalidToken
+	}
+
+	c, err := uc.codeRepo.GetByTicket(ctx, auth.HashOpaqueToken(input.ResetToken))
+	if err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+	now := time.Now()
+	if c == nil || c.ConsumedAt != nil || c.TicketExpiresAt == nil || now.After(*c.TicketExpiresAt) {
+		return customErr.ErrInvalidToken
+	}
+
+	if err := auth.ValidatePassword(input.NewPassword); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByPhone(ctx, c.Phone)
+	if err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+	if user == nil {
+		return customErr.ErrUserNotFound
+	}
+
+	hash, err := uc.passwords.Hash(input.NewPassword)
+	if err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+	if err := uc.userRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+	if err := uc.codeRepo.Consume(ctx, c.ID, now); err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+	// Код пришёл на этот телефон — владение подтверждено
+	if user.PendingVerification {
+		if err := uc.userRepo.MarkPhoneVerified(ctx, user.ID); err != nil {
+			return fmt.Errorf("ResetPassword: %w", err)
+		}
+	}
+
+	if err := uc.sessions.RevokeUserSessions(ctx, user.ID, domain.RevokePasswordChanged); err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+	return nil
+}
+
+// StartPhoneVerification отправляет код подтверждения только что зарегистрированному пользователю
+func (uc *VerificationUsecase) StartPhoneVerification(ctx context.Context, user domain.User) error {
+	return uc.issueCode(ctx, user.Phone, domain.PurposePhoneVerification, "Код подтверждения регистрации в библиотеке: %s")
+}
+
+// ResendPhoneVerification — повторная отправка кода подтверждения
+func (uc *VerificationUsecase) ResendPhoneVerification(ctx context.Context, phone string) error {
+	user, err := uc.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("ResendPhoneVerification: %w", err)
+	}
+	if user == nil || !user.PendingVerification {
+		return nil
+	}
+	return uc.StartPhoneVerification(ctx, *user)
+}
+
+// VerifyPhone подтверждает телефон кодом; после этого учётная запись может входить
+func (uc *VerificationUsecase) VerifyPhone(ctx context.Context, phone, code string) error {
+	c, err := uc.checkCode(ctx, phone, domain.PurposePhoneVerification, code)
+	if err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return fmt.Errorf("VerifyPhone: %w", err)
+	}
+	if user == nil {
+		return customErr.ErrCodeInvalid
+	}
+
+	if err := uc.userRepo.MarkPhoneVerified(ctx, user.ID); err != nil {
+		return fmt.Errorf("VerifyPhone: %w", err)
+	}
+	if err := uc.codeRepo.Consume(ctx, c.ID, time.Now()); err != nil {
+		return fmt.Errorf("VerifyPhone: %w", err)
+	}
+	return nil
+}
+
+// issueCode гасит прежние коды, создаёт новый и отправляет его через Sender
+func (uc *VerificationUsecase) issueCode(ctx context.Context, phone, purpose, text string) error {
+	now := time.Now()
+
+	last, err := uc.codeRepo.GetLatest(ctx, phone, purpose)
+	if err != nil {
+		return fmt.Errorf("issueCode: %w", err)
+	}
+	if last != nil && now.Sub(last.CreatedAt) < uc.policy.ResendCooldown {
+		return customErr.ErrCodeCooldown
+	}
+
+	if err := uc.codeRepo.InvalidateAll(ctx, phone, purpose, now); err != nil {
+		return fmt.Errorf("issueCode: %w", err)
+	}
+
+	code, err := randomDigits(6)
+	if err != nil {
+		return fmt.Errorf("issueCode: %w", err)
+	}
+	hash, err := uc.passwords.Hash(code)
+	if err != nil {
+		return fmt.Errorf("issueCode: %w", err)
+	}
+
+	c := domain.VerificationCode{
+		Phone:     phone,
+		Purpose:   purpose,
+		CodeHash:  hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.policy.CodeTTL),
+		ExpireAt:  now.Add(uc.policy.CodeTTL + uc.policy.TicketTTL + time.Hour),
+	}
+	if err := uc.codeRepo.Create(ctx, &c); err != nil {
+		return fmt.Errorf("issueCode: %w", err)
+	}
+
+	if err := uc.sender.Send(ctx, notify.Message{To: phone, Text: fmt.Sprintf(text, code)}); err != nil {
+		return fmt.Errorf("issueCode: send: %w", err)
+	}
+	return nil
+}
+
+// checkCode сверяет код с последним активным; после MaxAttempts неверных вводов код аннулируется.
+// Попытка засчитывается до проверки: иначе параллельные запросы видят старый счётчик
+// и перебирают короткий код сверх лимита
+func (uc *VerificationUsecase) checkCode(ctx context.Context, phone, purpose, code string) (*domain.VerificationCode, error) {
+	if phone == "" || code == "" {
+		return nil, customErr.ErrCodeInvalid
+	}
+
+	now := time.Now()
+	c, err := uc.codeRepo.GetActive(ctx, phone, purpose, now)
+	if err != nil {
+		return nil, fmt.Errorf("checkCode: %w", err)
+	}
+	if c == nil {
+		return nil, customErr.ErrCodeInvalid
+	}
+
+	attempts, ok, err := uc.codeRepo.IncrementAttempts(ctx, c.ID, uc.policy.MaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("checkCode: %w", err)
+	}
+	if !ok {
+		return nil, customErr.ErrCodeAttempts
+	}
+
+	if ok, _ := uc.passwords.Verify(c.CodeHash, code); ok {
+		return c, nil
+	}
+	if attempts >= uc.policy.MaxAttempts {
+		if err := uc.codeRepo.Consume(ctx, c.ID, now); err != nil {
+			log.Printf("checkCode: %v", err)
+		}
+		return nil, customErr.ErrCodeAttempts
+	}
+	return nil, customErr.ErrCodeInvalid
+}
+
+// randomDigits — криптостойкий цифровой код заданной длины
+func randomDigits(n int) (string, error) {
+	buf := make([]byte, n)
+	for i := range buf {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = byte('0' + d.Int64())
+	}
+	return string(buf), nil
+}
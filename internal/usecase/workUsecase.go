@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"library-Mongo/internal/domain"
+	customErr "library-Mongo/internal/errors"
+	"library-Mongo/internal/repo"
+	"library-Mongo/internal/textsim"
+	"library-Mongo/internal/usecase/dto"
+	"strings"
+	"time"
+)
+
+type WorkUsecase struct {
+	workRepo   repo.WorkRepository
+	bookRepo   repo.BookRepository
+	borrowRepo repo.BorrowRepository
+	books      BookUC
+	audit      AuditRecorder
+}
+
+func NewWorkUsecase(
+	workRepo repo.WorkRepository,
+	bookRepo repo.BookRepository,
+	borrowRepo repo.BorrowRepository,
+	books BookUC,
+	audit AuditRecorder,
+) *WorkUsecase {
+	return &WorkUsecase{
+		workRepo:   workRepo,
+		bookRepo:   bookRepo,
+		borrowRepo: borrowRepo,
+		books:      books,
+		audit:      audit,
+	}
+}
+
+// CreateWork заводит произведение; книги из BookIDs становятся его изданиями
+// через обычное изменение книги — с ревизией и записью в журнале
+func (uc *WorkUsecase) CreateWork(ctx context.Context, input dto.CreateWorkInput) (dto.WorkResponse, error) {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return dto.WorkResponse{}, customErr.ErrWorkTitleRequired
+	}
+	for _, id := range input.BookIDs {
+		if _, err := loadBook(ctx, uc.bookRepo, id); err != nil {
+			return dto.WorkResponse{}, err
+		}
+	}
+
+	now := time.Now()
+	work := domain.Work{
+		Title:     title,
+		Author:    strings.TrimSpace(input.Author),
+		Year:      input.Year,
+		Keys:      textsim.SearchKeys(title),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.workRepo.Create(ctx, &work); err != nil {
+		return dto.WorkResponse{}, fmt.Errorf("CreateWork: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditWorkCreate, domain.AuditEntityWork, work.ID, nil, work)
+
+	for _, id := range input.BookIDs {
+		if err := uc.books.UpdateBook(ctx, dto.UpdateBookInput{ID: id, WorkID: &work.ID}); err != nil {
+			return dto.WorkResponse{}, fmt.Errorf("CreateWork: attach book %s: %w", id, err)
+		}
+	}
+
+	return dto.NewWorkResponse(work), nil
+}
+
+func (uc *WorkUsecase) UpdateWork(ctx context.Context, input dto.UpdateWorkInput) error {
+	work, err := loadWork(ctx, uc.workRepo, input.ID)
+	if err != nil {
+		return err
+	}
+	before := *work
+
+	if input.Title != nil {
+		work.Title = strings.TrimSpace(*input.Title)
+		if work.Title == "" {
+			return customErr.ErrWorkTitleRequired
+		}
+		work.Keys = textsim.SearchKeys(work.Title)
+	}
+	if input.Author != nil {
+		work.Author = strings.TrimSpace(*input.Author)
+	}
+	if input.Year != nil {
+		work.Year = *input.Year
+	}
+	work.UpdatedAt = time.Now()
+
+	if err := uc.workRepo.Update(ctx, work); err != nil {
+		return fmt.Errorf("UpdateWork: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditWorkUpdate, domain.AuditEntityWork, work.ID, before, *work)
+	return nil
+}
+
+// DeleteWork удаляет произведение, у которого не осталось изданий (и списанных тоже)
+func (uc *WorkUsecase) DeleteWork(ctx context.Context, id string) error {
+	work, err := loadWork(ctx, uc.workRepo, id)
+	if err != nil {
+		return err
+	}
+	editions, err := uc.bookRepo.CountInWork(ctx, work.ID)
+	if err != nil {
+		return fmt.Errorf("DeleteWork: %w", err)
+	}
+	if editions > 0 {
+		return customErr.ErrWorkInUse
+	}
+	if err := uc.workRepo.Delete(ctx, work.ID); err != nil {
+		return fmt.Errorf("DeleteWork: %w", err)
+	}
+	uc.audit.Record(ctx, domain.AuditWorkDelete, domain.AuditEntityWork, work.ID, *work, nil)
+	return nil
+}
+
+func (uc *WorkUsecase) GetWork(ctx context.Context, id string) (dto.WorkResponse, error) {
+	work, err := loadWork(ctx, uc.workRepo, id)
+	if err != nil {
+		return dto.WorkResponse{}, err
+	}
+	return dto.NewWorkResponse(*work), nil
+}
+
+func (uc *WorkUsecase) SearchWorks(ctx context.Context, title string, page domain.PageRequest) (domain.Page[dto.WorkResponse], error) {
+	works, err := uc.workRepo.SearchPage(ctx, title, page)
+	if err != nil {
+		return domain.Page[dto.WorkResponse]{}, fmt.Errorf("SearchWorks: %w", err)
+	}
+	return domain.Page[dto.WorkResponse]{
+		Items: dto.NewWorkResponses(works.Items),
+		Total: works.Total,
+		Next:  works.Next,
+	}, nil
+}
+
+// WorkBorrows — выдачи всех изданий произведения, включая списанные
+func (uc *WorkUsecase) WorkBorrows(ctx context.Context, id string, page domain.PageRequest) (domain.Page[dto.BorrowHistoryItem], error) {
+	work, err := loadWork(ctx, uc.workRepo, id)
+	if err != nil {
+		return domain.Page[dto.BorrowHistoryItem]{}, err
+	}
+	editions, err := workEditions(ctx, uc.bookRepo, work.ID)
+	if err != nil {
+		return domain.Page[dto.BorrowHistoryItem]{}, err
+	}
+	borrows, err := uc.borrowRepo.GetByBooksPage(ctx, editionIDs(editions), page)
+	if err != nil {
+		return domain.Page[dto.BorrowHistoryItem]{}, fmt.Errorf("WorkBorrows: %w", err)
+	}
+
+	books := make(map[primitive.ObjectID]domain.Book, len(editions))
+	for _, b := range editions {
+		oid, _ := primitive.ObjectIDFromHex(b.ID)
+		books[oid] = b
+	}
+
+	now := time.Now()
+	history := make([]dto.BorrowHistoryItem, 0, len(borrows.Items))
+	for _, b := range borrows.Items {
+		book := books[b.BookID]
+		isOverdue := b.ReturnedAt == nil && b.BorrowedAt.Before(now.AddDate(0, 0, -21))
+		item := dto.BorrowHistoryItem{
+			BorrowID:   b.ID,
+			UserID:     b.ClientID.Hex(),
+			BookID:     b.BookID.Hex(),
+			ItemID:     itemIDHex(b.ItemID),
+			Title:      book.Title,
+			Author:     book.Author,
+			BorrowedAt: b.BorrowedAt,
+			ReturnedAt: b.ReturnedAt,
+			Status:     "ok",
+			Withdrawn:  book.Withdrawn != nil,
+		}
+		if isOverdue {
+			item.Status = "overdue"
+		}
+		history = append(history, item)
+	}
+
+	return domain.Page[dto.BorrowHistoryItem]{Items: history, Total: borrows.Total, Next: borrows.Next}, nil
+}
+
+// WorkStats сводит выдачи по изданиям произведения; издания без выдач тоже попадают в отчёт
+func (uc *WorkUsecase) WorkStats(ctx context.Context, id string) (dto.WorkStatsResponse, error) {
+	work, err := loadWork(ctx, uc.workRepo, id)
+	if err != nil {
+		return dto.WorkStatsResponse{}, err
+	}
+	editions, err := workEditions(ctx, uc.bookRepo, work.ID)
+	if err != nil {
+		return dto.WorkStatsResponse{}, err
+	}
+	stats, err := uc.borrowRepo.StatsByBook(ctx, editionIDs(editions))
+	if err != nil {
+		return dto.WorkStatsResponse{}, fmt.Errorf("WorkStats: %w", err)
+	}
+	byBook := make(map[string]domain.WorkBorrowStat, len(stats))
+	for _, s := range stats {
+		byBook[s.BookID] = s
+	}
+
+	res := dto.WorkStatsResponse{
+		Work:      dto.NewWorkResponse(*work),
+		Editions:  len(editions),
+		ByEdition: make([]dto.EditionBorrowStats, 0, len(editions)),
+	}
+	readers := map[string]bool{}
+	for _, b := range editions {
+		s := byBook[b.ID]
+		res.Borrows += s.Borrows
+		res.ActiveBorrows += s.Active
+		for _, r := range s.Readers {
+			readers[r] = true
+		}
+		res.ByEdition = append(res.ByEdition, dto.EditionBorrowStats{
+			BookID:        b.ID,
+			Title:         b.Title,
+			Year:          b.Year,
+			ISBN13:        b.ISBN13,
+			Withdrawn:     b.Withdrawn != nil,
+			Borrows:       s.Borrows,
+			ActiveBorrows: s.Active,
+			UniqueReaders: len(s.Readers),
+		})
+	}
+	res.UniqueReaders = len(readers)
+	return res, nil
+}
+
+// loadWork — произведение по ID; ErrWorkNotFound, если его нет
+func loadWork(ctx context.Context, workRepo repo.WorkRepository, id string) (*domain.Work, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	work, err := workRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("loadWork: %w", err)
+	}
+	if work == nil {
+		return nil, customErr.ErrWorkNotFound
+	}
+	return work, nil
+}
+
+// workEditions — все издания произведения, включая списанные: их выдачи остаются в истории
+func workEditions(ctx context.Context, bookRepo repo.BookRepository, workID string) ([]domain.Book, error) {
+	if _, err := primitive.ObjectIDFromHex(workID); err != nil {
+		return nil, customErr.ErrInvalidID
+	}
+	editions, err := bookRepo.Search(ctx, domain.BookFilter{WorkID: workID, Withdrawn: domain.WithdrawnInclude})
+	if err != nil {
+		return nil, fmt.Errorf("workEditions: %w", err)
+	}
+	return editions, nil
+}
+
+// editionIDs — ObjectID книг; пустой, но не nil срез: nil для репозитория означает «без фильтра»
+func editionIDs(books []domain.Book) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(books))
+	for _, b := range books {
+		if oid, err := primitive.ObjectIDFromHex(b.ID); err == nil {
+			ids = append(ids, oid)
+		}
+	}
+	return ids
+}